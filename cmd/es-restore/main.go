@@ -22,6 +22,12 @@ func main() {
 }
 
 func restore() {
+	// The indexes are recreated so the documents are indexed with the current mappings.
+	err := es.RecreateIndexes()
+	if err != nil {
+		l.Logger.Fatal("[ERROR] recreating indexes failed:", zap.Error(err))
+	}
+
 	funcs := []func(){
 		restoreEntities,
 		restoreUsers,
@@ -79,10 +85,19 @@ func restoreEntities() {
 			Region:  entity.Region,
 			Country: entity.Country,
 			// Account
-			AccountNumber: entity.AccountNumber,
-			Balance:       &account.Balance,
-			MaxNegBal:     &limit.MaxNegBal,
-			MaxPosBal:     &limit.MaxPosBal,
+			AccountNumber:  entity.AccountNumber,
+			Balance:        &account.Balance,
+			MaxNegBal:      &limit.MaxNegBal,
+			MaxPosBal:      &limit.MaxPosBal,
+			AccountAliases: entity.AccountAliases,
+			// Images
+			ImageURLs: types.EntityImageURLs(entity.Images),
+			// Geo
+			Location: types.NewESGeoPoint(logic.Geo.Resolve(&entity)),
+		}
+		if entity.Logo != nil {
+			record.LogoURL = entity.Logo.URL
+			record.LogoThumbnailURL = entity.Logo.ThumbnailURL
 		}
 		_, err = es.Client().Index().
 			Index("entities").
@@ -3,11 +3,15 @@ package main
 import (
 	"github.com/ic3network/mccs-alpha-api/global"
 	"github.com/ic3network/mccs-alpha-api/internal/app/http"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic/balancecheck"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic/dailyemail"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic/dataexport"
+	"github.com/ic3network/mccs-alpha-api/util/jwt"
 	"github.com/ic3network/mccs-alpha-api/util/l"
 	"github.com/robfig/cron"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 func init() {
@@ -17,6 +21,10 @@ func init() {
 func main() {
 	// Flushes log buffer, if any.
 	defer l.Logger.Sync()
+	// The keys are parsed once, later changes to the configuration are picked up by the rotation schedule.
+	if err := jwt.Init(); err != nil {
+		l.Logger.Fatal("[main] Loading the JWT keys failed:", zap.Error(err))
+	}
 	go ServeBackGround()
 	go RunMigration()
 
@@ -39,8 +47,56 @@ func ServeBackGround() {
 		balancecheck.Run()
 	})
 
+	viper.SetDefault("data_export_cleanup_schedule", "0 30 * * * *")
+	c.AddFunc(viper.GetString("data_export_cleanup_schedule"), func() {
+		l.Logger.Info("[ServeBackGround] Running data export cleanup schedule. \n")
+		dataexport.DeleteExpired()
+	})
+
+	viper.SetDefault("refresh_token_cleanup_schedule", "0 45 * * * *")
+	c.AddFunc(viper.GetString("refresh_token_cleanup_schedule"), func() {
+		l.Logger.Info("[ServeBackGround] Running refresh token cleanup schedule. \n")
+		count, err := logic.RefreshToken.DeleteExpired()
+		if err != nil {
+			l.Logger.Error("[ServeBackGround] Deleting expired refresh tokens failed:", zap.Error(err))
+		} else if count > 0 {
+			l.Logger.Info("[ServeBackGround] Deleted expired refresh tokens.", zap.Int64("count", count))
+		}
+	})
+
+	viper.SetDefault("jwt.key_rotation_schedule", "0 */5 * * * *")
+	c.AddFunc(viper.GetString("jwt.key_rotation_schedule"), func() {
+		previous := jwt.NewJWTManager().SigningKeyID()
+		kid, err := jwt.NewJWTManager().Rotate()
+		if err != nil {
+			l.Logger.Error("[ServeBackGround] Rotating the JWT keys failed:", zap.Error(err))
+		} else if kid != previous {
+			l.Logger.Info("[ServeBackGround] Activated JWT signing key.", zap.String("kid", kid))
+		}
+	})
+
 	c.Start()
 }
 
 func RunMigration() {
+	count, err := logic.Entity.BackfillMembers()
+	if err != nil {
+		l.Logger.Error("[RunMigration] Backfilling entity members failed:", zap.Error(err))
+	} else if count > 0 {
+		l.Logger.Info("[RunMigration] Backfilled entity members.", zap.Int("count", count))
+	}
+
+	count, err = logic.Application.Backfill()
+	if err != nil {
+		l.Logger.Error("[RunMigration] Backfilling applications failed:", zap.Error(err))
+	} else if count > 0 {
+		l.Logger.Info("[RunMigration] Backfilled applications.", zap.Int("count", count))
+	}
+
+	roles, err := logic.AdminUser.BackfillRoles()
+	if err != nil {
+		l.Logger.Error("[RunMigration] Backfilling admin roles failed:", zap.Error(err))
+	} else if roles > 0 {
+		l.Logger.Info("[RunMigration] Backfilled admin roles.", zap.Int64("count", roles))
+	}
 }
@@ -0,0 +1,62 @@
+package constant
+
+// Admin role groups admin permissions. The super admin role is built in and has every permission.
+var AdminRole = struct {
+	SuperAdmin string
+}{
+	SuperAdmin: "superadmin",
+}
+
+// Admin permission decides which admin routes an admin user can call.
+var AdminPermission = struct {
+	UsersRead         string
+	UsersWrite        string
+	UsersDelete       string
+	UsersImpersonate  string
+	EntitiesRead      string
+	EntitiesWrite     string
+	EntitiesDelete    string
+	ApplicationsWrite string
+	TransfersRead     string
+	TransfersWrite    string
+	LedgerRead        string
+	LedgerWrite       string
+	TagsWrite         string
+	LogsRead          string
+	AdminsWrite       string
+}{
+	UsersRead:         "users:read",
+	UsersWrite:        "users:write",
+	UsersDelete:       "users:delete",
+	UsersImpersonate:  "users:impersonate",
+	EntitiesRead:      "entities:read",
+	EntitiesWrite:     "entities:write",
+	EntitiesDelete:    "entities:delete",
+	ApplicationsWrite: "applications:write",
+	TransfersRead:     "transfers:read",
+	TransfersWrite:    "transfers:write",
+	LedgerRead:        "ledger:read",
+	LedgerWrite:       "ledger:write",
+	TagsWrite:         "tags:write",
+	LogsRead:          "logs:read",
+	AdminsWrite:       "admins:write",
+}
+
+// AdminPermissions lists every admin permission.
+var AdminPermissions = []string{
+	AdminPermission.UsersRead,
+	AdminPermission.UsersWrite,
+	AdminPermission.UsersDelete,
+	AdminPermission.UsersImpersonate,
+	AdminPermission.EntitiesRead,
+	AdminPermission.EntitiesWrite,
+	AdminPermission.EntitiesDelete,
+	AdminPermission.ApplicationsWrite,
+	AdminPermission.TransfersRead,
+	AdminPermission.TransfersWrite,
+	AdminPermission.LedgerRead,
+	AdminPermission.LedgerWrite,
+	AdminPermission.TagsWrite,
+	AdminPermission.LogsRead,
+	AdminPermission.AdminsWrite,
+}
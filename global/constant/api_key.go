@@ -0,0 +1,19 @@
+package constant
+
+// API key scope decides which routes an integration can call with an API key.
+var APIKeyScope = struct {
+	ReadBalance      string
+	ReadTransfers    string
+	ProposeTransfers string
+}{
+	ReadBalance:      "balance:read",
+	ReadTransfers:    "transfers:read",
+	ProposeTransfers: "transfers:propose",
+}
+
+// APIKeyScopes lists every API key scope.
+var APIKeyScopes = []string{
+	APIKeyScope.ReadBalance,
+	APIKeyScope.ReadTransfers,
+	APIKeyScope.ProposeTransfers,
+}
@@ -0,0 +1,22 @@
+package constant
+
+// Application status of a membership application in the review queue.
+var Application = struct {
+	Open          string
+	InfoRequested string
+	Approved      string
+	Rejected      string
+}{
+	Open:          "open",
+	InfoRequested: "infoRequested",
+	Approved:      "approved",
+	Rejected:      "rejected",
+}
+
+var ApplicationDecision = struct {
+	Approve string
+	Reject  string
+}{
+	Approve: "approve",
+	Reject:  "reject",
+}
@@ -0,0 +1,14 @@
+package constant
+
+// DataExport status of a personal data export.
+var DataExport = struct {
+	Pending string
+	Ready   string
+	Failed  string
+	Expired string
+}{
+	Pending: "pending",
+	Ready:   "ready",
+	Failed:  "failed",
+	Expired: "expired",
+}
@@ -21,3 +21,34 @@ var Trading = struct {
 	Accepted: "tradingAccepted",
 	Rejected: "tradingRejected",
 }
+
+// EntityStatusTransitions lists the statuses an entity can move to from each status.
+var EntityStatusTransitions = map[string][]string{
+	Entity.Pending:   {Entity.Accepted, Entity.Rejected},
+	Entity.Rejected:  {Entity.Pending},
+	Entity.Accepted:  {Trading.Pending, Entity.Rejected},
+	Trading.Pending:  {Trading.Accepted, Trading.Rejected},
+	Trading.Accepted: {Trading.Rejected},
+	Trading.Rejected: {Trading.Pending, Entity.Rejected},
+}
+
+// Entity role decides what an associated user can do on behalf of the entity.
+var EntityRole = struct {
+	Owner      string
+	Bookkeeper string
+	Staff      string
+	Viewer     string
+}{
+	Owner:      "owner",
+	Bookkeeper: "bookkeeper",
+	Staff:      "staff",
+	Viewer:     "viewer",
+}
+
+// EntityRoleRank orders the entity roles from the least to the most privileged.
+var EntityRoleRank = map[string]int{
+	EntityRole.Viewer:     1,
+	EntityRole.Staff:      2,
+	EntityRole.Bookkeeper: 3,
+	EntityRole.Owner:      4,
+}
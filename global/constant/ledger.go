@@ -0,0 +1,22 @@
+package constant
+
+// LedgerAccountType is the type of an account in the chart of accounts of the general ledger.
+var LedgerAccountType = struct {
+	Asset     string
+	Liability string
+	Income    string
+	Expense   string
+	Equity    string
+}{
+	Asset:     "asset",
+	Liability: "liability",
+	Income:    "income",
+	Expense:   "expense",
+	Equity:    "equity",
+}
+
+var LedgerJournalType = struct {
+	Manual string
+}{
+	Manual: "manual",
+}
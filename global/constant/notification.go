@@ -0,0 +1,13 @@
+package constant
+
+var Notification = struct {
+	LowBalance         string
+	MaxNegBalProximity string
+	MaxPosBalProximity string
+	WriteOff           string
+}{
+	LowBalance:         "lowBalance",
+	MaxNegBalProximity: "maxNegativeBalanceProximity",
+	MaxPosBalProximity: "maxPositiveBalanceProximity",
+	WriteOff:           "writeOff",
+}
@@ -0,0 +1,12 @@
+package constant
+
+// Rate limit group decides which limit of `rate_limiting` applies to a route.
+var RateLimitGroup = struct {
+	Default string
+	Auth    string
+	Email   string
+}{
+	Default: "default",
+	Auth:    "auth",
+	Email:   "email",
+}
@@ -32,7 +32,11 @@ var TransferDirection = struct {
 var TransferType = struct {
 	Transfer      string
 	AdminTransfer string
+	AdminWriteOff string
+	Merge         string
 }{
 	Transfer:      "transfer",
 	AdminTransfer: "adminTransfer",
+	AdminWriteOff: "adminWriteOff",
+	Merge:         "merge",
 }
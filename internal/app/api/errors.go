@@ -15,4 +15,10 @@ var (
 	ErrUnauthorized = errors.New("Could not authenticate you.")
 	// ErrPermissionDenied occurs when the user does not have permission to perform the action.
 	ErrPermissionDenied = errors.New("Permission denied.")
+	// ErrEmailNotVerified occurs when the user has not confirmed the email address yet.
+	ErrEmailNotVerified = errors.New("Please verify your email address first.")
+	// ErrImpersonationReadOnly occurs when an admin impersonating a user calls a route the impersonation token does not allow.
+	ErrImpersonationReadOnly = errors.New("This action is not allowed while impersonating a user.")
+	// ErrTooManyRequests occurs when the rate limit of the route has been reached.
+	ErrTooManyRequests = errors.New("Too many requests, please try again later.")
 )
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var AdminRoleHandler = newAdminRoleHandler()
+
+type adminRoleHandler struct {
+	once *sync.Once
+}
+
+func newAdminRoleHandler() *adminRoleHandler {
+	return &adminRoleHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *adminRoleHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		adminPrivate.Path("/roles").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.list())).Methods("GET")
+		adminPrivate.Path("/roles").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.create())).Methods("POST")
+		adminPrivate.Path("/roles/{role}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.update())).Methods("PATCH")
+		adminPrivate.Path("/roles/{role}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.delete())).Methods("DELETE")
+	})
+}
+
+// GET /admin/roles
+
+func (handler *adminRoleHandler) list() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.AdminRoleRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		roles, err := logic.AdminRole.FindAll()
+		if err != nil {
+			l.Logger.Error("[Error] AdminRoleHandler.list failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		data := []*types.AdminRoleRespond{}
+		for _, role := range roles {
+			data = append(data, types.NewAdminRoleRespond(role))
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /admin/roles
+
+func (handler *adminRoleHandler) create() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminRoleRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminCreateRoleReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		created, err := logic.AdminRole.Create(&types.AdminRole{
+			Name:        req.Name,
+			Description: req.Description,
+			Permissions: req.Permissions,
+		})
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminChangeRole(r.Header.Get("userID"), "created", created)
+
+		api.Respond(w, r, http.StatusCreated, respond{Data: types.NewAdminRoleRespond(created)})
+	}
+}
+
+// PATCH /admin/roles/{role}
+
+func (handler *adminRoleHandler) update() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminRoleRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminUpdateRoleReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		_, err := logic.AdminRole.FindByName(req.Name)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		updated, err := logic.AdminRole.FindOneAndUpdate(&types.AdminRole{
+			Name:        req.Name,
+			Description: req.Description,
+			Permissions: req.Permissions,
+		})
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminChangeRole(r.Header.Get("userID"), "modified", updated)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminRoleRespond(updated)})
+	}
+}
+
+// DELETE /admin/roles/{role}
+
+func (handler *adminRoleHandler) delete() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["role"]
+		if name == constant.AdminRole.SuperAdmin {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("The built-in role cannot be deleted."))
+			return
+		}
+
+		role, err := logic.AdminRole.FindByName(name)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		err = logic.AdminRole.Delete(name)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminChangeRole(r.Header.Get("userID"), "deleted", role)
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
@@ -5,17 +5,17 @@ import (
 	"errors"
 	"net/http"
 	"sync"
-	"time"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
 	"github.com/ic3network/mccs-alpha-api/util"
 	"github.com/ic3network/mccs-alpha-api/util/cookie"
-	"github.com/ic3network/mccs-alpha-api/util/jwt"
 	"github.com/ic3network/mccs-alpha-api/util/l"
 	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -41,34 +41,27 @@ func (handler *adminUserHandler) RegisterRoutes(
 	adminPrivate *mux.Router,
 ) {
 	handler.once.Do(func() {
-		adminPublic.Path("/login").HandlerFunc(handler.login()).Methods("POST")
+		middleware.RateLimit(adminPublic.Path("/login").HandlerFunc(handler.login()).Methods("POST"), constant.RateLimitGroup.Auth)
+		adminPublic.Path("/refresh").HandlerFunc(handler.refresh()).Methods("POST")
 		adminPrivate.Path("/logout").HandlerFunc(handler.logout()).Methods("POST")
-		adminPublic.Path("/password-reset").HandlerFunc(handler.requestPasswordReset()).Methods("POST")
-		adminPublic.Path("/password-reset/{token}").HandlerFunc(handler.passwordReset()).Methods("POST")
+		middleware.RateLimit(adminPublic.Path("/password-reset").HandlerFunc(handler.requestPasswordReset()).Methods("POST"), constant.RateLimitGroup.Auth)
+		middleware.RateLimit(adminPublic.Path("/password-reset/{token}").HandlerFunc(handler.passwordReset()).Methods("POST"), constant.RateLimitGroup.Auth)
 		adminPrivate.Path("/password-change").HandlerFunc(handler.passwordChange()).Methods("POST")
+
+		adminPrivate.Path("/admin-users").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.listAdminUsers())).Methods("GET")
+		adminPrivate.Path("/admin-users").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.createAdminUser())).Methods("POST")
+		adminPrivate.Path("/admin-users/{adminID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.getAdminUser())).Methods("GET")
+		adminPrivate.Path("/admin-users/{adminID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.updateAdminUser())).Methods("PATCH")
+		adminPrivate.Path("/admin-users/{adminID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.deleteAdminUser())).Methods("DELETE")
+		adminPrivate.Path("/admin-users/{adminID}/2fa").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.AdminsWrite, handler.resetAdminTwoFactor())).Methods("DELETE")
 	})
 }
 
 // POST /admin/login
 
 func (handler *adminUserHandler) login() func(http.ResponseWriter, *http.Request) {
-	type data struct {
-		Token         string     `json:"token"`
-		LastLoginIP   string     `json:"lastLoginIP,omitempty"`
-		LastLoginDate *time.Time `json:"lastLoginDate,omitempty"`
-	}
 	type respond struct {
-		Data data `json:"data"`
-	}
-	respondData := func(info *types.LoginInfo, token string) data {
-		d := data{Token: token}
-		if info.LastLoginIP != "" {
-			d.LastLoginIP = info.LastLoginIP
-		}
-		if !info.LastLoginDate.IsZero() {
-			d.LastLoginDate = &info.LastLoginDate
-		}
-		return d
+		Data *types.LoginRespond `json:"data"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		req, errs := types.NewLoginReq(r)
@@ -85,16 +78,67 @@ func (handler *adminUserHandler) login() func(http.ResponseWriter, *http.Request
 			go logic.UserAction.AdminLoginFail(req.Email, util.IPAddress(r))
 			return
 		}
-		loginInfo, err := logic.AdminUser.UpdateLoginInfo(user.ID, util.IPAddress(r))
+
+		// Two-factor authentication is mandatory for admins, the ones without it have to enrol
+		// through POST /admin/login/2fa/setup before the tokens are issued by POST /admin/login/2fa.
+		twoFactorToken, err := logic.TwoFactor.NewChallenge(user.ID, true)
 		if err != nil {
-			l.Logger.Error("[Error] AdminUser.UpdateLoginInfo failed:", zap.Error(err))
+			l.Logger.Error("[Error] AdminUserHandler.login failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
 		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewTwoFactorChallengeRespond(twoFactorToken, !user.TwoFactor.Enabled())})
+	}
+}
+
+// completeLogin issues the tokens once the admin has been authenticated.
+func (handler *adminUserHandler) completeLogin(r *http.Request, user *types.AdminUser) (*types.LoginRespond, error) {
+	loginInfo, err := logic.AdminUser.UpdateLoginInfo(user.ID, util.IPAddress(r))
+	if err != nil {
+		l.Logger.Error("[Error] AdminUser.UpdateLoginInfo failed:", zap.Error(err))
+		loginInfo = &types.LoginInfo{}
+	}
+
+	tokens, err := logic.RefreshToken.Issue(user.ID, true, types.NewDevice(r))
+	if err != nil {
+		return nil, err
+	}
+
+	go logic.UserAction.AdminLogin(user, util.IPAddress(r))
+
+	respond := types.NewLoginRespond(loginInfo, tokens.AccessToken, tokens.RefreshToken)
+	respond.PasswordChangeRequired = logic.Password.AdminExpired(user)
+	return respond, nil
+}
 
-		token, err := jwt.NewJWTManager().Generate(user.ID.Hex(), true)
+// POST /admin/refresh
 
-		go logic.UserAction.AdminLogin(user, util.IPAddress(r))
+func (handler *adminUserHandler) refresh() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewRefreshTokenReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
 
-		api.Respond(w, r, http.StatusOK, respond{Data: respondData(loginInfo, token)})
+		tokens, err := logic.RefreshToken.Refresh(req.RefreshToken, true, types.NewDevice(r))
+		if err != nil {
+			l.Logger.Info("[Info] AdminUserHandler.refresh failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+		}})
 	}
 }
 
@@ -102,6 +146,12 @@ func (handler *adminUserHandler) login() func(http.ResponseWriter, *http.Request
 
 func (handler *adminUserHandler) logout() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
+		err := logic.RefreshToken.RevokeByStringID(r.Header.Get("sessionID"))
+		if err != nil {
+			l.Logger.Error("[Error] AdminUserHandler.logout failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
 		http.SetCookie(w, cookie.ResetCookie())
 		api.Respond(w, r, http.StatusOK)
 	}
@@ -195,6 +245,10 @@ func (handler *adminUserHandler) passwordReset() func(http.ResponseWriter, *http
 		}
 
 		err = logic.AdminUser.ResetPassword(lostPassword.Email, req.Password)
+		if err == logic.ErrPasswordReused {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
 		if err != nil {
 			l.Logger.Error("[ERROR] AdminUserHandler.passwordReset failed:", zap.Error(err))
 			api.Respond(w, r, http.StatusInternalServerError, err)
@@ -247,6 +301,10 @@ func (handler *adminUserHandler) passwordChange() func(http.ResponseWriter, *htt
 		}
 
 		err = logic.AdminUser.ResetPassword(user.Email, req.Password)
+		if err == logic.ErrPasswordReused {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
 		if err != nil {
 			l.Logger.Error("[ERROR] AdminUserHandler.passwordChange failed:", zap.Error(err))
 			api.Respond(w, r, http.StatusInternalServerError, err)
@@ -256,3 +314,171 @@ func (handler *adminUserHandler) passwordChange() func(http.ResponseWriter, *htt
 		api.Respond(w, r, http.StatusOK)
 	}
 }
+
+func (handler *adminUserHandler) findByPath(r *http.Request) (*types.AdminUser, error) {
+	return logic.AdminUser.FindByIDString(mux.Vars(r)["adminID"])
+}
+
+// GET /admin/admin-users
+
+func (handler *adminUserHandler) listAdminUsers() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.AdminAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		admins, err := logic.AdminUser.FindAll()
+		if err != nil {
+			l.Logger.Error("[Error] AdminUserHandler.listAdminUsers failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		data := []*types.AdminAccountRespond{}
+		for _, admin := range admins {
+			data = append(data, types.NewAdminAccountRespond(admin))
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// GET /admin/admin-users/{adminID}
+
+func (handler *adminUserHandler) getAdminUser() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, err := handler.findByPath(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminAccountRespond(admin)})
+	}
+}
+
+// POST /admin/admin-users
+
+func (handler *adminUserHandler) createAdminUser() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminCreateAdminUserReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		created, err := logic.AdminUser.Create(&types.AdminUser{
+			Email:    req.Email,
+			Name:     req.Name,
+			Password: req.Password,
+			Roles:    req.Roles,
+		})
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminCreateAdminUser(r.Header.Get("userID"), created)
+
+		api.Respond(w, r, http.StatusCreated, respond{Data: types.NewAdminAccountRespond(created)})
+	}
+}
+
+// PATCH /admin/admin-users/{adminID}
+
+func (handler *adminUserHandler) updateAdminUser() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminUpdateAdminUserReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		origin, err := handler.findByPath(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+		// Stops an admin from locking itself out of the admin management.
+		if req.Roles != nil && origin.ID.Hex() == r.Header.Get("userID") {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("You cannot change your own roles."))
+			return
+		}
+
+		update := &types.AdminUser{Name: req.Name}
+		if req.Roles != nil {
+			update.Roles = *req.Roles
+		}
+		updated, err := logic.AdminUser.FindOneAndUpdate(origin.ID, update)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminModifyAdminUser(r.Header.Get("userID"), origin, updated)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminAccountRespond(updated)})
+	}
+}
+
+// DELETE /admin/admin-users/{adminID}
+
+func (handler *adminUserHandler) deleteAdminUser() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, err := handler.findByPath(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+		if admin.ID.Hex() == r.Header.Get("userID") {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("You cannot delete yourself."))
+			return
+		}
+
+		deleted, err := logic.AdminUser.FindOneAndDelete(admin.ID)
+		if err == logic.ErrLastSuperAdmin {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			l.Logger.Error("[Error] AdminUserHandler.deleteAdminUser failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminDeleteAdminUser(r.Header.Get("userID"), deleted)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminAccountRespond(deleted)})
+	}
+}
+
+// DELETE /admin/admin-users/{adminID}/2fa
+
+func (handler *adminUserHandler) resetAdminTwoFactor() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, err := handler.findByPath(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		err = logic.AdminUser.ResetTwoFactor(admin.ID)
+		if err != nil {
+			l.Logger.Error("[Error] AdminUserHandler.resetAdminTwoFactor failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminResetAdminTwoFactor(r.Header.Get("userID"), admin)
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var APIKeyHandler = newAPIKeyHandler()
+
+type apiKeyHandler struct {
+	once *sync.Once
+}
+
+func newAPIKeyHandler() *apiKeyHandler {
+	return &apiKeyHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *apiKeyHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		middleware.DenyImpersonation(private.Path("/user/entities/{entityID}/api-keys").HandlerFunc(handler.createAPIKey()).Methods("POST"))
+		middleware.DenyImpersonation(private.Path("/user/entities/{entityID}/api-keys").HandlerFunc(handler.listAPIKeys()).Methods("GET"))
+		middleware.DenyImpersonation(private.Path("/user/entities/{entityID}/api-keys/{keyID}").HandlerFunc(handler.revokeAPIKey()).Methods("DELETE"))
+	})
+}
+
+// findEntity returns the entity of the path when the user can manage its API keys.
+func (handler *apiKeyHandler) findEntity(w http.ResponseWriter, r *http.Request) (*types.Entity, bool) {
+	entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+	if err != nil {
+		api.Respond(w, r, http.StatusNotFound, err)
+		return nil, false
+	}
+	if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Bookkeeper) {
+		api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+		return nil, false
+	}
+	return entity, true
+}
+
+// POST /user/entities/{entityID}/api-keys
+
+func (handler *apiKeyHandler) createAPIKey() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.APIKeyRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, ok := handler.findEntity(w, r)
+		if !ok {
+			return
+		}
+
+		req, errs := types.NewAPIKeyReq(r, entity)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] APIKeyHandler.createAPIKey failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		created, key, err := logic.APIKey.Create(&types.APIKey{
+			EntityID:   entity.ID,
+			CreatedBy:  user.ID,
+			Name:       req.Name,
+			Scopes:     req.Scopes,
+			AllowedIPs: req.AllowedIPs,
+		})
+		if err != nil {
+			l.Logger.Error("[Error] APIKeyHandler.createAPIKey failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.CreateAPIKey(user, entity, created)
+
+		data := types.NewAPIKeyRespond(created)
+		data.Key = key
+		api.Respond(w, r, http.StatusCreated, respond{Data: data})
+	}
+}
+
+// GET /user/entities/{entityID}/api-keys
+
+func (handler *apiKeyHandler) listAPIKeys() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.APIKeyRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, ok := handler.findEntity(w, r)
+		if !ok {
+			return
+		}
+
+		keys, err := logic.APIKey.FindByEntityID(entity.ID)
+		if err != nil {
+			l.Logger.Error("[Error] APIKeyHandler.listAPIKeys failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		data := []*types.APIKeyRespond{}
+		for _, key := range keys {
+			data = append(data, types.NewAPIKeyRespond(key))
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// DELETE /user/entities/{entityID}/api-keys/{keyID}
+
+func (handler *apiKeyHandler) revokeAPIKey() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.APIKeyRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, ok := handler.findEntity(w, r)
+		if !ok {
+			return
+		}
+
+		key, err := logic.APIKey.FindByStringID(mux.Vars(r)["keyID"])
+		if err != nil || key.EntityID != entity.ID {
+			api.Respond(w, r, http.StatusNotFound, errors.New("API key not found."))
+			return
+		}
+
+		revoked, err := logic.APIKey.Revoke(key.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err == nil {
+			go logic.UserAction.RevokeAPIKey(user, entity, revoked)
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAPIKeyRespond(revoked)})
+	}
+}
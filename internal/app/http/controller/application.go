@@ -0,0 +1,375 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var ApplicationHandler = newApplicationHandler()
+
+type applicationHandler struct {
+	once *sync.Once
+}
+
+func newApplicationHandler() *applicationHandler {
+	return &applicationHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *applicationHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/user/entities/{entityID}/application").HandlerFunc(handler.getApplication()).Methods("GET")
+		private.Path("/user/entities/{entityID}/application/resubmit").HandlerFunc(handler.resubmitApplication()).Methods("POST")
+
+		adminPrivate.Path("/applications").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesRead, handler.adminSearchApplication())).Methods("GET")
+		adminPrivate.Path("/applications/{applicationID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesRead, handler.adminGetApplication())).Methods("GET")
+		adminPrivate.Path("/applications/{applicationID}/reviewer").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.ApplicationsWrite, handler.adminAssignReviewer())).Methods("PATCH")
+		adminPrivate.Path("/applications/{applicationID}/notes").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.ApplicationsWrite, handler.adminAddNote())).Methods("POST")
+		adminPrivate.Path("/applications/{applicationID}/info-requests").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.ApplicationsWrite, handler.adminRequestInfo())).Methods("POST")
+		adminPrivate.Path("/applications/{applicationID}/decision").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.ApplicationsWrite, handler.adminDecide())).Methods("POST")
+	})
+}
+
+func (handler *applicationHandler) findEntityAndApplication(r *http.Request) (*types.Entity, *types.Application, error) {
+	application, err := logic.Application.FindByID(util.ToObjectID(mux.Vars(r)["applicationID"]))
+	if err != nil {
+		return nil, nil, err
+	}
+	entity, err := logic.Entity.FindByID(application.EntityID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entity, application, nil
+}
+
+// GET /user/entities/{entityID}/application
+
+func (handler *applicationHandler) getApplication() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		application, err := logic.Application.FindByEntityID(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewApplicationRespond(application)})
+	}
+}
+
+// POST /user/entities/{entityID}/application/resubmit
+
+func (handler *applicationHandler) resubmitApplication() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewResubmitApplicationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] ApplicationHandler.resubmitApplication failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		application, err := logic.Application.Resubmit(entity.ID, req.Response)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ResubmitApplication(user, entity)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewApplicationRespond(application)})
+	}
+}
+
+// GET /admin/applications
+
+func (handler *applicationHandler) adminSearchApplication() func(http.ResponseWriter, *http.Request) {
+	type meta struct {
+		NumberOfResults int `json:"numberOfResults"`
+		TotalPages      int `json:"totalPages"`
+	}
+	type respond struct {
+		Data []*types.AdminApplicationRespond `json:"data"`
+		Meta meta                             `json:"meta"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminSearchApplicationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		found, err := logic.Application.Search(req)
+		if err != nil {
+			l.Logger.Error("[Error] ApplicationHandler.adminSearchApplication failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// Include the submitted profile so admins can review the applications from the list.
+		data := []*types.AdminApplicationRespond{}
+		for _, application := range found.Applications {
+			entity, err := logic.Entity.FindByID(application.EntityID)
+			if err != nil {
+				l.Logger.Error("[Error] ApplicationHandler.adminSearchApplication failed:", zap.Error(err))
+			}
+			data = append(data, types.NewAdminApplicationRespond(application, entity))
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{
+			Data: data,
+			Meta: meta{
+				TotalPages:      found.TotalPages,
+				NumberOfResults: found.NumberOfResults,
+			},
+		})
+	}
+}
+
+// GET /admin/applications/{applicationID}
+
+func (handler *applicationHandler) adminGetApplication() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, application, err := handler.findEntityAndApplication(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminApplicationRespond(application, entity)})
+	}
+}
+
+// PATCH /admin/applications/{applicationID}/reviewer
+
+func (handler *applicationHandler) adminAssignReviewer() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminAssignReviewerReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		entity, application, err := handler.findEntityAndApplication(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		reviewer, err := logic.AdminUser.FindByIDString(req.ReviewerID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] ApplicationHandler.adminAssignReviewer failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		updated, err := logic.Application.AssignReviewer(application.ID, reviewer)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminAssignApplicationReviewer(admin, entity, reviewer)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminApplicationRespond(updated, entity)})
+	}
+}
+
+// POST /admin/applications/{applicationID}/notes
+
+func (handler *applicationHandler) adminAddNote() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminApplicationNoteReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		entity, application, err := handler.findEntityAndApplication(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] ApplicationHandler.adminAddNote failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		updated, err := logic.Application.AddNote(application.ID, &types.ApplicationNote{
+			Body:      req.Note,
+			CreatedBy: admin.Email,
+		})
+		if err != nil {
+			l.Logger.Error("[Error] ApplicationHandler.adminAddNote failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminAddApplicationNote(admin, entity)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminApplicationRespond(updated, entity)})
+	}
+}
+
+// POST /admin/applications/{applicationID}/info-requests
+
+func (handler *applicationHandler) adminRequestInfo() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminApplicationInfoReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		entity, application, err := handler.findEntityAndApplication(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] ApplicationHandler.adminRequestInfo failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		updated, err := logic.Application.RequestInfo(application.ID, &types.ApplicationInfoRequest{
+			Message:     req.Message,
+			RequestedBy: admin.Email,
+		})
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go email.Application.InfoRequested(&email.ApplicationInfoRequestEmail{
+			EntityName: entity.Name,
+			Email:      entity.Email,
+			Message:    req.Message,
+		})
+		go logic.UserAction.AdminRequestApplicationInfo(admin, entity, req.Message)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminApplicationRespond(updated, entity)})
+	}
+}
+
+// POST /admin/applications/{applicationID}/decision
+
+func (handler *applicationHandler) adminDecide() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminApplicationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminApplicationDecisionReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		entity, application, err := handler.findEntityAndApplication(r)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if application.Status != constant.Application.Open && application.Status != constant.Application.InfoRequested {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("The application has already been decided."))
+			return
+		}
+
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] ApplicationHandler.adminDecide failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// The status change closes the application and sends the welcome or rejection email.
+		updated, err := EntityHandler.ChangeStatus(admin.ID.Hex(), entity, req.Status(), req.Reason)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminDecideApplication(admin, updated, req.Decision, req.Reason)
+
+		application.Status = constant.Application.Approved
+		if req.Decision == constant.ApplicationDecision.Reject {
+			application.Status = constant.Application.Rejected
+		}
+		application.DecisionReason = req.Reason
+		application.DecidedBy = admin.Email
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminApplicationRespond(application, updated)})
+	}
+}
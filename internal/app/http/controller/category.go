@@ -7,7 +7,9 @@ import (
 	"sync"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/util/l"
@@ -35,9 +37,9 @@ func (handler *categoryHandler) RegisterRoutes(
 	handler.once.Do(func() {
 		public.Path("/categories").HandlerFunc(handler.search()).Methods("GET")
 
-		adminPrivate.Path("/categories").HandlerFunc(handler.create()).Methods("POST")
-		adminPrivate.Path("/categories/{id}").HandlerFunc(handler.update()).Methods("PATCH")
-		adminPrivate.Path("/categories/{id}").HandlerFunc(handler.delete()).Methods("DELETE")
+		adminPrivate.Path("/categories").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TagsWrite, handler.create())).Methods("POST")
+		adminPrivate.Path("/categories/{id}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TagsWrite, handler.update())).Methods("PATCH")
+		adminPrivate.Path("/categories/{id}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TagsWrite, handler.delete())).Methods("DELETE")
 	})
 }
 
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic/dataexport"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var DataExportHandler = newDataExportHandler()
+
+type dataExportHandler struct {
+	once *sync.Once
+}
+
+func newDataExportHandler() *dataExportHandler {
+	return &dataExportHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *dataExportHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/exports/{token}").HandlerFunc(handler.download()).Methods("GET")
+		middleware.DenyImpersonation(private.Path("/user/export").HandlerFunc(handler.export()).Methods("GET"))
+		adminPrivate.Path("/users/{userID}/export").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersRead, handler.adminExport())).Methods("GET")
+	})
+}
+
+// create starts generating the archive in the background, the link is sent to the requester once it is ready.
+func (handler *dataExportHandler) create(user *types.User, requestedBy string, receiver string) (*types.DataExport, error) {
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	export, created, err := logic.DataExport.Create(&types.DataExport{
+		UserID:      user.ID,
+		RequestedBy: requestedBy,
+		Token:       uid.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		go dataexport.Run(export, user, receiver)
+	}
+	return export, nil
+}
+
+// GET /user/export
+
+func (handler *dataExportHandler) export() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.DataExportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] DataExportHandler.export failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		export, err := handler.create(user, user.Email, user.FirstName+" "+user.LastName)
+		if err != nil {
+			l.Logger.Error("[Error] DataExportHandler.export failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.RequestDataExport(user)
+
+		api.Respond(w, r, http.StatusAccepted, respond{Data: types.NewDataExportRespond(export)})
+	}
+}
+
+// GET /admin/users/{userID}/export
+
+func (handler *dataExportHandler) adminExport() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.DataExportRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := logic.User.FindByStringID(mux.Vars(r)["userID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] DataExportHandler.adminExport failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// The link is sent to the admin, who hands the archive over to the user.
+		export, err := handler.create(user, admin.Email, admin.Name)
+		if err != nil {
+			l.Logger.Error("[Error] DataExportHandler.adminExport failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminRequestDataExport(admin, user)
+
+		api.Respond(w, r, http.StatusAccepted, respond{Data: types.NewDataExportRespond(export)})
+	}
+}
+
+// GET /exports/{token}
+
+func (handler *dataExportHandler) download() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		export, err := logic.DataExport.FindByToken(mux.Vars(r)["token"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+		if logic.DataExport.IsExpired(export) {
+			api.Respond(w, r, http.StatusGone, errors.New("The download link has expired, please request a new export."))
+			return
+		}
+		if export.Status != constant.DataExport.Ready {
+			api.Respond(w, r, http.StatusNotFound, errors.New("The export is not ready."))
+			return
+		}
+
+		data, err := logic.DataExport.Download(export)
+		if err != nil {
+			l.Logger.Error("[Error] DataExportHandler.download failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="data-export.zip"`)
+		w.Write(data)
+	}
+}
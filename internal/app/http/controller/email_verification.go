@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var EmailVerificationHandler = newEmailVerificationHandler()
+
+type emailVerificationHandler struct {
+	once *sync.Once
+}
+
+func newEmailVerificationHandler() *emailVerificationHandler {
+	return &emailVerificationHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *emailVerificationHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/email-verification/{token}").HandlerFunc(handler.verify()).Methods("POST")
+		middleware.RateLimit(private.Path("/user/email-verification").HandlerFunc(handler.resendToUser()).Methods("POST"), constant.RateLimitGroup.Email)
+		middleware.RateLimit(private.Path("/user/entities/{entityID}/email-verification").HandlerFunc(handler.resendToEntity()).Methods("POST"), constant.RateLimitGroup.Email)
+
+		adminPrivate.Path("/users/{userID}/email-verification").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersWrite, handler.adminResendToUser())).Methods("POST")
+		adminPrivate.Path("/entities/{entityID}/email-verification").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesWrite, handler.adminResendToEntity())).Methods("POST")
+	})
+}
+
+type emailVerificationData struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Token     string    `json:"token,omitempty"`
+}
+
+func (data *emailVerificationData) set(verification *types.EmailVerification) {
+	data.Email = verification.Email
+	data.ExpiresAt = logic.EmailVerification.ExpiresAt(verification)
+	if viper.GetString("env") == "development" {
+		data.Token = verification.Token
+	}
+}
+
+// sendWelcome follows the confirmation of the email address given on signup.
+func (handler *emailVerificationHandler) sendWelcome(verification *types.EmailVerification) {
+	user, err := logic.User.FindByID(verification.UserID)
+	if err != nil {
+		l.Logger.Error("[Error] EmailVerificationHandler.sendWelcome failed:", zap.Error(err))
+		return
+	}
+	entities, err := logic.Entity.FindByIDs(user.Entities)
+	if err != nil || len(entities) == 0 {
+		return
+	}
+	email.Welcome(&email.WelcomeEmail{
+		EntityName: entities[0].Name,
+		Email:      entities[0].Email,
+		Receiver:   user.FirstName + " " + user.LastName,
+	})
+}
+
+// POST /email-verification/{token}
+
+func (handler *emailVerificationHandler) verify() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data emailVerificationData `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		verification, err := logic.EmailVerification.Verify(mux.Vars(r)["token"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.VerifyEmail(verification)
+		if verification.Signup {
+			go handler.sendWelcome(verification)
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: emailVerificationData{Email: verification.Email}})
+	}
+}
+
+// POST /user/email-verification
+
+func (handler *emailVerificationHandler) resendToUser() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data emailVerificationData `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := logic.User.FindByStringID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] EmailVerificationHandler.resendToUser failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		created, err := logic.EmailVerification.SendToUser(user, false)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		data := emailVerificationData{}
+		data.set(created)
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /user/entities/{entityID}/email-verification
+
+func (handler *emailVerificationHandler) resendToEntity() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data emailVerificationData `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		created, err := logic.EmailVerification.SendToEntity(entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		data := emailVerificationData{}
+		data.set(created)
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /admin/users/{userID}/email-verification
+
+func (handler *emailVerificationHandler) adminResendToUser() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data emailVerificationData `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := logic.User.FindByStringID(mux.Vars(r)["userID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		created, err := logic.EmailVerification.SendToUser(user, false)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminResendEmailVerification(r.Header.Get("userID"), created.Email)
+
+		data := emailVerificationData{}
+		data.set(created)
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /admin/entities/{entityID}/email-verification
+
+func (handler *emailVerificationHandler) adminResendToEntity() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data emailVerificationData `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		created, err := logic.EmailVerification.SendToEntity(entity)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminResendEmailVerification(r.Header.Get("userID"), created.Email)
+
+		data := emailVerificationData{}
+		data.set(created)
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
@@ -3,6 +3,8 @@ package controller
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"sync"
@@ -11,6 +13,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
@@ -43,13 +46,15 @@ func (handler *entityHandler) RegisterRoutes(
 		public.Path("/entities").HandlerFunc(handler.searchEntity()).Methods("GET")
 		public.Path("/entities/{searchEntityID}").HandlerFunc(handler.getEntity()).Methods("GET")
 		private.Path("/favorites").HandlerFunc(handler.addToFavoriteEntities()).Methods("POST")
-		private.Path("/send-email").HandlerFunc(handler.sendEmailToEntity()).Methods("POST")
-		private.Path("/balance").HandlerFunc(handler.getBalance()).Methods("GET")
-
-		adminPrivate.Path("/entities").HandlerFunc(handler.adminSearchEntity()).Methods("GET")
-		adminPrivate.Path("/entities/{entityID}").HandlerFunc(handler.adminGetEntity()).Methods("GET")
-		adminPrivate.Path("/entities/{entityID}").HandlerFunc(handler.adminUpdateEntity()).Methods("PATCH")
-		adminPrivate.Path("/entities/{entityID}").HandlerFunc(handler.adminDeleteEntity()).Methods("DELETE")
+		middleware.RateLimit(private.Path("/send-email").HandlerFunc(middleware.RequireVerifiedEmail(handler.sendEmailToEntity())).Methods("POST"), constant.RateLimitGroup.Email)
+		middleware.AllowAPIKey(private.Path("/balance").HandlerFunc(handler.getBalance()).Methods("GET"), constant.APIKeyScope.ReadBalance)
+
+		adminPrivate.Path("/entities").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesRead, handler.adminSearchEntity())).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesRead, handler.adminGetEntity())).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesWrite, handler.adminUpdateEntity())).Methods("PATCH")
+		adminPrivate.Path("/entities/{entityID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesDelete, handler.adminDeleteEntity())).Methods("DELETE")
+		adminPrivate.Path("/entities/{entityID}/status-history").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesRead, handler.adminGetStatusHistory())).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}/merge").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesDelete, handler.adminMergeEntity())).Methods("POST")
 	})
 }
 
@@ -127,11 +132,16 @@ func (handler *entityHandler) searchEntity() func(http.ResponseWriter, *http.Req
 		Data []*types.SearchEntityRespond `json:"data"`
 		Meta meta                         `json:"meta"`
 	}
-	toData := func(query *types.SearchEntityReq, entities []*types.Entity) []*types.SearchEntityRespond {
+	toData := func(query *types.SearchEntityReq, found *types.SearchEntityResult) []*types.SearchEntityRespond {
 		result := []*types.SearchEntityRespond{}
 		queryingEntityStatus := handler.getQueryingEntityStatus(query.QueryingEntityID)
-		for _, entity := range entities {
-			result = append(result, types.NewSearchEntityRespond(entity, queryingEntityStatus, query.FavoriteEntities))
+		for _, entity := range found.Entities {
+			respond := types.NewSearchEntityRespond(entity, queryingEntityStatus, query.FavoriteEntities)
+			if distance, ok := found.Distances[entity.ID.Hex()]; ok {
+				distance = math.Round(distance*100) / 100
+				respond.Distance = &distance
+			}
+			result = append(result, respond)
 		}
 		return result
 	}
@@ -168,7 +178,7 @@ func (handler *entityHandler) searchEntity() func(http.ResponseWriter, *http.Req
 		}
 
 		api.Respond(w, r, http.StatusOK, respond{
-			Data: toData(query, found.Entities),
+			Data: toData(query, found),
 			Meta: meta{
 				TotalPages:      found.TotalPages,
 				NumberOfResults: found.NumberOfResults,
@@ -241,7 +251,7 @@ func (handler *entityHandler) addToFavoriteEntities() func(http.ResponseWriter,
 			return
 		}
 
-		if !UserHandler.IsEntityBelongsToUser(req.AddToEntityID, r.Header.Get("userID")) {
+		if !UserHandler.HasEntityRole(req.AddToEntityID, r.Header.Get("userID"), constant.EntityRole.Staff) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -290,7 +300,7 @@ func (handler *entityHandler) sendEmailToEntity() func(http.ResponseWriter, *htt
 			api.Respond(w, r, http.StatusInternalServerError, err)
 			return
 		}
-		if !UserHandler.IsEntityBelongsToUser(req.SenderEntityID, r.Header.Get("userID")) {
+		if !UserHandler.HasEntityRole(req.SenderEntityID, r.Header.Get("userID"), constant.EntityRole.Staff) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -345,7 +355,7 @@ func (handler *entityHandler) getBalance() func(http.ResponseWriter, *http.Reque
 			return
 		}
 
-		if !UserHandler.IsEntityBelongsToUser(query.QueryingEntityID, r.Header.Get("userID")) {
+		if !UserHandler.IsEntityBelongsToUser(query.QueryingEntityID, r.Header.Get("userID")) || !middleware.IsAPIKeyEntity(r, query.QueryingEntityID) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -490,6 +500,15 @@ func (handler *entityHandler) adminUpdateEntity() func(http.ResponseWriter, *htt
 			return
 		}
 
+		statusChanged := req.Status != "" && req.Status != req.OriginEntity.Status
+		if statusChanged {
+			err := logic.EntityStatus.CheckTransition(req.OriginEntity, req.Status, req.StatusReason)
+			if err != nil {
+				api.Respond(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
+
 		updated, err := logic.Entity.AdminFindOneAndUpdate(req)
 		if err != nil {
 			l.Logger.Error("[Error] EntityHandler.updateEntity failed:", zap.Error(err))
@@ -518,8 +537,18 @@ func (handler *entityHandler) adminUpdateEntity() func(http.ResponseWriter, *htt
 			return
 		}
 
+		if updated.Email != req.OriginEntity.Email {
+			_, err = logic.EmailVerification.EntityEmailChanged(updated)
+			if err != nil {
+				l.Logger.Error("[Error] EntityHandler.adminUpdateEntity failed:", zap.Error(err))
+			}
+		}
+
 		go logic.UserAction.AdminModifyEntity(r.Header.Get("userID"), req.OriginEntity, updated)
 		go logic.UserAction.AdminModifyBalance(r.Header.Get("userID"), req.OriginBalanceLimit, res.BalanceLimit)
+		if statusChanged {
+			go handler.recordStatusChange(r.Header.Get("userID"), req, updated)
+		}
 
 		api.Respond(w, r, http.StatusOK, respond{Data: res})
 	}
@@ -566,6 +595,82 @@ func (handler *entityHandler) newAdminUpdateEntityRespond(req *types.AdminUpdate
 	return types.NewAdminUpdateEntityRespond(users, entity, balanceLimit), nil
 }
 
+func (handler *entityHandler) recordStatusChange(adminID string, req *types.AdminUpdateEntityReq, updated *types.Entity) {
+	admin, err := logic.AdminUser.FindByIDString(adminID)
+	if err != nil {
+		l.Logger.Error("[Error] EntityHandler.recordStatusChange failed:", zap.Error(err))
+		return
+	}
+	logic.EntityStatus.Record(updated, req.OriginEntity.Status, updated.Status, req.StatusReason, admin.Email)
+	logic.UserAction.AdminChangeEntityStatus(admin, updated, req.OriginEntity.Status, req.StatusReason)
+}
+
+// POST /admin/applications/{applicationID}/decision
+
+// ChangeStatus moves the entity to the new status the same way as PATCH /admin/entities/{entityID} does.
+func (handler *entityHandler) ChangeStatus(adminID string, entity *types.Entity, status string, reason string) (*types.Entity, error) {
+	err := logic.EntityStatus.CheckTransition(entity, status, reason)
+	if err != nil {
+		return nil, err
+	}
+	balanceLimit, err := logic.BalanceLimit.FindByAccountNumber(entity.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	req := &types.AdminUpdateEntityReq{
+		OriginEntity:       entity,
+		OriginBalanceLimit: balanceLimit,
+		Status:             status,
+		StatusReason:       reason,
+	}
+
+	updated, err := logic.Entity.AdminFindOneAndUpdate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	go handler.UpdateOfferAndWants(&types.UpdateOfferAndWants{
+		EntityID:      entity.ID,
+		OriginStatus:  entity.Status,
+		UpdatedStatus: updated.Status,
+		UpdatedOffers: types.TagFieldToNames(updated.Offers),
+		UpdatedWants:  types.TagFieldToNames(updated.Wants),
+	})
+	go handler.updateEntityMemberStartedAt(entity, status)
+	go handler.recordStatusChange(adminID, req, updated)
+
+	return updated, nil
+}
+
+// GET /admin/entities/{entityID}/status-history
+
+func (handler *entityHandler) adminGetStatusHistory() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.EntityStatusChangeRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		changes, err := logic.EntityStatus.FindHistory(entity.ID)
+		if err != nil {
+			l.Logger.Error("[Error] EntityHandler.adminGetStatusHistory failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		res := []*types.EntityStatusChangeRespond{}
+		for _, change := range changes {
+			res = append(res, types.NewEntityStatusChangeRespond(change))
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
+
 func (handler *entityHandler) updateEntityMemberStartedAt(oldEntity *types.Entity, newStatus string) {
 	// Set timestamp when first trading status applied.
 	if oldEntity.MemberStartedAt.IsZero() && (newStatus == constant.Trading.Accepted) {
@@ -633,3 +738,61 @@ func (handler *entityHandler) adminDeleteEntity() func(http.ResponseWriter, *htt
 		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminDeleteEntityRespond(deleted)})
 	}
 }
+
+// POST /admin/entities/{entityID}/merge
+
+func (handler *entityHandler) adminMergeEntity() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminMergeEntityRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newAdminMergeEntityReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		result, err := logic.EntityMerge.Merge(req)
+		if err == logic.ErrMergeExceedsLimit {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			l.Logger.Error("[Error] EntityHandler.adminMergeEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminMergeEntity(r.Header.Get("userID"), req, result)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminMergeEntityRespond(result)})
+	}
+}
+
+func (handler *entityHandler) newAdminMergeEntityReq(r *http.Request) (*types.AdminMergeEntityReq, []error) {
+	var body types.AdminMergeEntityUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	if body.IntoEntityID == "" {
+		return nil, []error{errors.New("Please enter the id of the entity to merge into.")}
+	}
+	entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+	if err != nil {
+		return nil, []error{err}
+	}
+	intoEntity, err := logic.Entity.FindByStringID(body.IntoEntityID)
+	if err != nil {
+		return nil, []error{err}
+	}
+	account, err := logic.Account.FindByAccountNumber(entity.AccountNumber)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewAdminMergeEntityReq(entity, intoEntity, account.Balance)
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var EntityImageHandler = newEntityImageHandler()
+
+type entityImageHandler struct {
+	once *sync.Once
+}
+
+func newEntityImageHandler() *entityImageHandler {
+	return &entityImageHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *entityImageHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/images/{key}").HandlerFunc(handler.getImage()).Methods("GET")
+		private.Path("/user/entities/{entityID}/logo").HandlerFunc(handler.uploadLogo()).Methods("PUT")
+		private.Path("/user/entities/{entityID}/logo").HandlerFunc(handler.deleteLogo()).Methods("DELETE")
+		private.Path("/user/entities/{entityID}/images").HandlerFunc(handler.uploadImage()).Methods("POST")
+		private.Path("/user/entities/{entityID}/images/{imageID}").HandlerFunc(handler.deleteImage()).Methods("DELETE")
+	})
+}
+
+// GET /images/{key}
+
+func (handler *entityImageHandler) getImage() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := logic.EntityImage.Get(mux.Vars(r)["key"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+		// Keys are never reused so the images can be cached for good.
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Del("Pragma")
+		w.Header().Del("Expires")
+		w.Header().Set("Content-Type", http.DetectContentType(data))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Write(data)
+	}
+}
+
+// PUT /user/entities/{entityID}/logo
+
+func (handler *entityImageHandler) uploadLogo() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newEntityImageReq(w, r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !logic.Entity.HasRole(req.Entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		updated, err := logic.EntityImage.SetLogo(req.Entity, req.Data)
+		if err != nil {
+			l.Logger.Info("[INFO] EntityImageHandler.uploadLogo failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityImages(r.Header.Get("userID"), updated, "uploaded logo")
+
+		handler.respond(w, r, updated)
+	}
+}
+
+// DELETE /user/entities/{entityID}/logo
+
+func (handler *entityImageHandler) deleteLogo() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+		if entity.Logo == nil {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("The entity does not have a logo."))
+			return
+		}
+
+		updated, err := logic.EntityImage.RemoveLogo(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityImageHandler.deleteLogo failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityImages(r.Header.Get("userID"), updated, "deleted logo")
+
+		handler.respond(w, r, updated)
+	}
+}
+
+// POST /user/entities/{entityID}/images
+
+func (handler *entityImageHandler) uploadImage() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newEntityImageReq(w, r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !logic.Entity.HasRole(req.Entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		updated, err := logic.EntityImage.AddImage(req.Entity, req.Data)
+		if err != nil {
+			l.Logger.Info("[INFO] EntityImageHandler.uploadImage failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityImages(r.Header.Get("userID"), updated, "uploaded gallery image")
+
+		handler.respond(w, r, updated)
+	}
+}
+
+// DELETE /user/entities/{entityID}/images/{imageID}
+
+func (handler *entityImageHandler) deleteImage() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		var image *types.EntityImage
+		for _, i := range entity.Images {
+			if i.ID == mux.Vars(r)["imageID"] {
+				image = i
+			}
+		}
+		if image == nil {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Image not found."))
+			return
+		}
+
+		updated, err := logic.EntityImage.RemoveImage(entity, image)
+		if err != nil {
+			l.Logger.Error("[Error] EntityImageHandler.deleteImage failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.ModifyEntityImages(r.Header.Get("userID"), updated, "deleted gallery image")
+
+		handler.respond(w, r, updated)
+	}
+}
+
+func (handler *entityImageHandler) newEntityImageReq(w http.ResponseWriter, r *http.Request) (*types.EntityImageReq, []error) {
+	entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+	if err != nil {
+		return nil, []error{err}
+	}
+	// Leave some room for the multipart boundaries and headers.
+	r.Body = http.MaxBytesReader(w, r.Body, viper.GetInt64("images.max_size")+1024*1024)
+	return types.NewEntityImageReq(r, entity)
+}
+
+func (handler *entityImageHandler) respond(w http.ResponseWriter, r *http.Request, entity *types.Entity) {
+	type respond struct {
+		Data *types.EntityRespond `json:"data"`
+	}
+	res, err := EntityHandler.NewEntityRespond(entity)
+	if err != nil {
+		l.Logger.Error("[Error] EntityImageHandler.respond failed:", zap.Error(err))
+		api.Respond(w, r, http.StatusBadRequest, err)
+		return
+	}
+	api.Respond(w, r, http.StatusOK, respond{Data: res})
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var EntityMemberHandler = newEntityMemberHandler()
+
+type entityMemberHandler struct {
+	once *sync.Once
+}
+
+func newEntityMemberHandler() *entityMemberHandler {
+	return &entityMemberHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *entityMemberHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/user/entities/{entityID}/members").HandlerFunc(handler.listMembers()).Methods("GET")
+		private.Path("/user/entities/{entityID}/members/{userID}").HandlerFunc(handler.updateMember()).Methods("PATCH")
+		private.Path("/user/entities/{entityID}/members/{userID}").HandlerFunc(handler.removeMember()).Methods("DELETE")
+	})
+}
+
+// GET /user/entities/{entityID}/members
+
+func (handler *entityMemberHandler) listMembers() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.EntityMemberRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Viewer) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		users, err := logic.Entity.FindMembers(entity)
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.listMembers failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		res := []*types.EntityMemberRespond{}
+		for _, user := range users {
+			res = append(res, types.NewEntityMemberRespond(user, entity.MemberRole(user.ID)))
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func (handler *entityMemberHandler) updateMember() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.EntityMemberRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newUpdateEntityMemberReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !logic.Entity.HasRole(req.Entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		updated, err := logic.Entity.UpdateMemberRole(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		user, err := logic.User.FindByID(req.UserID)
+		if err != nil {
+			l.Logger.Error("[Error] EntityMemberHandler.updateMember failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.UpdateEntityMember(r.Header.Get("userID"), req.Entity, user, req.Role)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewEntityMemberRespond(user, updated.MemberRole(user.ID))})
+	}
+}
+
+func (handler *entityMemberHandler) newUpdateEntityMemberReq(r *http.Request) (*types.UpdateEntityMemberReq, []error) {
+	entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var j types.UpdateEntityMemberJSON
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&j)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return types.NewUpdateEntityMemberReq(j, entity, mux.Vars(r)["userID"])
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+func (handler *entityMemberHandler) removeMember() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req, errs := types.NewEntityMemberReq(entity, mux.Vars(r)["userID"])
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		// Members can always leave the entity themselves.
+		if req.UserID.Hex() != r.Header.Get("userID") && !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		user, err := logic.User.FindByID(req.UserID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		err = logic.Entity.RemoveMember(req)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.RemoveEntityMember(r.Header.Get("userID"), entity, user)
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
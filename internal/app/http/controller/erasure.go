@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var ErasureHandler = newErasureHandler()
+
+type erasureHandler struct {
+	once *sync.Once
+}
+
+func newErasureHandler() *erasureHandler {
+	return &erasureHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *erasureHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		adminPrivate.Path("/users/{userID}/erasure").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersDelete, handler.adminEraseUser())).Methods("POST")
+		adminPrivate.Path("/entities/{entityID}/erasure").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesDelete, handler.adminEraseEntity())).Methods("POST")
+	})
+}
+
+// POST /admin/users/{userID}/erasure
+
+func (handler *erasureHandler) adminEraseUser() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminEraseUserRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminDeleteUserReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		erased, entities, err := logic.Erasure.EraseUser(req.UserID)
+		if err != nil {
+			l.Logger.Error("[Error] ErasureHandler.adminEraseUser failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminEraseUser(r.Header.Get("userID"), erased, entities)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminEraseUserRespond(erased, entities)})
+	}
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func (handler *erasureHandler) adminEraseEntity() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminEraseEntityRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminDeleteEntity(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		erased, err := logic.Erasure.EraseEntity(req.EntityID)
+		if err != nil {
+			l.Logger.Error("[Error] ErasureHandler.adminEraseEntity failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminEraseEntity(r.Header.Get("userID"), erased)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminEraseEntityRespond(erased)})
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var ImpersonationHandler = newImpersonationHandler()
+
+type impersonationHandler struct {
+	once *sync.Once
+}
+
+func newImpersonationHandler() *impersonationHandler {
+	return &impersonationHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *impersonationHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		adminPrivate.Path("/users/{userID}/impersonation").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersImpersonate, handler.adminImpersonateUser())).Methods("POST")
+	})
+}
+
+// POST /admin/users/{userID}/impersonation
+
+// adminImpersonateUser issues a token for the user's endpoints, write access also needs the users:write permission.
+func (handler *impersonationHandler) adminImpersonateUser() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		Token     string    `json:"token"`
+		SessionID string    `json:"sessionID"`
+		ExpiresAt time.Time `json:"expiresAt"`
+		Write     bool      `json:"write"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminImpersonationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+		if req.Write && !middleware.HasPermission(r, constant.AdminPermission.UsersWrite) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		user, err := UserHandler.FindByID(mux.Vars(r)["userID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] ImpersonationHandler.adminImpersonateUser failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		token, session, err := logic.Impersonation.Start(admin, user, req.Write, types.NewDevice(r))
+		if err != nil {
+			l.Logger.Error("[Error] ImpersonationHandler.adminImpersonateUser failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminImpersonateUser(admin, user, req.Write, req.Reason)
+
+		api.Respond(w, r, http.StatusCreated, respond{Data: data{
+			Token:     token,
+			SessionID: session.ID.Hex(),
+			ExpiresAt: session.ExpiresAt,
+			Write:     req.Write,
+		}})
+	}
+}
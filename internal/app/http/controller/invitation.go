@@ -0,0 +1,311 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var InvitationHandler = newInvitationHandler()
+
+type invitationHandler struct {
+	once *sync.Once
+}
+
+func newInvitationHandler() *invitationHandler {
+	return &invitationHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *invitationHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/invitations/{token}").HandlerFunc(handler.getInvitation()).Methods("GET")
+		public.Path("/invitations/{token}/accept").HandlerFunc(handler.acceptInvitation()).Methods("POST")
+		private.Path("/user/entities/{entityID}/invitations").HandlerFunc(handler.createInvitation()).Methods("POST")
+		private.Path("/user/entities/{entityID}/invitations").HandlerFunc(handler.listInvitations()).Methods("GET")
+		private.Path("/user/entities/{entityID}/invitations/{invitationID}").HandlerFunc(handler.revokeInvitation()).Methods("DELETE")
+	})
+}
+
+// POST /user/entities/{entityID}/invitations
+
+func (handler *invitationHandler) createInvitation() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		*types.InvitationRespond
+		Token string `json:"token,omitempty"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newInvitationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !logic.Entity.HasRole(req.Entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		existing, err := logic.User.FindByEmail(req.Email)
+		if err == nil && req.Entity.MemberRole(existing.ID) != "" {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("The user is already a member of the entity."))
+			return
+		}
+
+		inviter, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] InvitationHandler.createInvitation failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		uid, err := uuid.NewV4()
+		if err != nil {
+			l.Logger.Error("[Error] InvitationHandler.createInvitation failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		created, err := logic.Invitation.Create(&types.Invitation{
+			EntityID:   req.Entity.ID,
+			EntityName: req.Entity.Name,
+			Email:      req.Email,
+			Role:       req.Role,
+			InvitedBy:  inviter.ID,
+			Token:      uid.String(),
+		})
+		if err != nil {
+			l.Logger.Error("[Error] InvitationHandler.createInvitation failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go email.Invitation(&email.InvitationEmail{
+			InviterName:   inviter.FirstName + " " + inviter.LastName,
+			EntityName:    created.EntityName,
+			Role:          created.Role,
+			ReceiverEmail: created.Email,
+			Token:         created.Token,
+		})
+		go logic.UserAction.InviteEntityMember(inviter, created)
+
+		res := data{InvitationRespond: types.NewInvitationRespond(created, logic.Invitation.ExpiresAt(created))}
+		if viper.GetString("env") == "development" {
+			res.Token = created.Token
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
+
+func (handler *invitationHandler) newInvitationReq(r *http.Request) (*types.InvitationReq, []error) {
+	entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var j types.InvitationJSON
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&j)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return types.NewInvitationReq(j, entity)
+}
+
+// GET /user/entities/{entityID}/invitations
+
+func (handler *invitationHandler) listInvitations() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.InvitationRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		invitations, err := logic.Invitation.FindPending(entity.ID)
+		if err != nil {
+			l.Logger.Error("[Error] InvitationHandler.listInvitations failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		res := []*types.InvitationRespond{}
+		for _, inv := range invitations {
+			res = append(res, types.NewInvitationRespond(inv, logic.Invitation.ExpiresAt(inv)))
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
+
+// DELETE /user/entities/{entityID}/invitations/{invitationID}
+
+func (handler *invitationHandler) revokeInvitation() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		inv, err := logic.Invitation.FindByID(util.ToObjectID(mux.Vars(r)["invitationID"]))
+		if err != nil || inv.EntityID != entity.ID {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Invitation not found."))
+			return
+		}
+		if logic.Invitation.IsTokenInvalid(inv) {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("The invitation is no longer pending."))
+			return
+		}
+
+		err = logic.Invitation.Revoke(inv.ID)
+		if err != nil {
+			l.Logger.Error("[Error] InvitationHandler.revokeInvitation failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.RevokeEntityInvitation(r.Header.Get("userID"), inv)
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
+
+// GET /invitations/{token}
+
+func (handler *invitationHandler) getInvitation() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		*types.InvitationRespond
+		UserExists bool `json:"userExists"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		inv, err := logic.Invitation.FindByToken(mux.Vars(r)["token"])
+		if err != nil || logic.Invitation.IsTokenInvalid(inv) {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Token is invalid."))
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{
+			InvitationRespond: types.NewInvitationRespond(inv, logic.Invitation.ExpiresAt(inv)),
+			UserExists:        logic.User.EmailExists(inv.Email),
+		}})
+	}
+}
+
+// POST /invitations/{token}/accept
+
+func (handler *invitationHandler) acceptInvitation() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		UserID   string `json:"userID"`
+		EntityID string `json:"entityID"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAcceptInvitationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		inv, err := logic.Invitation.FindByToken(req.Token)
+		if err != nil || logic.Invitation.IsTokenInvalid(inv) {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Token is invalid."))
+			return
+		}
+		entity, err := logic.Entity.FindByID(inv.EntityID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// The token is claimed first so concurrent requests cannot both create or link the user.
+		err = logic.Invitation.Claim(inv.Token)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// The invitation either links an existing user or creates a new one.
+		user, err := logic.User.FindByEmail(inv.Email)
+		if err != nil {
+			errs := req.ValidateNewUser(inv.Email)
+			if len(errs) > 0 {
+				logic.Invitation.Unclaim(inv.Token)
+				api.Respond(w, r, http.StatusBadRequest, errs)
+				return
+			}
+			user, err = logic.User.Create(&types.User{
+				Email:     inv.Email,
+				Password:  req.Password,
+				FirstName: req.FirstName,
+				LastName:  req.LastName,
+				Telephone: req.UserPhone,
+			})
+			if err != nil {
+				logic.Invitation.Unclaim(inv.Token)
+				l.Logger.Error("[Error] InvitationHandler.acceptInvitation failed:", zap.Error(err))
+				api.Respond(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		} else if entity.MemberRole(user.ID) != "" {
+			logic.Invitation.Unclaim(inv.Token)
+			api.Respond(w, r, http.StatusBadRequest, errors.New("You are already a member of the entity."))
+			return
+		}
+
+		err = logic.Entity.AddMember(entity.ID, user.ID, inv.Role)
+		if err != nil {
+			logic.Invitation.Unclaim(inv.Token)
+			l.Logger.Error("[Error] InvitationHandler.acceptInvitation failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AcceptEntityInvitation(user, inv)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{
+			UserID:   user.ID.Hex(),
+			EntityID: entity.ID.Hex(),
+		}})
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/util/jwt"
+)
+
+var JWKSHandler = newJWKSHandler()
+
+type jwksHandler struct {
+	once *sync.Once
+}
+
+func newJWKSHandler() *jwksHandler {
+	return &jwksHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *jwksHandler) RegisterRoutes(wellKnown *mux.Router) {
+	handler.once.Do(func() {
+		wellKnown.Path("/jwks.json").HandlerFunc(handler.jwks()).Methods("GET")
+	})
+}
+
+// GET /.well-known/jwks.json
+
+// jwks publishes the public keys so other services can verify the tokens, the response follows RFC 7517.
+func (handler *jwksHandler) jwks() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Keys []jwt.JWK `json:"keys"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		api.Respond(w, r, http.StatusOK, respond{Keys: jwt.NewJWTManager().JWKS()})
+	}
+}
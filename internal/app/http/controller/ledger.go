@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var LedgerHandler = newLedgerHandler()
+
+type ledgerHandler struct {
+	once *sync.Once
+}
+
+func newLedgerHandler() *ledgerHandler {
+	return &ledgerHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *ledgerHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		adminPrivate.Path("/ledger/accounts").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.LedgerWrite, handler.adminCreateAccount())).Methods("POST")
+		adminPrivate.Path("/ledger/accounts").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.LedgerRead, handler.adminListAccounts())).Methods("GET")
+		adminPrivate.Path("/ledger/journals").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.LedgerWrite, handler.adminCreateJournal())).Methods("POST")
+		adminPrivate.Path("/ledger/trial-balance").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.LedgerRead, handler.adminTrialBalance())).Methods("GET")
+		adminPrivate.Path("/ledger/income-statement").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.LedgerRead, handler.adminIncomeStatement())).Methods("GET")
+	})
+}
+
+// POST /admin/ledger/accounts
+
+func (handler *ledgerHandler) adminCreateAccount() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.LedgerAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminCreateLedgerAccountReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		created, err := logic.Ledger.CreateAccount(req)
+		if err != nil {
+			l.Logger.Error("[Error] LedgerHandler.adminCreateAccount failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminCreateLedgerAccount(r.Header.Get("userID"), created)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewLedgerAccountRespond(created)})
+	}
+}
+
+// GET /admin/ledger/accounts
+
+func (handler *ledgerHandler) adminListAccounts() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.LedgerAccountRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		accounts, err := logic.Ledger.FindAccounts()
+		if err != nil {
+			l.Logger.Error("[Error] LedgerHandler.adminListAccounts failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		res := []*types.LedgerAccountRespond{}
+		for _, a := range accounts {
+			res = append(res, types.NewLedgerAccountRespond(a))
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
+
+// POST /admin/ledger/journals
+
+func (handler *ledgerHandler) adminCreateJournal() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.LedgerJournalRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminCreateLedgerJournalReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req.PostedBy = admin.Email
+
+		created, err := logic.Ledger.CreateJournal(req)
+		if err != nil {
+			l.Logger.Error("[Error] LedgerHandler.adminCreateJournal failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminCreateLedgerJournal(r.Header.Get("userID"), created)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewLedgerJournalRespond(created)})
+	}
+}
+
+// GET /admin/ledger/trial-balance
+
+func (handler *ledgerHandler) adminTrialBalance() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TrialBalanceRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminTrialBalanceReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		res, err := logic.Ledger.TrialBalance(req)
+		if err != nil {
+			l.Logger.Error("[Error] LedgerHandler.adminTrialBalance failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
+
+// GET /admin/ledger/income-statement
+
+func (handler *ledgerHandler) adminIncomeStatement() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.IncomeStatementRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAdminIncomeStatementReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		res, err := logic.Ledger.IncomeStatement(req)
+		if err != nil {
+			l.Logger.Error("[Error] LedgerHandler.adminIncomeStatement failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: res})
+	}
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var NotificationHandler = newNotificationHandler()
+
+type notificationHandler struct {
+	once *sync.Once
+}
+
+func newNotificationHandler() *notificationHandler {
+	return &notificationHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *notificationHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/notifications").HandlerFunc(handler.searchNotification()).Methods("GET")
+	})
+}
+
+// GET /notifications
+
+func (handler *notificationHandler) searchNotification() func(http.ResponseWriter, *http.Request) {
+	type meta struct {
+		NumberOfResults int `json:"numberOfResults"`
+		TotalPages      int `json:"totalPages"`
+	}
+	type respond struct {
+		Data []*types.NotificationRespond `json:"data"`
+		Meta meta                         `json:"meta"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newSearchNotificationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !UserHandler.IsEntityBelongsToUser(req.QueryingEntity.ID.Hex(), r.Header.Get("userID")) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		found, err := logic.Notification.Search(req)
+		if err != nil {
+			l.Logger.Error("[Error] NotificationHandler.searchNotification failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{
+			Data: types.NewNotificationsRespond(found.Notifications),
+			Meta: meta{
+				TotalPages:      found.TotalPages,
+				NumberOfResults: found.NumberOfResults,
+			},
+		})
+	}
+}
+
+func (handler *notificationHandler) newSearchNotificationReq(r *http.Request) (*types.SearchNotificationReq, []error) {
+	entity, err := logic.Entity.FindByStringID(r.URL.Query().Get("querying_entity_id"))
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewSearchNotificationReq(r, entity)
+}
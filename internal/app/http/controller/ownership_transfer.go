@@ -0,0 +1,386 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var OwnershipTransferHandler = newOwnershipTransferHandler()
+
+type ownershipTransferHandler struct {
+	once *sync.Once
+}
+
+func newOwnershipTransferHandler() *ownershipTransferHandler {
+	return &ownershipTransferHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *ownershipTransferHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/ownership-transfers/{token}").HandlerFunc(handler.getOwnershipTransfer()).Methods("GET")
+		public.Path("/ownership-transfers/{token}/accept").HandlerFunc(handler.acceptOwnershipTransfer()).Methods("POST")
+		private.Path("/user/entities/{entityID}/ownership-transfer").HandlerFunc(handler.nominateOwner()).Methods("POST")
+		private.Path("/user/entities/{entityID}/ownership-transfer").HandlerFunc(handler.getPendingTransfer()).Methods("GET")
+		private.Path("/user/entities/{entityID}/ownership-transfer").HandlerFunc(handler.revokeOwnershipTransfer()).Methods("DELETE")
+		adminPrivate.Path("/entities/{entityID}/ownership-transfer").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesWrite, handler.adminNominateOwner())).Methods("POST")
+		adminPrivate.Path("/entities/{entityID}/ownership-transfer").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesRead, handler.adminGetPendingTransfer())).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}/ownership-transfer").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.EntitiesWrite, handler.adminRevokeOwnershipTransfer())).Methods("DELETE")
+	})
+}
+
+func (handler *ownershipTransferHandler) newOwnershipTransferReq(r *http.Request) (*types.OwnershipTransferReq, []error) {
+	entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var j types.OwnershipTransferJSON
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&j)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return types.NewOwnershipTransferReq(j, entity)
+}
+
+// create stores the nomination and sends the confirmation link to the nominated email address.
+func (handler *ownershipTransferHandler) create(req *types.OwnershipTransferReq, nominatedBy string) (*types.OwnershipTransfer, error) {
+	existing, err := logic.User.FindByEmail(req.Email)
+	if err == nil && req.Entity.MemberRole(existing.ID) == constant.EntityRole.Owner {
+		return nil, errors.New("The user is already an owner of the entity.")
+	}
+
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	created, err := logic.OwnershipTransfer.Create(&types.OwnershipTransfer{
+		EntityID:           req.Entity.ID,
+		EntityName:         req.Entity.Name,
+		Email:              req.Email,
+		NominatedBy:        nominatedBy,
+		PreviousOwnersRole: req.PreviousOwnersRole,
+		UpdateEntityEmail:  req.UpdateEntityEmail,
+		Token:              uid.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go email.OwnershipTransfer.Nominated(&email.OwnershipTransferEmail{
+		NominatedBy:   created.NominatedBy,
+		EntityName:    created.EntityName,
+		ReceiverEmail: created.Email,
+		Token:         created.Token,
+	})
+
+	return created, nil
+}
+
+type ownershipTransferData struct {
+	*types.OwnershipTransferRespond
+	Token string `json:"token,omitempty"`
+}
+
+func (handler *ownershipTransferHandler) newData(transfer *types.OwnershipTransfer) ownershipTransferData {
+	data := ownershipTransferData{
+		OwnershipTransferRespond: types.NewOwnershipTransferRespond(transfer, logic.OwnershipTransfer.ExpiresAt(transfer)),
+	}
+	if viper.GetString("env") == "development" {
+		data.Token = transfer.Token
+	}
+	return data
+}
+
+// POST /user/entities/{entityID}/ownership-transfer
+
+func (handler *ownershipTransferHandler) nominateOwner() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data ownershipTransferData `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newOwnershipTransferReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !logic.Entity.HasRole(req.Entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] OwnershipTransferHandler.nominateOwner failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		created, err := handler.create(req, user.Email)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.NominateEntityOwner(user, created)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: handler.newData(created)})
+	}
+}
+
+// GET /user/entities/{entityID}/ownership-transfer
+
+func (handler *ownershipTransferHandler) getPendingTransfer() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.OwnershipTransferRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		transfer, err := logic.OwnershipTransfer.FindPending(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewOwnershipTransferRespond(transfer, logic.OwnershipTransfer.ExpiresAt(transfer))})
+	}
+}
+
+// DELETE /user/entities/{entityID}/ownership-transfer
+
+func (handler *ownershipTransferHandler) revokeOwnershipTransfer() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !logic.Entity.HasRole(entity, r.Header.Get("userID"), constant.EntityRole.Owner) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		transfer, err := logic.OwnershipTransfer.FindPending(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = logic.OwnershipTransfer.Revoke(transfer.ID)
+		if err != nil {
+			l.Logger.Error("[Error] OwnershipTransferHandler.revokeOwnershipTransfer failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.RevokeOwnershipTransfer(r.Header.Get("userID"), transfer)
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
+
+// POST /admin/entities/{entityID}/ownership-transfer
+
+func (handler *ownershipTransferHandler) adminNominateOwner() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data ownershipTransferData `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newOwnershipTransferReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] OwnershipTransferHandler.adminNominateOwner failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		created, err := handler.create(req, admin.Email)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.AdminNominateEntityOwner(admin, created)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: handler.newData(created)})
+	}
+}
+
+// GET /admin/entities/{entityID}/ownership-transfer
+
+func (handler *ownershipTransferHandler) adminGetPendingTransfer() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.OwnershipTransferRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		transfer, err := logic.OwnershipTransfer.FindPending(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewOwnershipTransferRespond(transfer, logic.OwnershipTransfer.ExpiresAt(transfer))})
+	}
+}
+
+// DELETE /admin/entities/{entityID}/ownership-transfer
+
+func (handler *ownershipTransferHandler) adminRevokeOwnershipTransfer() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		transfer, err := logic.OwnershipTransfer.FindPending(entity.ID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = logic.OwnershipTransfer.Revoke(transfer.ID)
+		if err != nil {
+			l.Logger.Error("[Error] OwnershipTransferHandler.adminRevokeOwnershipTransfer failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminRevokeOwnershipTransfer(r.Header.Get("userID"), transfer)
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
+
+// GET /ownership-transfers/{token}
+
+func (handler *ownershipTransferHandler) getOwnershipTransfer() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		*types.OwnershipTransferRespond
+		UserExists bool `json:"userExists"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		transfer, err := logic.OwnershipTransfer.FindByToken(mux.Vars(r)["token"])
+		if err != nil || logic.OwnershipTransfer.IsTokenInvalid(transfer) {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Token is invalid."))
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{
+			OwnershipTransferRespond: types.NewOwnershipTransferRespond(transfer, logic.OwnershipTransfer.ExpiresAt(transfer)),
+			UserExists:               logic.User.EmailExists(transfer.Email),
+		}})
+	}
+}
+
+// POST /ownership-transfers/{token}/accept
+
+func (handler *ownershipTransferHandler) acceptOwnershipTransfer() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		UserID   string `json:"userID"`
+		EntityID string `json:"entityID"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewAcceptInvitationReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		transfer, err := logic.OwnershipTransfer.FindByToken(req.Token)
+		if err != nil || logic.OwnershipTransfer.IsTokenInvalid(transfer) {
+			api.Respond(w, r, http.StatusBadRequest, errors.New("Token is invalid."))
+			return
+		}
+		entity, err := logic.Entity.FindByID(transfer.EntityID)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// The transfer goes either to an existing user or to a new one.
+		user, err := logic.User.FindByEmail(transfer.Email)
+		if err != nil {
+			errs := req.ValidateNewUser(transfer.Email)
+			if len(errs) > 0 {
+				api.Respond(w, r, http.StatusBadRequest, errs)
+				return
+			}
+			user, err = logic.User.Create(&types.User{
+				Email:     transfer.Email,
+				Password:  req.Password,
+				FirstName: req.FirstName,
+				LastName:  req.LastName,
+				Telephone: req.UserPhone,
+			})
+			if err != nil {
+				l.Logger.Error("[Error] OwnershipTransferHandler.acceptOwnershipTransfer failed:", zap.Error(err))
+				api.Respond(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		updated, err := logic.OwnershipTransfer.Complete(transfer, entity, user)
+		if err != nil {
+			l.Logger.Error("[Error] OwnershipTransferHandler.acceptOwnershipTransfer failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{
+			UserID:   user.ID.Hex(),
+			EntityID: updated.ID.Hex(),
+		}})
+	}
+}
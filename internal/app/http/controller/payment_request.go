@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var PaymentRequestHandler = newPaymentRequestHandler()
+
+type paymentRequestHandler struct {
+	once *sync.Once
+}
+
+func newPaymentRequestHandler() *paymentRequestHandler {
+	return &paymentRequestHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *paymentRequestHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		public.Path("/payment-requests/{token}").HandlerFunc(handler.getPaymentRequest()).Methods("GET")
+		private.Path("/payment-requests").HandlerFunc(handler.createPaymentRequest()).Methods("POST")
+		private.Path("/payment-requests/{token}/pay").HandlerFunc(middleware.RequireVerifiedEmail(handler.payPaymentRequest())).Methods("POST")
+	})
+}
+
+// POST /payment-requests
+
+func (handler *paymentRequestHandler) createPaymentRequest() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.PaymentRequestRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newPaymentRequestReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !logic.Entity.HasRole(req.PayeeEntity, r.Header.Get("userID"), constant.EntityRole.Staff) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		created, err := logic.PaymentRequest.Create(req)
+		if err != nil {
+			l.Logger.Error("[Error] PaymentRequestHandler.createPaymentRequest failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewPaymentRequestRespond(created, logic.PaymentRequest.Link(created))})
+
+		go logic.UserAction.CreatePaymentRequest(r.Header.Get("userID"), created)
+	}
+}
+
+func (handler *paymentRequestHandler) newPaymentRequestReq(r *http.Request) (*types.PaymentRequestReq, []error) {
+	var body types.PaymentRequestUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	payeeEntity, err := logic.Entity.FindByAccountNumber(body.Payee)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewPaymentRequestReq(&body, payeeEntity)
+}
+
+// GET /payment-requests/{token}
+
+func (handler *paymentRequestHandler) getPaymentRequest() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.PaymentRequestRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewGetPaymentRequestReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		found, err := logic.PaymentRequest.FindByToken(req.Token)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if req.Format == "json" {
+			api.Respond(w, r, http.StatusOK, respond{Data: types.NewPaymentRequestRespond(found, logic.PaymentRequest.Link(found))})
+			return
+		}
+
+		image, err := logic.PaymentRequest.QRCode(found, req.Format, req.Size)
+		if err != nil {
+			l.Logger.Error("[Error] PaymentRequestHandler.getPaymentRequest failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if req.Format == "svg" {
+			w.Header().Set("Content-Type", "image/svg+xml")
+		} else {
+			w.Header().Set("Content-Type", "image/png")
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(image)
+	}
+}
+
+// POST /payment-requests/{token}/pay
+
+func (handler *paymentRequestHandler) payPaymentRequest() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.ProposeTransferRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		paymentRequest, err := logic.PaymentRequest.FindByToken(mux.Vars(r)["token"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		req, errs := handler.newTransferReq(r, paymentRequest)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		if !logic.Entity.HasRole(req.InitiatorEntity, r.Header.Get("userID"), constant.EntityRole.Staff) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+
+		err = logic.Transfer.CheckBalance(req.FromAccountNumber, req.ToAccountNumber, req.Amount)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = logic.PaymentRequest.SetUsed(paymentRequest)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		journal, err := logic.Transfer.Propose(req)
+		if err != nil {
+			l.Logger.Error("[Error] PaymentRequestHandler.payPaymentRequest failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			err := logic.PaymentRequest.UnsetUsed(paymentRequest)
+			if err != nil {
+				l.Logger.Error("[Error] PaymentRequestHandler.payPaymentRequest failed:", zap.Error(err))
+			}
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewProposeTransferRespond(journal)})
+
+		go logic.UserAction.ProposeTransfer(r.Header.Get("userID"), req)
+		go logic.Email.Transfer.Initiate(req)
+	}
+}
+
+func (handler *paymentRequestHandler) newTransferReq(r *http.Request, paymentRequest *types.PaymentRequest) (*types.TransferReq, []error) {
+	var body types.PayPaymentRequestUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	payerEntity, err := logic.Entity.FindByAccountNumber(body.Payer)
+	if err != nil {
+		return nil, []error{err}
+	}
+	payeeEntity, err := logic.Entity.FindByAccountNumber(paymentRequest.AccountNumber)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewTransferReq(&types.TransferUserReq{
+		TransferDirection:      constant.TransferDirection.Out,
+		InitiatorAccountNumber: payerEntity.AccountNumber,
+		ReceiverAccountNumber:  payeeEntity.AccountNumber,
+		Amount:                 paymentRequest.Amount,
+		Description:            paymentRequest.Description,
+	}, payerEntity, payeeEntity)
+}
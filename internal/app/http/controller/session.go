@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var SessionHandler = newSessionHandler()
+
+type sessionHandler struct {
+	once *sync.Once
+}
+
+func newSessionHandler() *sessionHandler {
+	return &sessionHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *sessionHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		private.Path("/user/sessions").HandlerFunc(handler.listSessions()).Methods("GET")
+		middleware.DenyImpersonation(private.Path("/user/sessions").HandlerFunc(handler.revokeSessions()).Methods("DELETE"))
+		middleware.DenyImpersonation(private.Path("/user/sessions/{sessionID}").HandlerFunc(handler.revokeSession()).Methods("DELETE"))
+
+		adminPrivate.Path("/users/{userID}/sessions").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersRead, handler.adminListSessions())).Methods("GET")
+		adminPrivate.Path("/users/{userID}/sessions").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersWrite, handler.adminRevokeSessions())).Methods("DELETE")
+		adminPrivate.Path("/users/{userID}/sessions/{sessionID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersWrite, handler.adminRevokeSession())).Methods("DELETE")
+	})
+}
+
+func (handler *sessionHandler) toRespond(sessions []*types.RefreshToken, currentSessionID string) []*types.SessionRespond {
+	data := []*types.SessionRespond{}
+	for _, session := range sessions {
+		data = append(data, types.NewSessionRespond(session, currentSessionID))
+	}
+	return data
+}
+
+// GET /user/sessions
+
+func (handler *sessionHandler) listSessions() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.SessionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := primitive.ObjectIDFromHex(r.Header.Get("userID"))
+		if err != nil {
+			api.Respond(w, r, http.StatusUnauthorized, api.ErrUnauthorized)
+			return
+		}
+
+		sessions, err := logic.RefreshToken.FindSessions(userID, false)
+		if err != nil {
+			l.Logger.Error("[Error] SessionHandler.listSessions failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: handler.toRespond(sessions, r.Header.Get("sessionID"))})
+	}
+}
+
+// DELETE /user/sessions
+
+// revokeSessions logs out every other device, the session the request was made with stays active.
+func (handler *sessionHandler) revokeSessions() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		Revoked int `json:"revoked"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] SessionHandler.revokeSessions failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		current, _ := primitive.ObjectIDFromHex(r.Header.Get("sessionID"))
+
+		revoked, err := logic.RefreshToken.RevokeOthers(user.ID, false, current)
+		if err != nil {
+			l.Logger.Error("[Error] SessionHandler.revokeSessions failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.RevokeSessions(user, strconv.Itoa(revoked)+" other sessions", util.IPAddress(r))
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{Revoked: revoked}})
+	}
+}
+
+// DELETE /user/sessions/{sessionID}
+
+func (handler *sessionHandler) revokeSession() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.SessionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] SessionHandler.revokeSession failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		revoked, err := logic.RefreshToken.RevokeSession(user.ID, false, mux.Vars(r)["sessionID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		go logic.UserAction.RevokeSessions(user, revoked.Device.Name+" "+revoked.Device.IP, util.IPAddress(r))
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewSessionRespond(revoked, r.Header.Get("sessionID"))})
+	}
+}
+
+// GET /admin/users/{userID}/sessions
+
+func (handler *sessionHandler) adminListSessions() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data []*types.SessionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserHandler.FindByID(mux.Vars(r)["userID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		sessions, err := logic.RefreshToken.FindSessions(user.ID, false)
+		if err != nil {
+			l.Logger.Error("[Error] SessionHandler.adminListSessions failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: handler.toRespond(sessions, "")})
+	}
+}
+
+// DELETE /admin/users/{userID}/sessions
+
+func (handler *sessionHandler) adminRevokeSessions() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		Revoked int `json:"revoked"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserHandler.FindByID(mux.Vars(r)["userID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		revoked, err := logic.RefreshToken.RevokeOthers(user.ID, false, primitive.NilObjectID)
+		if err != nil {
+			l.Logger.Error("[Error] SessionHandler.adminRevokeSessions failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminRevokeUserSessions(r.Header.Get("userID"), user, "all "+strconv.Itoa(revoked)+" sessions")
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{Revoked: revoked}})
+	}
+}
+
+// DELETE /admin/users/{userID}/sessions/{sessionID}
+
+func (handler *sessionHandler) adminRevokeSession() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.SessionRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserHandler.FindByID(mux.Vars(r)["userID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		revoked, err := logic.RefreshToken.RevokeSession(user.ID, false, mux.Vars(r)["sessionID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		go logic.UserAction.AdminRevokeUserSessions(r.Header.Get("userID"), user, revoked.Device.Name+" "+revoked.Device.IP)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewSessionRespond(revoked, "")})
+	}
+}
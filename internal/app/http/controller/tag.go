@@ -9,7 +9,9 @@ import (
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
 	"github.com/ic3network/mccs-alpha-api/util/l"
 	"go.uber.org/zap"
 )
@@ -35,9 +37,9 @@ func (handler *tagHandler) RegisterRoutes(
 	handler.once.Do(func() {
 		public.Path("/tags").HandlerFunc(handler.searchTag()).Methods("GET")
 
-		adminPrivate.Path("/tags").HandlerFunc(handler.adminCreate()).Methods("POST")
-		adminPrivate.Path("/tags/{id}").HandlerFunc(handler.adminUpdate()).Methods("PATCH")
-		adminPrivate.Path("/tags/{id}").HandlerFunc(handler.adminDelete()).Methods("DELETE")
+		adminPrivate.Path("/tags").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TagsWrite, handler.adminCreate())).Methods("POST")
+		adminPrivate.Path("/tags/{id}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TagsWrite, handler.adminUpdate())).Methods("PATCH")
+		adminPrivate.Path("/tags/{id}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TagsWrite, handler.adminDelete())).Methods("DELETE")
 	})
 }
 
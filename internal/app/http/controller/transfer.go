@@ -10,6 +10,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/util"
@@ -37,13 +38,14 @@ func (handler *transferHandler) RegisterRoutes(
 	adminPrivate *mux.Router,
 ) {
 	handler.once.Do(func() {
-		private.Path("/transfers").HandlerFunc(handler.proposeTransfer()).Methods("POST")
-		private.Path("/transfers").HandlerFunc(handler.searchTransfer()).Methods("GET")
-		private.Path("/transfers/{transferID}").HandlerFunc(handler.updateTransfer()).Methods("PATCH")
-
-		adminPrivate.Path("/transfers").HandlerFunc(handler.adminCreateTransfer()).Methods("POST")
-		adminPrivate.Path("/transfers").HandlerFunc(handler.adminSearchTransfer()).Methods("GET")
-		adminPrivate.Path("/transfers/{transferID}").HandlerFunc(handler.adminGetTransfer()).Methods("GET")
+		middleware.AllowAPIKey(private.Path("/transfers").HandlerFunc(middleware.RequireVerifiedEmail(handler.proposeTransfer())).Methods("POST"), constant.APIKeyScope.ProposeTransfers)
+		middleware.AllowAPIKey(private.Path("/transfers").HandlerFunc(handler.searchTransfer()).Methods("GET"), constant.APIKeyScope.ReadTransfers)
+		private.Path("/transfers/{transferID}").HandlerFunc(middleware.RequireVerifiedEmail(handler.updateTransfer())).Methods("PATCH")
+
+		adminPrivate.Path("/transfers").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TransfersWrite, handler.adminCreateTransfer())).Methods("POST")
+		adminPrivate.Path("/transfers").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TransfersRead, handler.adminSearchTransfer())).Methods("GET")
+		adminPrivate.Path("/transfers/{transferID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TransfersRead, handler.adminGetTransfer())).Methods("GET")
+		adminPrivate.Path("/entities/{entityID}/write-off").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.TransfersWrite, handler.adminWriteOff())).Methods("POST")
 	})
 }
 
@@ -60,7 +62,7 @@ func (handler *transferHandler) proposeTransfer() func(http.ResponseWriter, *htt
 			return
 		}
 
-		if !UserHandler.IsEntityBelongsToUser(req.InitiatorEntity.ID.Hex(), r.Header.Get("userID")) {
+		if !logic.Entity.HasRole(req.InitiatorEntity, r.Header.Get("userID"), constant.EntityRole.Staff) || !middleware.IsAPIKeyEntity(r, req.InitiatorEntity.ID.Hex()) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -124,7 +126,7 @@ func (handler *transferHandler) searchTransfer() func(http.ResponseWriter, *http
 			return
 		}
 
-		if !UserHandler.IsEntityBelongsToUser(req.QueryingEntityID, r.Header.Get("userID")) {
+		if !UserHandler.IsEntityBelongsToUser(req.QueryingEntityID, r.Header.Get("userID")) || !middleware.IsAPIKeyEntity(r, req.QueryingEntityID) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -274,10 +276,24 @@ func (handler *transferHandler) checkPermissions(req *types.UpdateTransferReq) e
 		if req.Action != "cancel" {
 			return errors.New("You don't have permission to perform this action.")
 		}
-	} else {
-		if req.Action != "accept" && req.Action != "reject" {
+		if !logic.Entity.HasRole(req.InitiateEntity, req.LoggedInUserID, constant.EntityRole.Staff) {
 			return errors.New("You don't have permission to perform this action.")
 		}
+		return nil
+	}
+
+	if req.Action != "accept" && req.Action != "reject" {
+		return errors.New("You don't have permission to perform this action.")
+	}
+	receiverEntity := req.FromEntity
+	if req.InitiateEntity.ID == req.FromEntity.ID {
+		receiverEntity = req.ToEntity
+	}
+	if req.Action == "accept" && !logic.Entity.CanAcceptTransfer(receiverEntity, req.LoggedInUserID, req.Journal.Amount) {
+		return errors.New("You don't have permission to accept this transfer.")
+	}
+	if req.Action == "reject" && !logic.Entity.HasRole(receiverEntity, req.LoggedInUserID, constant.EntityRole.Staff) {
+		return errors.New("You don't have permission to perform this action.")
 	}
 
 	return nil
@@ -323,6 +339,13 @@ func (handler *transferHandler) checkBalances(req *types.UpdateTransferReq) erro
 		return errors.New(reason)
 	}
 
+	if req.Action == "accept" {
+		err = logic.Transfer.CheckVelocity(req.Journal.FromAccountNumber, req.Journal.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -332,6 +355,7 @@ func (handler *transferHandler) acceptTransfer(j *types.Journal) (*types.Journal
 		return nil, err
 	}
 	go logic.Email.Transfer.Accept(j)
+	go logic.BalanceAlert.Check(updated)
 	return updated, nil
 }
 
@@ -415,6 +439,7 @@ func (handler *transferHandler) adminCreateTransfer() func(http.ResponseWriter,
 		}
 
 		go logic.UserAction.AdminTransfer(r.Header.Get("userID"), journal)
+		go logic.BalanceAlert.Check(journal)
 
 		api.Respond(w, r, http.StatusOK, respond{Data: types.NewJournalToAdminTransferRespond(journal)})
 	}
@@ -441,6 +466,62 @@ func (handler *transferHandler) newAdminTransferReq(r *http.Request) (*types.Adm
 	return types.NewAdminTransferReq(&body, payerEntity, payeeEntity)
 }
 
+// POST /admin/entities/{entityID}/write-off
+
+func (handler *transferHandler) adminWriteOff() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.AdminTransferRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := handler.newAdminWriteOffReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		journal, err := logic.Transfer.WriteOff(req)
+		if err == logic.ErrNothingToWriteOff || err == logic.ErrPendingTransfers {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			l.Logger.Error("[Error] TransferHandler.adminWriteOff failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminWriteOff(r.Header.Get("userID"), journal)
+		go logic.BalanceAlert.WriteOff(req)
+
+		api.Respond(w, r, http.StatusOK, respond{Data: types.NewJournalToAdminTransferRespond(journal)})
+	}
+}
+
+func (handler *transferHandler) newAdminWriteOffReq(r *http.Request) (*types.AdminWriteOffReq, []error) {
+	var body types.AdminWriteOffUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	entity, err := logic.Entity.FindByStringID(mux.Vars(r)["entityID"])
+	if err != nil {
+		return nil, []error{err}
+	}
+	reserveEntity, err := logic.Transfer.FindWriteOffEntity()
+	if err != nil {
+		return nil, []error{err}
+	}
+	account, err := logic.Account.FindByAccountNumber(entity.AccountNumber)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return types.NewAdminWriteOffReq(&body, entity, reserveEntity, account.Balance)
+}
+
 // GET /admin/transfers/{transferID}
 
 func (handler *transferHandler) adminGetTransfer() func(http.ResponseWriter, *http.Request) {
@@ -0,0 +1,399 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+var TwoFactorHandler = newTwoFactorHandler()
+
+type twoFactorHandler struct {
+	once *sync.Once
+}
+
+func newTwoFactorHandler() *twoFactorHandler {
+	return &twoFactorHandler{
+		once: new(sync.Once),
+	}
+}
+
+func (handler *twoFactorHandler) RegisterRoutes(
+	public *mux.Router,
+	private *mux.Router,
+	adminPublic *mux.Router,
+	adminPrivate *mux.Router,
+) {
+	handler.once.Do(func() {
+		middleware.RateLimit(public.Path("/login/2fa").HandlerFunc(handler.login()).Methods("POST"), constant.RateLimitGroup.Auth)
+		middleware.DenyImpersonation(private.Path("/user/2fa/setup").HandlerFunc(handler.setup()).Methods("POST"))
+		middleware.DenyImpersonation(private.Path("/user/2fa/enable").HandlerFunc(handler.enable()).Methods("POST"))
+		middleware.DenyImpersonation(private.Path("/user/2fa/disable").HandlerFunc(handler.disable()).Methods("POST"))
+		middleware.DenyImpersonation(private.Path("/user/2fa/recovery-codes").HandlerFunc(handler.regenerateRecoveryCodes()).Methods("POST"))
+
+		middleware.RateLimit(adminPublic.Path("/login/2fa/setup").HandlerFunc(handler.adminLoginSetup()).Methods("POST"), constant.RateLimitGroup.Auth)
+		middleware.RateLimit(adminPublic.Path("/login/2fa").HandlerFunc(handler.adminLogin()).Methods("POST"), constant.RateLimitGroup.Auth)
+		adminPrivate.Path("/2fa/recovery-codes").HandlerFunc(handler.adminRegenerateRecoveryCodes()).Methods("POST")
+		adminPrivate.Path("/users/{userID}/2fa").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersWrite, handler.adminResetUser())).Methods("DELETE")
+	})
+}
+
+func (handler *twoFactorHandler) setupRespond(secret string, uri string) (*types.TwoFactorSetupRespond, error) {
+	qrCode, err := logic.TwoFactor.QRCode(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TwoFactorSetupRespond{
+		Secret: secret,
+		URI:    uri,
+		QRCode: qrCode,
+	}, nil
+}
+
+// POST /login/2fa
+
+func (handler *twoFactorHandler) login() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.LoginRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewTwoFactorLoginReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		userID, err := logic.TwoFactor.FindChallenge(req.TwoFactorToken, false)
+		if err != nil {
+			api.Respond(w, r, http.StatusUnauthorized, err)
+			return
+		}
+		user, err := logic.User.FindByID(userID)
+		if err != nil {
+			api.Respond(w, r, http.StatusUnauthorized, logic.ErrInvalidTwoFactorChallenge)
+			return
+		}
+
+		err = logic.User.VerifyTwoFactor(user, req.Code)
+		if err != nil {
+			go logic.User.IncLoginAttempts(user.Email)
+			go logic.UserAction.LoginFail(user.Email, util.IPAddress(r))
+
+			l.Logger.Info("[INFO] TwoFactorHandler.login failed", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		logic.TwoFactor.DeleteChallenge(req.TwoFactorToken, false)
+
+		data, err := UserHandler.completeLogin(r, user)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.login failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /user/2fa/setup
+
+func (handler *twoFactorHandler) setup() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TwoFactorSetupRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.setup failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		secret, uri, err := logic.User.SetupTwoFactor(user)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		data, err := handler.setupRespond(secret, uri)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.setup failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /user/2fa/enable
+
+func (handler *twoFactorHandler) enable() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.RecoveryCodesRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewTwoFactorCodeReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.enable failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		codes, err := logic.User.EnableTwoFactor(user, req.Code)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		go logic.UserAction.EnableTwoFactor(user, util.IPAddress(r))
+
+		api.Respond(w, r, http.StatusOK, respond{Data: &types.RecoveryCodesRespond{RecoveryCodes: codes}})
+	}
+}
+
+// POST /user/2fa/disable
+
+func (handler *twoFactorHandler) disable() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewTwoFactorCodeReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.disable failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		err = logic.User.VerifyTwoFactor(user, req.Code)
+		if err != nil {
+			go logic.User.IncLoginAttempts(user.Email)
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		err = logic.User.DisableTwoFactor(user.ID)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.disable failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.DisableTwoFactor(user, util.IPAddress(r))
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
+
+// POST /user/2fa/recovery-codes
+
+func (handler *twoFactorHandler) regenerateRecoveryCodes() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.RecoveryCodesRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewTwoFactorCodeReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		user, err := UserHandler.FindByID(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.regenerateRecoveryCodes failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		err = logic.User.VerifyTwoFactor(user, req.Code)
+		if err != nil {
+			go logic.User.IncLoginAttempts(user.Email)
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		codes, err := logic.User.RegenerateRecoveryCodes(user)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.regenerateRecoveryCodes failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.RegenerateRecoveryCodes(user, util.IPAddress(r))
+
+		api.Respond(w, r, http.StatusOK, respond{Data: &types.RecoveryCodesRespond{RecoveryCodes: codes}})
+	}
+}
+
+// POST /admin/login/2fa/setup
+
+func (handler *twoFactorHandler) adminLoginSetup() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.TwoFactorSetupRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewTwoFactorLoginSetupReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		adminID, err := logic.TwoFactor.FindChallenge(req.TwoFactorToken, true)
+		if err != nil {
+			api.Respond(w, r, http.StatusUnauthorized, err)
+			return
+		}
+		admin, err := logic.AdminUser.FindByID(adminID)
+		if err != nil {
+			api.Respond(w, r, http.StatusUnauthorized, logic.ErrInvalidTwoFactorChallenge)
+			return
+		}
+
+		secret, uri, err := logic.AdminUser.SetupTwoFactor(admin)
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		data, err := handler.setupRespond(secret, uri)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.adminLoginSetup failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /admin/login/2fa
+
+func (handler *twoFactorHandler) adminLogin() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.LoginRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewTwoFactorLoginReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		adminID, err := logic.TwoFactor.FindChallenge(req.TwoFactorToken, true)
+		if err != nil {
+			api.Respond(w, r, http.StatusUnauthorized, err)
+			return
+		}
+		admin, err := logic.AdminUser.FindByID(adminID)
+		if err != nil {
+			api.Respond(w, r, http.StatusUnauthorized, logic.ErrInvalidTwoFactorChallenge)
+			return
+		}
+
+		// The first code of an admin without two-factor authentication completes the enrolment.
+		var recoveryCodes []string
+		if admin.TwoFactor.Enabled() {
+			err = logic.AdminUser.VerifyTwoFactor(admin, req.Code)
+		} else {
+			recoveryCodes, err = logic.AdminUser.EnableTwoFactor(admin, req.Code)
+		}
+		if err != nil {
+			go logic.AdminUser.IncLoginAttempts(admin.Email)
+			go logic.UserAction.AdminLoginFail(admin.Email, util.IPAddress(r))
+
+			l.Logger.Info("[Info] TwoFactorHandler.adminLogin failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		logic.TwoFactor.DeleteChallenge(req.TwoFactorToken, true)
+		if len(recoveryCodes) != 0 {
+			go logic.UserAction.AdminEnableTwoFactor(admin, util.IPAddress(r))
+		}
+
+		data, err := AdminUserHandler.completeLogin(r, admin)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.adminLogin failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		data.RecoveryCodes = recoveryCodes
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
+
+// POST /admin/2fa/recovery-codes
+
+func (handler *twoFactorHandler) adminRegenerateRecoveryCodes() func(http.ResponseWriter, *http.Request) {
+	type respond struct {
+		Data *types.RecoveryCodesRespond `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewTwoFactorCodeReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		admin, err := logic.AdminUser.FindByIDString(r.Header.Get("userID"))
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.adminRegenerateRecoveryCodes failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		err = logic.AdminUser.VerifyTwoFactor(admin, req.Code)
+		if err != nil {
+			go logic.AdminUser.IncLoginAttempts(admin.Email)
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+		codes, err := logic.AdminUser.RegenerateRecoveryCodes(admin)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.adminRegenerateRecoveryCodes failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminRegenerateRecoveryCodes(admin, util.IPAddress(r))
+
+		api.Respond(w, r, http.StatusOK, respond{Data: &types.RecoveryCodesRespond{RecoveryCodes: codes}})
+	}
+}
+
+// DELETE /admin/users/{userID}/2fa
+
+func (handler *twoFactorHandler) adminResetUser() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := logic.User.FindByStringID(mux.Vars(r)["userID"])
+		if err != nil {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = logic.User.DisableTwoFactor(user.ID)
+		if err != nil {
+			l.Logger.Error("[Error] TwoFactorHandler.adminResetUser failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		go logic.UserAction.AdminResetUserTwoFactor(r.Header.Get("userID"), user)
+
+		api.Respond(w, r, http.StatusOK)
+	}
+}
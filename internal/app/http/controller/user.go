@@ -5,17 +5,17 @@ import (
 	"errors"
 	"net/http"
 	"sync"
-	"time"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
 	"github.com/ic3network/mccs-alpha-api/util"
 	"github.com/ic3network/mccs-alpha-api/util/cookie"
-	"github.com/ic3network/mccs-alpha-api/util/jwt"
 	"github.com/ic3network/mccs-alpha-api/util/l"
 	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -41,23 +41,24 @@ func (handler *userHandler) RegisterRoutes(
 	adminPrivate *mux.Router,
 ) {
 	handler.once.Do(func() {
-		public.Path("/login").HandlerFunc(handler.login()).Methods("POST")
-		public.Path("/signup").HandlerFunc(handler.signup()).Methods("POST")
+		middleware.RateLimit(public.Path("/login").HandlerFunc(handler.login()).Methods("POST"), constant.RateLimitGroup.Auth)
+		middleware.RateLimit(public.Path("/signup").HandlerFunc(handler.signup()).Methods("POST"), constant.RateLimitGroup.Auth)
+		public.Path("/refresh").HandlerFunc(handler.refresh()).Methods("POST")
 		private.Path("/logout").HandlerFunc(handler.logout()).Methods("POST")
 
-		public.Path("/password-reset").HandlerFunc(handler.requestPasswordReset()).Methods("POST")
-		public.Path("/password-reset/{token}").HandlerFunc(handler.passwordReset()).Methods("POST")
-		private.Path("/password-change").HandlerFunc(handler.passwordChange()).Methods("POST")
+		middleware.RateLimit(public.Path("/password-reset").HandlerFunc(handler.requestPasswordReset()).Methods("POST"), constant.RateLimitGroup.Auth)
+		middleware.RateLimit(public.Path("/password-reset/{token}").HandlerFunc(handler.passwordReset()).Methods("POST"), constant.RateLimitGroup.Auth)
+		middleware.DenyImpersonation(private.Path("/password-change").HandlerFunc(handler.passwordChange()).Methods("POST"))
 
 		private.Path("/user").HandlerFunc(handler.userProfile()).Methods("GET")
 		private.Path("/user").HandlerFunc(handler.updateUser()).Methods("PATCH")
 		private.Path("/user/entities").HandlerFunc(handler.listUserEntities()).Methods("GET")
 		private.Path("/user/entities/{entityID}").HandlerFunc(handler.updateUserEntity()).Methods("PATCH")
 
-		adminPrivate.Path("/users").HandlerFunc(handler.adminSearchUser()).Methods("GET")
-		adminPrivate.Path("/users/{userID}").HandlerFunc(handler.adminGetUser()).Methods("GET")
-		adminPrivate.Path("/users/{userID}").HandlerFunc(handler.adminUpdateUser()).Methods("PATCH")
-		adminPrivate.Path("/users/{userID}").HandlerFunc(handler.adminDeleteUser()).Methods("DELETE")
+		adminPrivate.Path("/users").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersRead, handler.adminSearchUser())).Methods("GET")
+		adminPrivate.Path("/users/{userID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersRead, handler.adminGetUser())).Methods("GET")
+		adminPrivate.Path("/users/{userID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersWrite, handler.adminUpdateUser())).Methods("PATCH")
+		adminPrivate.Path("/users/{userID}").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.UsersDelete, handler.adminDeleteUser())).Methods("DELETE")
 	})
 }
 
@@ -96,26 +97,20 @@ func (handler *userHandler) IsEntityBelongsToUser(entityID, userID string) bool
 	return false
 }
 
+// HasEntityRole checks whether the user is a member of the entity with at least the given role.
+func (handler *userHandler) HasEntityRole(entityID, userID, role string) bool {
+	entity, err := logic.Entity.FindByStringID(entityID)
+	if err != nil {
+		return false
+	}
+	return logic.Entity.HasRole(entity, userID, role)
+}
+
 // POST /login
 
 func (handler *userHandler) login() func(http.ResponseWriter, *http.Request) {
-	type data struct {
-		Token         string     `json:"token"`
-		LastLoginIP   string     `json:"lastLoginIP,omitempty"`
-		LastLoginDate *time.Time `json:"lastLoginDate,omitempty"`
-	}
 	type respond struct {
-		Data data `json:"data"`
-	}
-	respondData := func(info *types.LoginInfo, token string) data {
-		d := data{Token: token}
-		if info.LastLoginIP != "" {
-			d.LastLoginIP = info.LastLoginIP
-		}
-		if !info.LastLoginDate.IsZero() {
-			d.LastLoginDate = &info.LastLoginDate
-		}
-		return d
+		Data *types.LoginRespond `json:"data"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		req, errs := types.NewLoginReq(r)
@@ -135,26 +130,55 @@ func (handler *userHandler) login() func(http.ResponseWriter, *http.Request) {
 
 			return
 		}
-		loginInfo, err := logic.User.UpdateLoginInfo(user.ID, util.IPAddress(r))
-		if err != nil {
-			l.Logger.Error("[Error] AdminUser.UpdateLoginInfo failed:", zap.Error(err))
+
+		// The tokens are only issued once the code has been checked by POST /login/2fa.
+		if user.TwoFactor.Enabled() {
+			twoFactorToken, err := logic.TwoFactor.NewChallenge(user.ID, false)
+			if err != nil {
+				l.Logger.Error("[Error] UserHandler.login failed:", zap.Error(err))
+				api.Respond(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			api.Respond(w, r, http.StatusOK, respond{Data: types.NewTwoFactorChallengeRespond(twoFactorToken, false)})
+			return
 		}
 
-		go logic.UserAction.Login(user, util.IPAddress(r))
+		data, err := handler.completeLogin(r, user)
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.login failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		api.Respond(w, r, http.StatusOK, respond{Data: data})
+	}
+}
 
-		token, err := jwt.NewJWTManager().Generate(user.ID.Hex(), false)
+// completeLogin issues the tokens once the user has been authenticated.
+func (handler *userHandler) completeLogin(r *http.Request, user *types.User) (*types.LoginRespond, error) {
+	loginInfo, err := logic.User.UpdateLoginInfo(user.ID, util.IPAddress(r))
+	if err != nil {
+		l.Logger.Error("[Error] AdminUser.UpdateLoginInfo failed:", zap.Error(err))
+		loginInfo = &types.LoginInfo{}
+	}
 
-		api.Respond(w, r, http.StatusOK, respond{Data: respondData(loginInfo, token)})
+	tokens, err := logic.RefreshToken.Issue(user.ID, false, types.NewDevice(r))
+	if err != nil {
+		return nil, err
 	}
+
+	go logic.UserAction.Login(user, util.IPAddress(r))
+
+	return types.NewLoginRespond(loginInfo, tokens.AccessToken, tokens.RefreshToken), nil
 }
 
 // POST /signup
 
 func (handler *userHandler) signup() func(http.ResponseWriter, *http.Request) {
 	type data struct {
-		UserID   string `json:"userID"`
-		EntityID string `json:"entityID"`
-		Token    string `json:"token"`
+		UserID       string `json:"userID"`
+		EntityID     string `json:"entityID"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
 	}
 	type respond struct {
 		Data data `json:"data"`
@@ -189,6 +213,7 @@ func (handler *userHandler) signup() func(http.ResponseWriter, *http.Request) {
 			Wants:                              types.ToTagFields(req.Wants),
 			ShowTagsMatchedSinceLastLogin:      req.ShowTagsMatchedSinceLastLogin,
 			ReceiveDailyMatchNotificationEmail: req.ReceiveDailyMatchNotificationEmail,
+			EmailUnverified:                    true,
 		})
 		if err != nil {
 			l.Logger.Error("[ERROR] UserHandler.signup failed", zap.Error(err))
@@ -196,11 +221,12 @@ func (handler *userHandler) signup() func(http.ResponseWriter, *http.Request) {
 			return
 		}
 		createdUser, err := logic.User.Create(&types.User{
-			Email:     req.UserEmail,
-			Password:  req.Password,
-			FirstName: req.FirstName,
-			LastName:  req.LastName,
-			Telephone: req.UserPhone,
+			Email:           req.UserEmail,
+			Password:        req.Password,
+			FirstName:       req.FirstName,
+			LastName:        req.LastName,
+			Telephone:       req.UserPhone,
+			EmailUnverified: true,
 		})
 		if err != nil {
 			l.Logger.Error("[ERROR] UserHandler.signup failed", zap.Error(err))
@@ -220,7 +246,7 @@ func (handler *userHandler) signup() func(http.ResponseWriter, *http.Request) {
 			return
 		}
 
-		token, err := jwt.NewJWTManager().Generate(createdUser.ID.Hex(), false)
+		tokens, err := logic.RefreshToken.Issue(createdUser.ID, false, types.NewDevice(r))
 		if err != nil {
 			l.Logger.Error("[ERROR] UserHandler.signup failed", zap.Error(err))
 			api.Respond(w, r, http.StatusInternalServerError, err)
@@ -228,20 +254,65 @@ func (handler *userHandler) signup() func(http.ResponseWriter, *http.Request) {
 		}
 
 		go logic.UserAction.Signup(createdUser, createdEntity)
-		go email.Welcome(&email.WelcomeEmail{
-			EntityName: req.EntityName,
-			Email:      req.EntityEmail,
-			Receiver:   req.FirstName + " " + req.LastName,
-		})
+		go logic.EntityStatus.Record(createdEntity, "", createdEntity.Status, "", createdUser.Email)
+		go logic.Application.Create(createdEntity)
+		go handler.sendSignupVerification(createdUser, createdEntity)
 		go email.Signup(&email.SignupNotificationEmail{
 			EntityName:   req.EntityName,
 			ContactEmail: req.EntityEmail,
 		})
 
 		api.Respond(w, r, http.StatusOK, respond{Data: data{
-			UserID:   createdUser.ID.Hex(),
-			EntityID: createdEntity.ID.Hex(),
-			Token:    token,
+			UserID:       createdUser.ID.Hex(),
+			EntityID:     createdEntity.ID.Hex(),
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+		}})
+	}
+}
+
+// sendSignupVerification asks a new user to confirm the email addresses given on signup.
+// The entity email address is confirmed along with the user one when they are the same.
+func (handler *userHandler) sendSignupVerification(user *types.User, entity *types.Entity) {
+	_, err := logic.EmailVerification.SendToUser(user, true)
+	if err != nil {
+		l.Logger.Error("[ERROR] UserHandler.sendSignupVerification failed", zap.Error(err))
+	}
+	if entity.Email != user.Email {
+		_, err = logic.EmailVerification.SendToEntity(entity)
+		if err != nil {
+			l.Logger.Error("[ERROR] UserHandler.sendSignupVerification failed", zap.Error(err))
+		}
+	}
+}
+
+// POST /refresh
+
+func (handler *userHandler) refresh() func(http.ResponseWriter, *http.Request) {
+	type data struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	type respond struct {
+		Data data `json:"data"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, errs := types.NewRefreshTokenReq(r)
+		if len(errs) > 0 {
+			api.Respond(w, r, http.StatusBadRequest, errs)
+			return
+		}
+
+		tokens, err := logic.RefreshToken.Refresh(req.RefreshToken, false, types.NewDevice(r))
+		if err != nil {
+			l.Logger.Info("[INFO] UserHandler.refresh failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		api.Respond(w, r, http.StatusOK, respond{Data: data{
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
 		}})
 	}
 }
@@ -250,6 +321,12 @@ func (handler *userHandler) signup() func(http.ResponseWriter, *http.Request) {
 
 func (handler *userHandler) logout() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
+		err := logic.RefreshToken.RevokeByStringID(r.Header.Get("sessionID"))
+		if err != nil {
+			l.Logger.Error("[Error] UserHandler.logout failed:", zap.Error(err))
+			api.Respond(w, r, http.StatusInternalServerError, err)
+			return
+		}
 		http.SetCookie(w, cookie.ResetCookie())
 		api.Respond(w, r, http.StatusOK)
 	}
@@ -345,6 +422,10 @@ func (handler *userHandler) passwordReset() func(http.ResponseWriter, *http.Requ
 		}
 
 		err = logic.User.ResetPassword(lostPassword.Email, req.Password)
+		if err == logic.ErrPasswordReused {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
 		if err != nil {
 			l.Logger.Error("[ERROR] UserHandler.passwordReset failed:", zap.Error(err))
 			api.Respond(w, r, http.StatusInternalServerError, err)
@@ -398,6 +479,10 @@ func (handler *userHandler) passwordChange() func(http.ResponseWriter, *http.Req
 		}
 
 		err = logic.User.ResetPassword(user.Email, req.Password)
+		if err == logic.ErrPasswordReused {
+			api.Respond(w, r, http.StatusBadRequest, err)
+			return
+		}
 		if err != nil {
 			l.Logger.Error("[ERROR] UserHandler.passwordChange failed:", zap.Error(err))
 			api.Respond(w, r, http.StatusInternalServerError, err)
@@ -506,7 +591,7 @@ func (handler *userHandler) updateUserEntity() func(http.ResponseWriter, *http.R
 			return
 		}
 
-		if !handler.IsEntityBelongsToUser(req.OriginEntity.ID.Hex(), r.Header.Get("userID")) {
+		if !logic.Entity.HasRole(req.OriginEntity, r.Header.Get("userID"), constant.EntityRole.Owner) {
 			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
 			return
 		}
@@ -528,6 +613,13 @@ func (handler *userHandler) updateUserEntity() func(http.ResponseWriter, *http.R
 			AddedWants:    req.AddedWants,
 		})
 
+		if updated.Email != req.OriginEntity.Email {
+			_, err = logic.EmailVerification.EntityEmailChanged(updated)
+			if err != nil {
+				l.Logger.Error("[Error] UserHandler.updateUserEntity failed:", zap.Error(err))
+			}
+		}
+
 		go logic.UserAction.ModifyEntity(r.Header.Get("userID"), req.OriginEntity, updated)
 
 		res, err := EntityHandler.NewEntityRespond(updated)
@@ -666,6 +758,13 @@ func (handler *userHandler) adminUpdateUser() func(http.ResponseWriter, *http.Re
 			return
 		}
 
+		if updated.Email != req.OriginUser.Email {
+			_, err = logic.EmailVerification.UserEmailChanged(updated)
+			if err != nil {
+				l.Logger.Error("[Error] UserHandler.adminUpdateUser failed:", zap.Error(err))
+			}
+		}
+
 		go logic.UserAction.AdminModifyUser(r.Header.Get("userID"), req.OriginUser, updated)
 
 		api.Respond(w, r, http.StatusOK, respond{Data: types.NewAdminGetUserRespond(updated, entities)})
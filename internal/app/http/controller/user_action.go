@@ -5,7 +5,9 @@ import (
 	"sync"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/http/middleware"
 	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/util/l"
@@ -26,7 +28,7 @@ func newUserAction() *userAction {
 
 func (ua *userAction) RegisterRoutes(adminPrivate *mux.Router) {
 	ua.once.Do(func() {
-		adminPrivate.Path("/logs").HandlerFunc(ua.search()).Methods("GET")
+		adminPrivate.Path("/logs").HandlerFunc(middleware.RequirePermission(constant.AdminPermission.LogsRead, ua.search())).Methods("GET")
 	})
 }
 
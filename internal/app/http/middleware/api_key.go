@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/util"
+)
+
+// apiKeyRoutes holds the routes which accept API keys and the scope each of them needs.
+// It is only written while the routes are registered.
+var apiKeyRoutes = map[*mux.Route]string{}
+
+// AllowAPIKey lets the requests made with an API key which has the scope call the route.
+// Every other route refuses API keys.
+func AllowAPIKey(route *mux.Route, scope string) *mux.Route {
+	apiKeyRoutes[route] = scope
+	return route
+}
+
+// IsAPIKeyEntity checks that a request made with an API key only acts on the entity of the key.
+// Requests made with an access token are not restricted.
+func IsAPIKeyEntity(r *http.Request, entityID string) bool {
+	keyEntityID := r.Header.Get("apiKeyEntityID")
+	return keyEntityID == "" || keyEntityID == entityID
+}
+
+// authenticateAPIKey sets the identity headers of the member who created the key.
+// It returns false when the request has been answered.
+func authenticateAPIKey(w http.ResponseWriter, r *http.Request, key string) bool {
+	// The allow-list has to be checked against an address the caller cannot choose.
+	ipAddress := util.ClientIP(r)
+	found, err := logic.APIKey.Authenticate(key, ipAddress)
+	if err != nil {
+		api.Respond(w, r, http.StatusUnauthorized, err)
+		return false
+	}
+	scope, ok := apiKeyRoutes[mux.CurrentRoute(r)]
+	if !ok || !logic.APIKey.HasScope(found, scope) {
+		api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+		return false
+	}
+
+	go logic.APIKey.RecordUse(found, ipAddress)
+
+	r.Header.Set("userID", found.CreatedBy.Hex())
+	r.Header.Set("admin", "false")
+	r.Header.Set("apiKeyID", found.ID.Hex())
+	r.Header.Set("apiKeyEntityID", found.EntityID.Hex())
+	r.Header.Set("apiKeyScopes", strings.Join(found.Scopes, ","))
+	return true
+}
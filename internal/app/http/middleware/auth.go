@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
 	"github.com/ic3network/mccs-alpha-api/util/jwt"
 )
 
@@ -18,19 +19,47 @@ const (
 func GetLoggedInUser() mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The identity headers are only ever set from a verified token.
+			r.Header.Del("userID")
+			r.Header.Del("admin")
+			r.Header.Del("sessionID")
+			r.Header.Del("permissions")
+			r.Header.Del("apiKeyID")
+			r.Header.Del("apiKeyEntityID")
+			r.Header.Del("apiKeyScopes")
+			r.Header.Del("impersonatedBy")
+			r.Header.Del("impersonationWrite")
+
 			// Grab the raw Authoirzation header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" || !strings.HasPrefix(authHeader, BEARER_SCHEMA) {
 				next.ServeHTTP(w, r)
 				return
 			}
-			claims, err := jwt.NewJWTManager().Validate(authHeader[len(BEARER_SCHEMA):])
+			token := authHeader[len(BEARER_SCHEMA):]
+			if strings.HasPrefix(token, logic.APIKeyPrefix) {
+				if authenticateAPIKey(w, r, token) {
+					next.ServeHTTP(w, r)
+				}
+				return
+			}
+			claims, err := jwt.NewJWTManager().Validate(token)
 			if err != nil {
 				next.ServeHTTP(w, r)
 				return
 			}
+			// Tokens without a session cannot be revoked and are no longer accepted.
+			if claims.SessionID == "" || logic.RefreshToken.IsRevoked(claims.SessionID) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if claims.ImpersonatedBy != "" && !checkImpersonation(w, r, claims.ImpersonatedBy, claims.UserID, claims.ImpersonationWrite) {
+				return
+			}
 			r.Header.Set("userID", claims.UserID)
 			r.Header.Set("admin", strconv.FormatBool(claims.Admin))
+			r.Header.Set("sessionID", claims.SessionID)
+			r.Header.Set("permissions", strings.Join(claims.Permissions, ","))
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -71,3 +100,35 @@ func RequireAdmin() mux.MiddlewareFunc {
 		})
 	}
 }
+
+// RequirePermission wraps the handler of an admin route which needs the permission.
+func RequirePermission(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !HasPermission(r, permission) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrPermissionDenied)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// HasPermission returns whether the logged in admin has the permission.
+func HasPermission(r *http.Request, permission string) bool {
+	for _, p := range strings.Split(r.Header.Get("permissions"), ",") {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireVerifiedEmail wraps the handler of a user route which needs a confirmed email address.
+func RequireVerifiedEmail(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !logic.EmailVerification.IsUserVerified(r.Header.Get("userID")) {
+			api.Respond(w, r, http.StatusForbidden, api.ErrEmailNotVerified)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/internal/app/api"
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+)
+
+// impersonationDeniedRoutes holds the routes an admin cannot call while impersonating a user.
+// It is only written while the routes are registered.
+var impersonationDeniedRoutes = map[*mux.Route]bool{}
+
+// DenyImpersonation keeps the admins impersonating a user away from the route, even with write access.
+func DenyImpersonation(route *mux.Route) *mux.Route {
+	impersonationDeniedRoutes[route] = true
+	return route
+}
+
+// checkImpersonation records the request an admin makes on behalf of the user and refuses
+// the ones the impersonation token does not allow. It returns false when the request has been answered.
+func checkImpersonation(w http.ResponseWriter, r *http.Request, adminID string, userID string, write bool) bool {
+	// The token stops working as soon as the admin is deleted or loses the permission.
+	if !logic.Impersonation.IsPermitted(adminID, write) {
+		go logic.UserAction.ImpersonatedRequest(adminID, userID, r.Method, r.URL.Path, false)
+		api.Respond(w, r, http.StatusUnauthorized, api.ErrUnauthorized)
+		return false
+	}
+
+	readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+	allowed := !impersonationDeniedRoutes[mux.CurrentRoute(r)] && (readOnly || write)
+
+	go logic.UserAction.ImpersonatedRequest(adminID, userID, r.Method, r.URL.Path, allowed)
+
+	// Lets the front end show a banner while an admin is acting as the user.
+	if write {
+		w.Header().Set("X-Impersonation", "read-write")
+	} else {
+		w.Header().Set("X-Impersonation", "read-only")
+	}
+
+	if !allowed {
+		api.Respond(w, r, http.StatusForbidden, api.ErrImpersonationReadOnly)
+		return false
+	}
+
+	r.Header.Set("impersonatedBy", adminID)
+	r.Header.Set("impersonationWrite", strconv.FormatBool(write))
+	return true
+}
@@ -1,30 +1,82 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/api"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/redis"
-	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/ratelimit"
 	"github.com/spf13/viper"
 )
 
+// rateLimitRoutes holds the routes which do not use the default limit.
+// It is only written while the routes are registered.
+var rateLimitRoutes = map[*mux.Route]string{}
+
+// RateLimit applies the limit of the group to the route instead of the default one.
+func RateLimit(route *mux.Route, group string) *mux.Route {
+	rateLimitRoutes[route] = group
+	return route
+}
+
+// rateLimit returns the number of requests allowed within the window of the group.
+// A group without its own configuration uses `rate_limiting.limit` and `rate_limiting.duration`.
+func rateLimit(group string) (int, time.Duration) {
+	limit := viper.GetInt("rate_limiting.limit")
+	duration := viper.GetDuration("rate_limiting.duration") * time.Minute
+	if group != constant.RateLimitGroup.Default {
+		key := "rate_limiting.groups." + group
+		if viper.IsSet(key + ".limit") {
+			limit = viper.GetInt(key + ".limit")
+		}
+		if viper.IsSet(key + ".duration") {
+			duration = viper.GetDuration(key+".duration") * time.Minute
+		}
+	}
+	if duration <= 0 {
+		duration = time.Minute
+	}
+	return limit, duration
+}
+
+// RateLimiting limits the requests within a sliding window, it has to come after GetLoggedInUser.
+// The limits are kept in Redis so they are shared between the instances, each instance counts on its own while Redis is not available.
 func RateLimiting() mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := util.IPAddress(r)
+			group, ok := rateLimitRoutes[mux.CurrentRoute(r)]
+			if !ok {
+				group = constant.RateLimitGroup.Default
+			}
+			limit, window := rateLimit(group)
+			identity := group + ":" + ratelimit.Identity(r)
+
+			allowed, remaining, reset, err := redis.RateLimit(identity, limit, window)
+			if err != nil {
+				allowed, remaining, reset = localLimiter.Allow(identity, limit, window, time.Now())
+			}
 
-			requestCount := redis.GetRequestCount(ip)
+			seconds := strconv.Itoa(int(math.Ceil(reset.Seconds())))
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("RateLimit-Reset", seconds)
+			w.Header().Set("RateLimit-Policy", strconv.Itoa(limit)+";w="+strconv.Itoa(int(window.Seconds())))
 
-			if requestCount >= viper.GetInt("rate_limiting.limit") {
-				api.Respond(w, r, http.StatusTooManyRequests)
+			if !allowed {
+				w.Header().Set("Retry-After", seconds)
+				api.Respond(w, r, http.StatusTooManyRequests, api.ErrTooManyRequests)
 				return
 			}
 
-			redis.IncRequestCount(ip)
-
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// localLimiter is the in-process sliding window used while Redis is not available.
+var localLimiter = ratelimit.NewSlidingWindow()
@@ -8,20 +8,40 @@ import (
 
 func RegisterRoutes(r *mux.Router) {
 	public := r.PathPrefix("/api/v1").Subrouter()
-	public.Use(middleware.Recover(), middleware.RateLimiting(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser())
+	public.Use(middleware.Recover(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser(), middleware.RateLimiting())
 	private := r.PathPrefix("/api/v1").Subrouter()
-	private.Use(middleware.Recover(), middleware.RateLimiting(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser(), middleware.RequireUser())
+	private.Use(middleware.Recover(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser(), middleware.RateLimiting(), middleware.RequireUser())
 	adminPublic := r.PathPrefix("/api/v1/admin").Subrouter()
-	adminPublic.Use(middleware.Recover(), middleware.RateLimiting(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser())
+	adminPublic.Use(middleware.Recover(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser(), middleware.RateLimiting())
 	adminPrivate := r.PathPrefix("/api/v1/admin").Subrouter()
-	adminPrivate.Use(middleware.Recover(), middleware.RateLimiting(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser(), middleware.RequireAdmin())
+	adminPrivate.Use(middleware.Recover(), middleware.NoCache(), middleware.Logging(), middleware.GetLoggedInUser(), middleware.RateLimiting(), middleware.RequireAdmin())
+	// Served outside of the API prefix where other services look for the keys.
+	wellKnown := r.PathPrefix("/.well-known").Subrouter()
+	wellKnown.Use(middleware.Recover(), middleware.Logging(), middleware.RateLimiting())
 
 	controller.ServiceDiscovery.RegisterRoutes(public, private)
 	controller.UserHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.AdminUserHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.TwoFactorHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.AdminRoleHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.EmailVerificationHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.SessionHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.ImpersonationHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.EntityHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.EntityMemberHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.EntityImageHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.InvitationHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.OwnershipTransferHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.APIKeyHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.ApplicationHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.DataExportHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.ErasureHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.TagHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.CategoryHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.TransferHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.PaymentRequestHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.NotificationHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
+	controller.LedgerHandler.RegisterRoutes(public, private, adminPublic, adminPrivate)
 	controller.UserAction.RegisterRoutes(adminPrivate)
+	controller.JWKSHandler.RegisterRoutes(wellKnown)
 }
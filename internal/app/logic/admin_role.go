@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"errors"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type adminRole struct{}
+
+var AdminRole = &adminRole{}
+
+// superAdmin is not stored, it always has every permission.
+func (a *adminRole) superAdmin() *types.AdminRole {
+	return &types.AdminRole{
+		Name:        constant.AdminRole.SuperAdmin,
+		Description: "Built-in role with every permission.",
+		Permissions: constant.AdminPermissions,
+	}
+}
+
+// Permissions returns the permissions granted by the roles, the roles which do not exist grant nothing.
+func (a *adminRole) Permissions(roleNames []string) ([]string, error) {
+	for _, name := range roleNames {
+		if name == constant.AdminRole.SuperAdmin {
+			return constant.AdminPermissions, nil
+		}
+	}
+	roles, err := mongo.AdminRole.FindByNames(roleNames)
+	if err != nil {
+		return nil, err
+	}
+	granted := map[string]bool{}
+	for _, role := range roles {
+		for _, p := range role.Permissions {
+			granted[p] = true
+		}
+	}
+	// Keeps the order of constant.AdminPermissions and drops unknown permissions.
+	permissions := []string{}
+	for _, p := range constant.AdminPermissions {
+		if granted[p] {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions, nil
+}
+
+// GET /admin/roles
+
+func (a *adminRole) FindAll() ([]*types.AdminRole, error) {
+	roles, err := mongo.AdminRole.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return append([]*types.AdminRole{a.superAdmin()}, roles...), nil
+}
+
+// CheckExist makes sure the roles given to an admin exist.
+func (a *adminRole) CheckExist(roleNames []string) error {
+	names := []string{}
+	for _, name := range roleNames {
+		if name != constant.AdminRole.SuperAdmin {
+			names = append(names, name)
+		}
+	}
+	roles, err := mongo.AdminRole.FindByNames(names)
+	if err != nil {
+		return err
+	}
+	found := map[string]bool{}
+	for _, role := range roles {
+		found[role.Name] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return errors.New("Role " + name + " does not exist.")
+		}
+	}
+	return nil
+}
+
+// POST /admin/roles
+
+func (a *adminRole) Create(role *types.AdminRole) (*types.AdminRole, error) {
+	return mongo.AdminRole.Create(role)
+}
+
+// PATCH /admin/roles/{role}
+
+func (a *adminRole) FindByName(name string) (*types.AdminRole, error) {
+	if name == constant.AdminRole.SuperAdmin {
+		return a.superAdmin(), nil
+	}
+	return mongo.AdminRole.FindByName(name)
+}
+
+func (a *adminRole) FindOneAndUpdate(role *types.AdminRole) (*types.AdminRole, error) {
+	return mongo.AdminRole.FindOneAndUpdate(role)
+}
+
+// DELETE /admin/roles/{role}
+
+func (a *adminRole) Delete(name string) error {
+	count, err := mongo.AdminUser.CountByRole(name)
+	if err != nil {
+		return err
+	}
+	if count != 0 {
+		return errors.New("The role is still given to admins.")
+	}
+	return mongo.AdminRole.Delete(name)
+}
@@ -2,7 +2,10 @@ package logic
 
 import (
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/redis"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
@@ -62,8 +65,7 @@ func (a *adminUser) Login(email string, password string) (*types.AdminUser, erro
 		return nil, errors.New("Invalid password.")
 	}
 
-	redis.ResetLoginAttempts(email)
-
+	// Admins always go through the two-factor step, which resets the counter once the code is verified.
 	return user, nil
 }
 
@@ -91,16 +93,222 @@ func (a *adminUser) ResetPassword(email string, newPassword string) error {
 		return err
 	}
 
-	hashedPassword, err := bcrypt.Hash(newPassword)
+	hashedPassword, history, err := Password.Hash(newPassword, user.Password, user.PasswordHistory)
 	if err != nil {
 		return err
 	}
 
 	user.Password = hashedPassword
+	user.PasswordHistory = history
+	user.PasswordChangedAt = time.Now()
 	err = mongo.AdminUser.UpdatePassword(user)
 	if err != nil {
 		return err
 	}
 
+	// Log out every device which knew the old password.
+	return RefreshToken.RevokeAll(user.ID, true)
+}
+
+// POST /admin/login/2fa/setup
+
+// SetupTwoFactor starts the enrolment, two-factor authentication is only enabled once a code of the new secret is verified.
+func (a *adminUser) SetupTwoFactor(admin *types.AdminUser) (string, string, error) {
+	if admin.TwoFactor.Enabled() {
+		return "", "", errors.New("Two-factor authentication is already enabled.")
+	}
+	secret, uri, err := TwoFactor.NewSecret(admin.Email)
+	if err != nil {
+		return "", "", err
+	}
+	err = mongo.AdminUser.SetPendingTwoFactorSecret(admin.ID, secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, uri, nil
+}
+
+// POST /admin/login/2fa
+
+// EnableTwoFactor returns the recovery codes, they are only stored hashed.
+func (a *adminUser) EnableTwoFactor(admin *types.AdminUser, code string) ([]string, error) {
+	if admin.TwoFactor == nil || admin.TwoFactor.PendingSecret == "" {
+		return nil, errors.New("Two-factor authentication setup has not been started.")
+	}
+	attempts := redis.GetLoginAttempts(admin.Email)
+	if attempts >= viper.GetInt("login_attempts.limit") {
+		return nil, ErrLoginLocked
+	}
+	counter, err := TwoFactor.Counter(admin.TwoFactor.PendingSecret, code)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := TwoFactor.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.AdminUser.EnableTwoFactor(admin.ID, admin.TwoFactor.PendingSecret, counter, hashes)
+	if err != nil {
+		return nil, err
+	}
+	redis.ResetLoginAttempts(admin.Email)
+	return codes, nil
+}
+
+// VerifyTwoFactor checks a code of the admin. It is refused while the login is locked by too many failed attempts.
+func (a *adminUser) VerifyTwoFactor(admin *types.AdminUser, code string) error {
+	attempts := redis.GetLoginAttempts(admin.Email)
+	if attempts >= viper.GetInt("login_attempts.limit") {
+		return ErrLoginLocked
+	}
+
+	err := TwoFactor.Verify(
+		admin.TwoFactor,
+		code,
+		func(counter int64) error { return mongo.AdminUser.UseTwoFactorCounter(admin.ID, counter) },
+		func(hash string) error { return mongo.AdminUser.UseRecoveryCode(admin.ID, hash) },
+	)
+	if err != nil {
+		if attempts+1 >= viper.GetInt("login_attempts.limit") {
+			return ErrLoginLocked
+		}
+		return err
+	}
+
+	redis.ResetLoginAttempts(admin.Email)
+
 	return nil
 }
+
+// POST /admin/2fa/recovery-codes
+
+func (a *adminUser) RegenerateRecoveryCodes(admin *types.AdminUser) ([]string, error) {
+	codes, hashes, err := TwoFactor.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.AdminUser.SetRecoveryCodes(admin.ID, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// GET /admin/admin-users
+
+func (a *adminUser) FindAll() ([]*types.AdminUser, error) {
+	return mongo.AdminUser.FindAll()
+}
+
+// POST /admin/admin-users
+
+func (a *adminUser) Create(admin *types.AdminUser) (*types.AdminUser, error) {
+	err := AdminRole.CheckExist(admin.Roles)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.Hash(admin.Password)
+	if err != nil {
+		return nil, err
+	}
+	admin.Email = strings.ToLower(admin.Email)
+	admin.Password = hashedPassword
+	return mongo.AdminUser.Create(admin)
+}
+
+// PATCH /admin/admin-users/{adminID}
+
+func (a *adminUser) FindOneAndUpdate(id primitive.ObjectID, update *types.AdminUser) (*types.AdminUser, error) {
+	if update.Roles != nil {
+		err := AdminRole.CheckExist(update.Roles)
+		if err != nil {
+			return nil, err
+		}
+		if !hasRole(update.Roles, constant.AdminRole.SuperAdmin) {
+			err = a.checkNotLastSuperAdmin(id)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	updated, err := mongo.AdminUser.FindOneAndUpdate(id, update)
+	if err != nil {
+		return nil, err
+	}
+	// The users the admin impersonates were chosen with the previous roles.
+	if update.Roles != nil {
+		err = RefreshToken.RevokeImpersonations(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+// DELETE /admin/admin-users/{adminID}
+
+func (a *adminUser) FindOneAndDelete(id primitive.ObjectID) (*types.AdminUser, error) {
+	err := a.checkNotLastSuperAdmin(id)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := mongo.AdminUser.FindOneAndDelete(id)
+	if err != nil {
+		return nil, err
+	}
+	err = RefreshToken.RevokeAll(id, true)
+	if err != nil {
+		return nil, err
+	}
+	err = RefreshToken.RevokeImpersonations(id)
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// checkNotLastSuperAdmin stops the admin from losing the superadmin role when nobody else has it,
+// the superadmins are the only admins who can always manage the admins and the roles.
+func (a *adminUser) checkNotLastSuperAdmin(id primitive.ObjectID) error {
+	admin, err := mongo.AdminUser.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if !hasRole(admin.Roles, constant.AdminRole.SuperAdmin) {
+		return nil
+	}
+	count, err := mongo.AdminUser.CountByRole(constant.AdminRole.SuperAdmin)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return ErrLastSuperAdmin
+	}
+	return nil
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// DELETE /admin/admin-users/{adminID}/2fa
+
+// ResetTwoFactor makes the admin enrol again on the next login.
+func (a *adminUser) ResetTwoFactor(id primitive.ObjectID) error {
+	err := mongo.AdminUser.DisableTwoFactor(id)
+	if err != nil {
+		return err
+	}
+	return RefreshToken.RevokeAll(id, true)
+}
+
+// BackfillRoles gives the super admin role to the admins created before admin roles existed,
+// so they keep every permission they had.
+func (a *adminUser) BackfillRoles() (int64, error) {
+	return mongo.AdminUser.BackfillRoles(constant.AdminRole.SuperAdmin)
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// APIKeyPrefix starts every API key so the auth middleware can tell them apart from access tokens.
+const APIKeyPrefix = "mccs_"
+
+type apiKey struct{}
+
+var APIKey = &apiKey{}
+
+func (a *apiKey) hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// POST /user/entities/{entityID}/api-keys
+
+// Create returns the stored key and the key itself, which is not kept.
+func (a *apiKey) Create(key *types.APIKey) (*types.APIKey, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := APIKeyPrefix + hex.EncodeToString(b)
+	key.Prefix = plain[:len(APIKeyPrefix)+8]
+	key.KeyHash = a.hash(plain)
+	created, err := mongo.APIKey.Create(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return created, plain, nil
+}
+
+// GET /user/entities/{entityID}/api-keys
+
+func (a *apiKey) FindByEntityID(entityID primitive.ObjectID) ([]*types.APIKey, error) {
+	return mongo.APIKey.FindByEntityID(entityID)
+}
+
+// DELETE /user/entities/{entityID}/api-keys/{keyID}
+
+func (a *apiKey) FindByStringID(id string) (*types.APIKey, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("API key not found.")
+	}
+	return mongo.APIKey.FindByID(objectID)
+}
+
+func (a *apiKey) Revoke(id primitive.ObjectID) (*types.APIKey, error) {
+	return mongo.APIKey.Revoke(id)
+}
+
+// Authenticate finds the active key and checks the IP address against its allow-list.
+func (a *apiKey) Authenticate(key string, ipAddress string) (*types.APIKey, error) {
+	found, err := mongo.APIKey.FindActiveByHash(a.hash(key))
+	if err != nil {
+		return nil, err
+	}
+	if !a.isAllowedIP(found, ipAddress) {
+		return nil, errors.New("API key is not allowed from this IP address.")
+	}
+	return found, nil
+}
+
+// isAllowedIP accepts every address when the allow-list is empty.
+// The entries are either IP addresses or CIDR ranges.
+func (a *apiKey) isAllowedIP(key *types.APIKey, ipAddress string) bool {
+	if len(key.AllowedIPs) == 0 {
+		return true
+	}
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for _, allowed := range key.AllowedIPs {
+		if strings.Contains(allowed, "/") {
+			_, network, err := net.ParseCIDR(allowed)
+			if err == nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if ip.Equal(net.ParseIP(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope checks whether the key was given the scope.
+func (a *apiKey) HasScope(key *types.APIKey, scope string) bool {
+	return util.ContainString(key.Scopes, scope)
+}
+
+// RecordUse keeps the last use of the key and logs it at most once per usage_log_interval.
+func (a *apiKey) RecordUse(key *types.APIKey, ipAddress string) {
+	before, err := mongo.APIKey.UpdateLastUsed(key.ID, ipAddress)
+	if err != nil {
+		l.Logger.Error("[Error] APIKey.RecordUse failed:", zap.Error(err))
+		return
+	}
+	interval := viper.GetDuration("api_key.usage_log_interval") * time.Second
+	if before.LastUsedIP == ipAddress && time.Since(before.LastUsedAt) < interval {
+		return
+	}
+	UserAction.UseAPIKey(key, ipAddress)
+}
@@ -0,0 +1,120 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type application struct{}
+
+var Application = &application{}
+
+// POST /signup
+
+// Create puts the membership application of a new entity in the review queue.
+func (a *application) Create(entity *types.Entity) {
+	_, err := mongo.Application.Create(entity.ID)
+	if err != nil {
+		l.Logger.Error("logic.Application.Create failed", zap.Error(err))
+	}
+}
+
+func (a *application) FindByID(id primitive.ObjectID) (*types.Application, error) {
+	application, err := mongo.Application.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+func (a *application) FindByEntityID(entityID primitive.ObjectID) (*types.Application, error) {
+	application, err := mongo.Application.FindByEntityID(entityID)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// GET /admin/applications
+
+func (a *application) Search(req *types.AdminSearchApplicationReq) (*types.SearchApplicationResult, error) {
+	result, err := mongo.Application.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PATCH /admin/applications/{applicationID}/reviewer
+
+func (a *application) AssignReviewer(id primitive.ObjectID, reviewer *types.AdminUser) (*types.Application, error) {
+	application, err := mongo.Application.AssignReviewer(id, reviewer)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// POST /admin/applications/{applicationID}/notes
+
+func (a *application) AddNote(id primitive.ObjectID, note *types.ApplicationNote) (*types.Application, error) {
+	application, err := mongo.Application.AddNote(id, note)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// POST /admin/applications/{applicationID}/info-requests
+
+func (a *application) RequestInfo(id primitive.ObjectID, infoRequest *types.ApplicationInfoRequest) (*types.Application, error) {
+	application, err := mongo.Application.RequestInfo(id, infoRequest)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// POST /user/entities/{entityID}/application/resubmit
+
+func (a *application) Resubmit(entityID primitive.ObjectID, response string) (*types.Application, error) {
+	application, err := mongo.Application.Resubmit(entityID, response)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// Close records the decision when a pending entity is accepted or rejected, whether or not it was made from the queue.
+func (a *application) Close(entity *types.Entity, from string, to string, reason string, decidedBy string) {
+	if from != constant.Entity.Pending {
+		return
+	}
+	status := constant.Application.Approved
+	if to == constant.Entity.Rejected {
+		status = constant.Application.Rejected
+	}
+	err := mongo.Application.Close(entity.ID, status, reason, decidedBy)
+	if err != nil {
+		l.Logger.Error("logic.Application.Close failed", zap.Error(err))
+	}
+}
+
+// Backfill opens applications for the entities that were pending before the review queue existed.
+func (a *application) Backfill() (int, error) {
+	entities, err := mongo.Entity.FindByStatus(constant.Entity.Pending)
+	if err != nil {
+		return 0, err
+	}
+	for _, entity := range entities {
+		_, err := mongo.Application.Create(entity.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(entities), nil
+}
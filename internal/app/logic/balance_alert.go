@@ -0,0 +1,120 @@
+package logic
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	mail "github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.uber.org/zap"
+)
+
+type balanceAlert struct{}
+
+var BalanceAlert = &balanceAlert{}
+
+// Check evaluates the balance alerts of both entities after a journal has been completed.
+func (b *balanceAlert) Check(j *types.Journal) {
+	b.check(j.FromAccountNumber, j.Amount)
+	b.check(j.ToAccountNumber, -j.Amount)
+}
+
+// check only alerts when the journal made the balance cross a threshold, so entities
+// are not notified again on every transfer while they stay below it.
+func (b *balanceAlert) check(accountNumber string, amount float64) {
+	entity, err := Entity.FindByAccountNumber(accountNumber)
+	if err != nil {
+		l.Logger.Error("logic.BalanceAlert.Check failed", zap.Error(err))
+		return
+	}
+	if entity.BalanceAlerts == nil {
+		return
+	}
+	account, err := pg.Account.FindByAccountNumber(accountNumber)
+	if err != nil {
+		l.Logger.Error("logic.BalanceAlert.Check failed", zap.Error(err))
+		return
+	}
+	limit, err := pg.BalanceLimit.FindByAccountNumber(accountNumber)
+	if err != nil {
+		l.Logger.Error("logic.BalanceAlert.Check failed", zap.Error(err))
+		return
+	}
+
+	balance := account.Balance
+	previous := account.Balance + amount
+	alerts := entity.BalanceAlerts
+
+	if alerts.LowBalance != nil && balance < *alerts.LowBalance && previous >= *alerts.LowBalance {
+		b.notify(entity, constant.Notification.LowBalance, balance, *alerts.LowBalance)
+	}
+
+	maxNegBal := math.Abs(limit.MaxNegBal)
+	if alerts.MaxNegBalProximity != nil && maxNegBal > 0 {
+		threshold := maxNegBal * *alerts.MaxNegBalProximity / 100
+		if balance+maxNegBal <= threshold && previous+maxNegBal > threshold {
+			b.notify(entity, constant.Notification.MaxNegBalProximity, balance, maxNegBal)
+		}
+	}
+
+	if alerts.MaxPosBalProximity != nil && limit.MaxPosBal > 0 {
+		threshold := limit.MaxPosBal * *alerts.MaxPosBalProximity / 100
+		if limit.MaxPosBal-balance <= threshold && limit.MaxPosBal-previous > threshold {
+			b.notify(entity, constant.Notification.MaxPosBalProximity, balance, limit.MaxPosBal)
+		}
+	}
+}
+
+func (b *balanceAlert) notify(entity *types.Entity, notificationType string, balance float64, threshold float64) {
+	input := &mail.BalanceAlertEmail{
+		EntityName: entity.Name,
+		Email:      entity.Email,
+		Balance:    balance,
+		Threshold:  threshold,
+	}
+
+	var message string
+	switch notificationType {
+	case constant.Notification.LowBalance:
+		message = "Your balance of " + fmt.Sprintf("%.2f", balance) + " is below your low balance alert of " + fmt.Sprintf("%.2f", threshold) + "."
+		mail.Balance.SendLowBalanceEmail(input)
+	case constant.Notification.MaxNegBalProximity:
+		message = "Your balance of " + fmt.Sprintf("%.2f", balance) + " is close to your maximum negative balance of -" + fmt.Sprintf("%.2f", threshold) + "."
+		mail.Balance.SendMaxNegBalProximityEmail(input)
+	case constant.Notification.MaxPosBalProximity:
+		message = "Your balance of " + fmt.Sprintf("%.2f", balance) + " is close to your maximum positive balance of " + fmt.Sprintf("%.2f", threshold) + "."
+		mail.Balance.SendMaxPosBalProximityEmail(input)
+	}
+
+	_, err := mongo.Notification.Create(&types.Notification{
+		EntityID: entity.ID,
+		Type:     notificationType,
+		Message:  message,
+	})
+	if err != nil {
+		l.Logger.Error("logic.BalanceAlert.notify failed", zap.Error(err))
+	}
+}
+
+// WriteOff notifies the entity that its negative balance has been written off.
+func (b *balanceAlert) WriteOff(req *types.AdminWriteOffReq) {
+	mail.Balance.SendWriteOffEmail(&mail.WriteOffEmail{
+		EntityName: req.Entity.Name,
+		Email:      req.Entity.Email,
+		Amount:     req.Amount,
+		Reason:     req.Reason,
+	})
+
+	_, err := mongo.Notification.Create(&types.Notification{
+		EntityID: req.Entity.ID,
+		Type:     constant.Notification.WriteOff,
+		Message:  "Your negative balance of -" + fmt.Sprintf("%.2f", req.Amount) + " has been written off. Reason: " + req.Reason,
+	})
+	if err != nil {
+		l.Logger.Error("logic.BalanceAlert.WriteOff failed", zap.Error(err))
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/spf13/viper"
 )
 
 type balanceLimit struct{}
@@ -46,3 +47,27 @@ func (b balanceLimit) GetMaxNegBalance(accountNumber string) (float64, error) {
 	}
 	return math.Abs(balanceLimitRecord.MaxNegBal), nil
 }
+
+// GetVelocityLimits returns the velocity limits of the account, falling back to the network defaults
+// for the ones which are not set (NULL). A zero value means there is no limit.
+func (b balanceLimit) GetVelocityLimits(accountNumber string) (maxDailyAmount float64, maxMonthlyAmount float64, maxDailyCount int, err error) {
+	balanceLimitRecord, err := pg.BalanceLimit.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	maxDailyAmount = viper.GetFloat64("transaction.max_daily_out_amount")
+	if balanceLimitRecord.MaxDailyOutAmount != nil {
+		maxDailyAmount = *balanceLimitRecord.MaxDailyOutAmount
+	}
+	maxMonthlyAmount = viper.GetFloat64("transaction.max_monthly_out_amount")
+	if balanceLimitRecord.MaxMonthlyOutAmount != nil {
+		maxMonthlyAmount = *balanceLimitRecord.MaxMonthlyOutAmount
+	}
+	maxDailyCount = viper.GetInt("transaction.max_daily_out_count")
+	if balanceLimitRecord.MaxDailyOutCount != nil {
+		maxDailyCount = *balanceLimitRecord.MaxDailyOutCount
+	}
+
+	return maxDailyAmount, maxMonthlyAmount, maxDailyCount, nil
+}
@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/storage"
+	"github.com/spf13/viper"
+)
+
+type dataExport struct{}
+
+var DataExport = &dataExport{}
+
+// GET /user/export
+// GET /admin/users/{userID}/export
+
+// timeout is how long an export can stay pending, generation is considered interrupted after it.
+func (d *dataExport) timeout() time.Duration {
+	timeout := time.Duration(viper.GetInt64("data_export.timeout")) * time.Second
+	if timeout <= 0 {
+		return time.Hour
+	}
+	return timeout
+}
+
+// Create starts a new export for the user unless one is already being generated.
+// The returned bool reports whether the export was newly created.
+func (d *dataExport) Create(export *types.DataExport) (*types.DataExport, bool, error) {
+	pending, err := mongo.DataExport.FindPending(export.UserID, time.Now().Add(-d.timeout()))
+	if err == nil {
+		return pending, false, nil
+	}
+	created, err := mongo.DataExport.Create(export)
+	if err != nil {
+		return nil, false, err
+	}
+	return created, true, nil
+}
+
+func (d *dataExport) SetReady(export *types.DataExport, key string, size int) error {
+	return mongo.DataExport.SetReady(export.ID, key, size)
+}
+
+func (d *dataExport) SetFailed(export *types.DataExport) error {
+	return mongo.DataExport.SetStatus(export.ID, constant.DataExport.Failed)
+}
+
+// GET /exports/{token}
+
+func (d *dataExport) FindByToken(token string) (*types.DataExport, error) {
+	export, err := mongo.DataExport.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (d *dataExport) IsExpired(export *types.DataExport) bool {
+	if export.Status == constant.DataExport.Expired {
+		return true
+	}
+	return export.Status == constant.DataExport.Ready &&
+		time.Now().Sub(export.CompletedAt).Seconds() >= viper.GetFloat64("data_export.link_timeout")
+}
+
+func (d *dataExport) Download(export *types.DataExport) ([]byte, error) {
+	return storage.Private.Get(export.Key)
+}
+
+// DeleteExpired removes the archives whose download link has expired.
+func (d *dataExport) DeleteExpired() (int, error) {
+	before := time.Now().Add(-time.Duration(viper.GetInt64("data_export.link_timeout")) * time.Second)
+	exports, err := mongo.DataExport.FindExpired(before)
+	if err != nil {
+		return 0, err
+	}
+	for _, export := range exports {
+		err := storage.Private.Delete(export.Key)
+		if err != nil {
+			return 0, err
+		}
+		err = mongo.DataExport.SetStatus(export.ID, constant.DataExport.Expired)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(exports), nil
+}
+
+// FailStale marks the exports whose generation was interrupted, e.g. by a restart, as failed.
+func (d *dataExport) FailStale() (int64, error) {
+	return mongo.DataExport.FailStale(time.Now().Add(-d.timeout()))
+}
@@ -0,0 +1,273 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/logic"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/storage"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
+)
+
+// Run generates the ZIP archive of the personal data held about the user and emails the download link
+// to whoever requested it.
+func Run(export *types.DataExport, user *types.User, receiver string) {
+	data, err := build(user)
+	if err != nil {
+		fail(export, err)
+		return
+	}
+
+	key := "export-" + ksuid.New().String() + ".zip"
+	err = storage.Private.Put(key, data)
+	if err != nil {
+		fail(export, err)
+		return
+	}
+	err = logic.DataExport.SetReady(export, key, len(data))
+	if err != nil {
+		storage.Private.Delete(key)
+		fail(export, err)
+		return
+	}
+
+	email.DataExport.Ready(&email.DataExportReadyEmail{
+		Receiver:      receiver,
+		ReceiverEmail: export.RequestedBy,
+		UserEmail:     user.Email,
+		Token:         export.Token,
+	})
+}
+
+// DeleteExpired removes the archives whose download link has expired and fails the exports
+// which have been pending for too long.
+func DeleteExpired() {
+	failed, err := logic.DataExport.FailStale()
+	if err != nil {
+		l.Logger.Error("failing stale data exports failed", zap.Error(err))
+	} else if failed > 0 {
+		l.Logger.Info("failed stale data exports", zap.Int64("count", failed))
+	}
+
+	n, err := logic.DataExport.DeleteExpired()
+	if err != nil {
+		l.Logger.Error("deleting expired data exports failed", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		l.Logger.Info("deleted expired data exports", zap.Int("count", n))
+	}
+}
+
+func fail(export *types.DataExport, err error) {
+	l.Logger.Error("generating data export failed", zap.String("userID", export.UserID.Hex()), zap.Error(err))
+	err = logic.DataExport.SetFailed(export)
+	if err != nil {
+		l.Logger.Error("marking data export as failed failed", zap.Error(err))
+	}
+}
+
+func build(user *types.User) ([]byte, error) {
+	entities, err := mongo.Entity.FindByIDs(user.Entities)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+
+	err = writeJSON(w, "profile.json", profile(user))
+	if err != nil {
+		return nil, err
+	}
+	err = writeJSON(w, "entities.json", entities)
+	if err != nil {
+		return nil, err
+	}
+	favorites, err := favorites(entities)
+	if err != nil {
+		return nil, err
+	}
+	err = writeJSON(w, "favorites.json", favorites)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := transfers(entities)
+	if err != nil {
+		return nil, err
+	}
+	err = writeCSV(w, "transfers.csv", transfers)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := mongo.UserAction.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = writeCSV(w, "login_history.csv", loginHistory(actions))
+	if err != nil {
+		return nil, err
+	}
+	err = writeCSV(w, "user_actions.csv", userActions(actions))
+	if err != nil {
+		return nil, err
+	}
+	err = writeJSON(w, "password_resets.json", passwordResets(user))
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func profile(user *types.User) *types.User {
+	p := *user
+	p.Password = ""
+	p.PasswordHistory = nil
+	if p.TwoFactor != nil {
+		p.TwoFactor = &types.TwoFactor{EnabledAt: p.TwoFactor.EnabledAt}
+	}
+	return &p
+}
+
+type favorite struct {
+	EntityID         string `json:"entityID"`
+	EntityName       string `json:"entityName"`
+	FavoriteEntityID string `json:"favoriteEntityID"`
+	FavoriteName     string `json:"favoriteName"`
+}
+
+func favorites(entities []*types.Entity) ([]*favorite, error) {
+	result := []*favorite{}
+	for _, entity := range entities {
+		if len(entity.FavoriteEntities) == 0 {
+			continue
+		}
+		favoriteEntities, err := mongo.Entity.FindByIDs(entity.FavoriteEntities)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range favoriteEntities {
+			result = append(result, &favorite{
+				EntityID:         entity.ID.Hex(),
+				EntityName:       entity.Name,
+				FavoriteEntityID: f.ID.Hex(),
+				FavoriteName:     f.Name,
+			})
+		}
+	}
+	return result, nil
+}
+
+func transfers(entities []*types.Entity) ([][]string, error) {
+	records := [][]string{{
+		"transferID", "createdAt", "completedAt", "type", "status",
+		"fromAccountNumber", "fromEntityName", "toAccountNumber", "toEntityName",
+		"amount", "description", "cancellationReason",
+	}}
+	// A transfer between two entities of the user is listed once.
+	seen := map[string]bool{}
+	for _, entity := range entities {
+		if entity.AccountNumber == "" {
+			continue
+		}
+		journals, err := pg.Journal.FindByAccountNumber(entity.AccountNumber)
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range journals {
+			if seen[j.TransferID] {
+				continue
+			}
+			seen[j.TransferID] = true
+			records = append(records, []string{
+				j.TransferID, formatTime(j.CreatedAt), formatTime(j.CompletedAt), j.Type, j.Status,
+				j.FromAccountNumber, j.FromEntityName, j.ToAccountNumber, j.ToEntityName,
+				strconv.FormatFloat(j.Amount, 'f', 2, 64), j.Description, j.CancellationReason,
+			})
+		}
+	}
+	return records, nil
+}
+
+func loginHistory(actions []*types.UserAction) [][]string {
+	records := [][]string{{"createdAt", "action", "detail"}}
+	for _, a := range actions {
+		if a.Action != "user login successful" && a.Action != "user login failed" {
+			continue
+		}
+		records = append(records, []string{formatTime(a.CreatedAt), a.Action, a.Detail})
+	}
+	return records
+}
+
+func userActions(actions []*types.UserAction) [][]string {
+	records := [][]string{{"createdAt", "category", "action", "detail"}}
+	for _, a := range actions {
+		records = append(records, []string{formatTime(a.CreatedAt), a.Category, a.Action, a.Detail})
+	}
+	return records
+}
+
+type passwordReset struct {
+	CreatedAt time.Time `json:"createdAt"`
+	TokenUsed bool      `json:"tokenUsed"`
+}
+
+// passwordResets leaves out the reset token which could still be valid.
+func passwordResets(user *types.User) []*passwordReset {
+	result := []*passwordReset{}
+	lostPassword, err := mongo.LostPassword.FindByEmail(user.Email)
+	if err == nil {
+		result = append(result, &passwordReset{
+			CreatedAt: lostPassword.CreatedAt,
+			TokenUsed: lostPassword.TokenUsed,
+		})
+	}
+	return result
+}
+
+func writeJSON(w *zip.Writer, name string, v interface{}) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeCSV(w *zip.Writer, name string, records [][]string) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	err = cw.WriteAll(records)
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,161 @@
+package logic
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	mail "github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type emailVerification struct{}
+
+var EmailVerification = &emailVerification{}
+
+func (e *emailVerification) ExpiresAt(verification *types.EmailVerification) time.Time {
+	return verification.CreatedAt.Add(time.Duration(viper.GetInt("email_verification_timeout")) * time.Second)
+}
+
+func (e *emailVerification) create(verification *types.EmailVerification) (*types.EmailVerification, error) {
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	verification.Token = uid.String()
+	return mongo.EmailVerification.Create(verification)
+}
+
+// POST /signup
+// POST /user/email-verification
+// POST /admin/users/{userID}/email-verification
+
+// SendToUser sends a new verification token to the email address of the user.
+func (e *emailVerification) SendToUser(user *types.User, signup bool) (*types.EmailVerification, error) {
+	if !user.EmailUnverified {
+		return nil, errors.New("The email address is already verified.")
+	}
+	created, err := e.create(&types.EmailVerification{
+		UserID: user.ID,
+		Email:  user.Email,
+		Signup: signup,
+	})
+	if err != nil {
+		return nil, err
+	}
+	go mail.EmailVerification.Verify(&mail.EmailVerificationEmail{
+		Receiver:      user.FirstName + " " + user.LastName,
+		ReceiverEmail: user.Email,
+		Token:         created.Token,
+	})
+	return created, nil
+}
+
+// POST /signup
+// POST /user/entities/{entityID}/email-verification
+// POST /admin/entities/{entityID}/email-verification
+
+// SendToEntity sends a new verification token to the email address of the entity.
+func (e *emailVerification) SendToEntity(entity *types.Entity) (*types.EmailVerification, error) {
+	if !entity.EmailUnverified {
+		return nil, errors.New("The email address is already verified.")
+	}
+	if entity.Email == "" {
+		return nil, errors.New("The entity does not have an email address.")
+	}
+	created, err := e.create(&types.EmailVerification{
+		EntityID: entity.ID,
+		Email:    entity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	go mail.EmailVerification.Verify(&mail.EmailVerificationEmail{
+		Receiver:      entity.Name,
+		ReceiverEmail: entity.Email,
+		EntityName:    entity.Name,
+		Token:         created.Token,
+	})
+	return created, nil
+}
+
+// PATCH /admin/users/{userID}
+
+// UserEmailChanged asks the user to confirm the new email address.
+func (e *emailVerification) UserEmailChanged(user *types.User) (*types.EmailVerification, error) {
+	err := mongo.User.SetEmailUnverified(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.EmailUnverified = true
+	return e.SendToUser(user, false)
+}
+
+// PATCH /user/entities/{entityID}
+// PATCH /admin/entities/{entityID}
+
+// EntityEmailChanged asks the entity to confirm the new email address.
+func (e *emailVerification) EntityEmailChanged(entity *types.Entity) (*types.EmailVerification, error) {
+	err := mongo.Entity.SetEmailUnverified(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	entity.EmailUnverified = true
+	if entity.Email == "" {
+		return nil, nil
+	}
+	return e.SendToEntity(entity)
+}
+
+// POST /email-verification/{token}
+
+// Verify confirms the email address of the token. Confirming the email address of a user also
+// confirms the entities of the user which use the same address.
+func (e *emailVerification) Verify(token string) (*types.EmailVerification, error) {
+	verification, err := mongo.EmailVerification.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !verification.VerifiedAt.IsZero() || time.Now().After(e.ExpiresAt(verification)) {
+		return nil, errors.New("Invalid token.")
+	}
+
+	if !verification.UserID.IsZero() {
+		user, err := mongo.User.SetEmailVerified(verification.UserID, verification.Email)
+		if err != nil {
+			return nil, err
+		}
+		if len(user.Entities) != 0 {
+			_, err = mongo.Entity.SetEmailVerified(user.Entities, verification.Email)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		count, err := mongo.Entity.SetEmailVerified([]primitive.ObjectID{verification.EntityID}, verification.Email)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, errors.New("The email address has changed since the token was sent.")
+		}
+	}
+
+	err = mongo.EmailVerification.SetVerified(verification.ID)
+	if err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+// IsUserVerified checks whether the user can use the features which need a confirmed email address.
+func (e *emailVerification) IsUserVerified(userID string) bool {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return false
+	}
+	return !user.EmailUnverified
+}
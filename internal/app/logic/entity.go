@@ -4,10 +4,13 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -21,6 +24,9 @@ func (_ *entity) Create(entity *types.Entity) (*types.Entity, error) {
 		return nil, err
 	}
 	entity.AccountNumber = account.AccountNumber
+	if entity.Location == nil {
+		entity.Location = Geo.Locate(entity.PostalCode, entity.City, entity.Country)
+	}
 	created, err := mongo.Entity.Create(entity)
 	if err != nil {
 		return nil, err
@@ -42,6 +48,104 @@ func (_ *entity) AssociateUser(entityID, userID primitive.ObjectID) error {
 	return nil
 }
 
+// HasRole checks whether the user is a member of the entity with at least the given role.
+func (_ *entity) HasRole(entity *types.Entity, userID string, role string) bool {
+	memberRole := entity.MemberRole(util.ToObjectID(userID))
+	if memberRole == "" {
+		return false
+	}
+	return constant.EntityRoleRank[memberRole] >= constant.EntityRoleRank[role]
+}
+
+// CanAcceptTransfer checks whether the member is allowed to accept a transfer of the given amount.
+// Staff can only accept transfers up to the configured limit.
+func (e *entity) CanAcceptTransfer(entity *types.Entity, userID string, amount float64) bool {
+	if e.HasRole(entity, userID, constant.EntityRole.Bookkeeper) {
+		return true
+	}
+	if e.HasRole(entity, userID, constant.EntityRole.Staff) {
+		return !isAmountExceeded(amount, viper.GetFloat64("membership.staff_accept_limit"))
+	}
+	return false
+}
+
+// GET /user/entities/{entityID}/members
+
+func (_ *entity) FindMembers(entity *types.Entity) ([]*types.User, error) {
+	users, err := mongo.User.FindByIDs(entity.Users)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// POST /invitations/{token}/accept
+
+func (_ *entity) AddMember(entityID primitive.ObjectID, userID primitive.ObjectID, role string) error {
+	err := mongo.Entity.AddMember(entityID, userID, role)
+	if err != nil {
+		return err
+	}
+	err = mongo.User.AssociateEntity([]primitive.ObjectID{userID}, entityID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func (e *entity) UpdateMemberRole(req *types.UpdateEntityMemberReq) (*types.Entity, error) {
+	if req.Entity.MemberRole(req.UserID) == "" {
+		return nil, errors.New("Member not found.")
+	}
+	if req.Role != constant.EntityRole.Owner && e.isLastOwner(req.Entity, req.UserID) {
+		return nil, errors.New("An entity must have at least one owner.")
+	}
+	updated, err := mongo.Entity.UpdateMemberRole(req.Entity.ID, req.UserID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+func (e *entity) RemoveMember(req *types.EntityMemberReq) error {
+	if req.Entity.MemberRole(req.UserID) == "" {
+		return errors.New("Member not found.")
+	}
+	if e.isLastOwner(req.Entity, req.UserID) {
+		return errors.New("An entity must have at least one owner.")
+	}
+	err := mongo.Entity.RemoveMember(req.Entity.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (_ *entity) isLastOwner(entity *types.Entity, userID primitive.ObjectID) bool {
+	if entity.MemberRole(userID) != constant.EntityRole.Owner {
+		return false
+	}
+	for _, m := range entity.Members {
+		if m.Role == constant.EntityRole.Owner && m.UserID != userID {
+			return false
+		}
+	}
+	return true
+}
+
+// BackfillMembers gives the owner role to the users associated with an entity before entity roles existed.
+func (_ *entity) BackfillMembers() (int, error) {
+	count, err := mongo.Entity.BackfillMembers()
+	if err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
 func (_ *entity) FindByID(objectID primitive.ObjectID) (*types.Entity, error) {
 	entity, err := mongo.Entity.FindByID(objectID)
 	if err != nil {
@@ -86,6 +190,9 @@ func (_ *entity) EmailExists(email string) bool {
 // PATCH /user/entities/{entityID}
 
 func (_ *entity) FindOneAndUpdate(req *types.UpdateUserEntityReq) (*types.Entity, error) {
+	if req.Location == nil {
+		req.Location = Geo.Relocate(req.OriginEntity, req.PostalCode, req.City, req.Country)
+	}
 	err := es.Entity.Update(req)
 	if err != nil {
 		return nil, err
@@ -100,6 +207,9 @@ func (_ *entity) FindOneAndUpdate(req *types.UpdateUserEntityReq) (*types.Entity
 // PATCH /admin/entities/{entityID}
 
 func (_ *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.Entity, error) {
+	if req.Location == nil {
+		req.Location = Geo.Relocate(req.OriginEntity, req.PostalCode, req.City, req.Country)
+	}
 	err := es.Entity.AdminUpdate(req)
 	if err != nil {
 		return nil, err
@@ -138,10 +248,16 @@ func (_ *entity) AdminFindOneAndDelete(id primitive.ObjectID) (*types.Entity, er
 	if err != nil {
 		return nil, err
 	}
+	err = mongo.APIKey.RevokeByEntityID(id)
+	if err != nil {
+		return nil, err
+	}
 	err = pg.Account.Delete(deleted.AccountNumber)
 	if err != nil {
 		return nil, err
 	}
+	// The images are unset on the deleted entity so use the ones found before.
+	EntityImage.DeleteFiles(entity.AllImages()...)
 	return deleted, nil
 }
 
@@ -158,6 +274,7 @@ func (_ *entity) Search(req *types.SearchEntityReq) (*types.SearchEntityResult,
 		Entities:        entities,
 		NumberOfResults: result.NumberOfResults,
 		TotalPages:      result.TotalPages,
+		Distances:       result.Distances,
 	}, nil
 }
 
@@ -0,0 +1,144 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/storage"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/ic3network/mccs-alpha-api/util/thumbnail"
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type entityImage struct{}
+
+var EntityImage = &entityImage{}
+
+// PUT /user/entities/{entityID}/logo
+
+// SetLogo replaces the logo of the entity and removes the files of the previous one.
+func (e *entityImage) SetLogo(entity *types.Entity, data []byte) (*types.Entity, error) {
+	logo, err := e.store(entity, data)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := mongo.Entity.SetLogo(entity.ID, logo)
+	if err != nil {
+		e.DeleteFiles(logo)
+		return nil, err
+	}
+	err = es.Entity.UpdateImages(updated)
+	if err != nil {
+		return nil, err
+	}
+	if entity.Logo != nil {
+		e.DeleteFiles(entity.Logo)
+	}
+	return updated, nil
+}
+
+// DELETE /user/entities/{entityID}/logo
+
+func (e *entityImage) RemoveLogo(entity *types.Entity) (*types.Entity, error) {
+	updated, err := mongo.Entity.UnsetLogo(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Entity.UpdateImages(updated)
+	if err != nil {
+		return nil, err
+	}
+	if entity.Logo != nil {
+		e.DeleteFiles(entity.Logo)
+	}
+	return updated, nil
+}
+
+// POST /user/entities/{entityID}/images
+
+func (e *entityImage) AddImage(entity *types.Entity, data []byte) (*types.Entity, error) {
+	image, err := e.store(entity, data)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := mongo.Entity.AddImage(entity.ID, image, viper.GetInt("images.max_gallery"))
+	if err != nil {
+		e.DeleteFiles(image)
+		return nil, err
+	}
+	err = es.Entity.UpdateImages(updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DELETE /user/entities/{entityID}/images/{imageID}
+
+func (e *entityImage) RemoveImage(entity *types.Entity, image *types.EntityImage) (*types.Entity, error) {
+	updated, err := mongo.Entity.RemoveImage(entity.ID, image.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Entity.UpdateImages(updated)
+	if err != nil {
+		return nil, err
+	}
+	e.DeleteFiles(image)
+	return updated, nil
+}
+
+// store validates the uploaded image, then saves it together with a resized thumbnail.
+func (e *entityImage) store(entity *types.Entity, data []byte) (*types.EntityImage, error) {
+	img, contentType, err := thumbnail.Decode(data, viper.GetInt("images.max_pixels"))
+	if err != nil {
+		return nil, err
+	}
+	thumb, thumbContentType, err := thumbnail.Encode(thumbnail.Resize(img, viper.GetInt("images.thumbnail_size")), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	id := ksuid.New().String()
+	image := &types.EntityImage{
+		ID:           id,
+		Key:          entity.ID.Hex() + "-" + id + thumbnail.ContentTypes[contentType],
+		ThumbnailKey: entity.ID.Hex() + "-" + id + "-thumb" + thumbnail.ContentTypes[thumbContentType],
+		CreatedAt:    time.Now(),
+	}
+	image.URL = storage.Files.URL(image.Key)
+	image.ThumbnailURL = storage.Files.URL(image.ThumbnailKey)
+
+	err = storage.Files.Put(image.Key, data)
+	if err != nil {
+		return nil, err
+	}
+	err = storage.Files.Put(image.ThumbnailKey, thumb)
+	if err != nil {
+		e.DeleteFiles(image)
+		return nil, err
+	}
+	return image, nil
+}
+
+// DeleteFiles removes the stored files of the images, failures are only logged since the records are already gone.
+func (e *entityImage) DeleteFiles(images ...*types.EntityImage) {
+	for _, image := range images {
+		for _, key := range []string{image.Key, image.ThumbnailKey} {
+			err := storage.Files.Delete(key)
+			if err != nil {
+				l.Logger.Error("logic.EntityImage.DeleteFiles failed", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+}
+
+// GET /images/{key}
+
+func (e *entityImage) Get(key string) ([]byte, error) {
+	return storage.Files.Get(key)
+}
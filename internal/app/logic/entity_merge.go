@@ -0,0 +1,159 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type entityMerge struct{}
+
+var EntityMerge = &entityMerge{}
+
+// POST /admin/entities/{entityID}/merge
+
+// Merge moves everything of req.Entity into req.IntoEntity and closes the account of req.Entity.
+func (m *entityMerge) Merge(req *types.AdminMergeEntityReq) (*types.MergeEntityResult, error) {
+	journal, pending, err := pg.Journal.Merge(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ledger has been committed and cannot be undone, a failure from here on is logged with
+	// what is needed to finish the merge by hand. The search indexes can be rebuilt by es-restore.
+	updated, err := mongo.Entity.Merge(req.Entity, m.combine(req.Entity, req.IntoEntity))
+	if err != nil {
+		m.logIncomplete("mongo.Entity.Merge", req, journal, err)
+		return nil, err
+	}
+	err = mongo.APIKey.RevokeByEntityID(req.Entity.ID)
+	if err != nil {
+		m.logIncomplete("mongo.APIKey.RevokeByEntityID", req, journal, err)
+		return nil, err
+	}
+
+	if journal != nil {
+		err = es.Journal.Create(journal)
+		if err != nil {
+			m.logIncomplete("es.Journal.Create", req, journal, err)
+			return nil, err
+		}
+	}
+	for _, j := range pending {
+		err = es.Journal.UpdateAccounts(j)
+		if err != nil {
+			m.logIncomplete("es.Journal.UpdateAccounts", req, journal, err)
+			return nil, err
+		}
+	}
+	account, err := pg.Account.FindByAccountNumber(updated.AccountNumber)
+	if err != nil {
+		m.logIncomplete("pg.Account.FindByAccountNumber", req, journal, err)
+		return nil, err
+	}
+	err = es.Entity.Merge(req.Entity, updated, account.Balance)
+	if err != nil {
+		m.logIncomplete("es.Entity.Merge", req, journal, err)
+		return nil, err
+	}
+
+	return &types.MergeEntityResult{
+		Entity:           updated,
+		Journal:          journal,
+		PendingTransfers: pending,
+	}, nil
+}
+
+// logIncomplete records a merge whose ledger part has been committed but a later step failed.
+func (m *entityMerge) logIncomplete(step string, req *types.AdminMergeEntityReq, journal *types.Journal, err error) {
+	transferID := ""
+	if journal != nil {
+		transferID = journal.TransferID
+	}
+	l.Logger.Error("[Error] EntityMerge.Merge left incomplete, the ledger has been merged:",
+		zap.String("step", step),
+		zap.String("entityID", req.Entity.ID.Hex()),
+		zap.String("accountNumber", req.Entity.AccountNumber),
+		zap.String("intoEntityID", req.IntoEntity.ID.Hex()),
+		zap.String("intoAccountNumber", req.IntoEntity.AccountNumber),
+		zap.Float64("balance", req.Balance),
+		zap.String("transferID", transferID),
+		zap.Error(err),
+	)
+}
+
+// combine returns the kept entity with the users, tags, categories and favorites of the merged one added.
+// The kept entity wins where both have a value, e.g. the role of a user member of both.
+func (m *entityMerge) combine(merged *types.Entity, into *types.Entity) *types.Entity {
+	combined := *into
+
+	combined.Users = append([]primitive.ObjectID{}, into.Users...)
+	for _, id := range merged.Users {
+		if !util.ContainID(combined.Users, id.Hex()) {
+			combined.Users = append(combined.Users, id)
+		}
+	}
+	combined.Members = append([]*types.EntityMember{}, into.Members...)
+	for _, member := range merged.Members {
+		if into.MemberRole(member.UserID) == "" {
+			combined.Members = append(combined.Members, member)
+		}
+	}
+
+	combined.Offers = mergeTagFields(into.Offers, merged.Offers)
+	combined.Wants = mergeTagFields(into.Wants, merged.Wants)
+
+	combined.Categories = append([]string{}, into.Categories...)
+	for _, category := range merged.Categories {
+		if !containsString(combined.Categories, category) {
+			combined.Categories = append(combined.Categories, category)
+		}
+	}
+
+	combined.FavoriteEntities = []primitive.ObjectID{}
+	for _, id := range append(into.FavoriteEntities, merged.FavoriteEntities...) {
+		if id != into.ID && id != merged.ID && !util.ContainID(combined.FavoriteEntities, id.Hex()) {
+			combined.FavoriteEntities = append(combined.FavoriteEntities, id)
+		}
+	}
+
+	combined.AccountAliases = append([]string{}, into.AccountAliases...)
+	for _, accountNumber := range append([]string{merged.AccountNumber}, merged.AccountAliases...) {
+		if !containsString(combined.AccountAliases, accountNumber) {
+			combined.AccountAliases = append(combined.AccountAliases, accountNumber)
+		}
+	}
+
+	return &combined
+}
+
+func mergeTagFields(into []*types.TagField, merged []*types.TagField) []*types.TagField {
+	result := append([]*types.TagField{}, into...)
+	for _, tag := range merged {
+		found := false
+		for _, t := range result {
+			if t.Name == tag.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
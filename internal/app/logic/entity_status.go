@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	mail "github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type entityStatus struct{}
+
+var EntityStatus = &entityStatus{}
+
+// PATCH /admin/entities/{entityID}
+
+// CheckTransition checks if the entity is allowed to move to the new status.
+func (s *entityStatus) CheckTransition(entity *types.Entity, to string, reason string) error {
+	if !util.IsValidTransition(entity.Status, to) {
+		return errors.New("The entity status cannot be changed from " + entity.Status + " to " + to + ".")
+	}
+	if util.IsRejectedStatus(to) && strings.TrimSpace(reason) == "" {
+		return errors.New("Please specify a reason for the rejection.")
+	}
+	if to == constant.Trading.Pending || to == constant.Trading.Accepted {
+		if len(entity.Users) == 0 {
+			return errors.New("The entity must have at least one user before it can trade.")
+		}
+	}
+	if entity.Status == constant.Trading.Accepted {
+		pending, err := pg.Journal.GetPending(entity.AccountNumber)
+		if err != nil {
+			return err
+		}
+		if len(pending) != 0 {
+			return errors.New("The entity has pending transfers which must be completed or cancelled first.")
+		}
+	}
+	return nil
+}
+
+// Record adds the status change to the history of the entity, then notifies the entity.
+func (s *entityStatus) Record(entity *types.Entity, from string, to string, reason string, changedBy string) {
+	_, err := mongo.EntityStatusChange.Create(&types.EntityStatusChange{
+		EntityID:  entity.ID,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		ChangedBy: changedBy,
+	})
+	if err != nil {
+		l.Logger.Error("logic.EntityStatus.Record failed", zap.Error(err))
+	}
+	if from == "" {
+		return
+	}
+	Application.Close(entity, from, to, reason, changedBy)
+	mail.EntityStatus.Send(&mail.EntityStatusEmail{
+		EntityName: entity.Name,
+		Email:      entity.Email,
+		Status:     to,
+		Reason:     reason,
+	})
+}
+
+// GET /admin/entities/{entityID}/status-history
+
+func (s *entityStatus) FindHistory(entityID primitive.ObjectID) ([]*types.EntityStatusChange, error) {
+	changes, err := mongo.EntityStatusChange.FindByEntityID(entityID)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,272 @@
+package logic
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type erasure struct{}
+
+var Erasure = &erasure{}
+
+// Pseudonyms replace the personal data of erased users and entities. They are derived from identifiers
+// which carry no personal data so the ledger still reconciles and admins can tell the records apart.
+
+func (e *erasure) userPseudonym(user *types.User) *types.User {
+	return &types.User{
+		FirstName: "Erased",
+		LastName:  "User",
+		Email:     "erased-" + user.ID.Hex() + "@erased.invalid",
+	}
+}
+
+func (e *erasure) entityPseudonym(entity *types.Entity) string {
+	if entity.AccountNumber == "" {
+		return "Erased entity " + entity.ID.Hex()
+	}
+	return "Erased entity " + entity.AccountNumber
+}
+
+// POST /admin/users/{userID}/erasure
+
+// EraseUser anonymises the user everywhere it is stored. The entities the user is the only member of
+// are erased as well, so the erasure is refused while any of them cannot be erased.
+func (e *erasure) EraseUser(id primitive.ObjectID) (*types.User, []*types.Entity, error) {
+	user, err := mongo.User.FindErasable(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entities, err := mongo.Entity.FindByIDs(user.Entities)
+	if err != nil {
+		return nil, nil, err
+	}
+	soleEntities := []*types.Entity{}
+	for _, entity := range entities {
+		if len(entity.Users) == 1 && entity.Users[0] == user.ID {
+			soleEntities = append(soleEntities, entity)
+		}
+	}
+	for _, entity := range soleEntities {
+		err := e.checkEntity(entity)
+		if err != nil {
+			return nil, nil, errors.New(entity.Name + ": " + err.Error())
+		}
+	}
+
+	erasedEntities := []*types.Entity{}
+	for _, entity := range soleEntities {
+		erased, err := e.eraseEntity(entity)
+		if err != nil {
+			return nil, nil, err
+		}
+		erasedEntities = append(erasedEntities, erased)
+	}
+
+	pseudonym := e.userPseudonym(user)
+	err = e.anonymizeActions(user.ID, map[string]string{
+		user.Email:                           pseudonym.Email,
+		user.FirstName + " " + user.LastName: pseudonym.FirstName + " " + pseudonym.LastName,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = es.User.Erase(user.ID.Hex())
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.LostPassword.DeleteByEmail(user.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.EmailVerification.DeleteByUserID(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.Invitation.DeleteByEmail(user.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.Invitation.RemoveInvitedBy(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.OwnershipTransfer.DeleteByEmail(user.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.OwnershipTransfer.RenameNominatedBy(user.Email, pseudonym.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+	exports, err := mongo.DataExport.DeleteByUserID(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, export := range exports {
+		if export.Key != "" {
+			storage.Private.Delete(export.Key)
+		}
+	}
+
+	err = RefreshToken.RevokeAll(user.ID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mongo.User.RemoveFromEntities(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	erased, err := mongo.User.Erase(user.ID, pseudonym)
+	if err != nil {
+		return nil, nil, err
+	}
+	return erased, erasedEntities, nil
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func (e *erasure) EraseEntity(id primitive.ObjectID) (*types.Entity, error) {
+	entity, err := mongo.Entity.FindErasable(id)
+	if err != nil {
+		return nil, err
+	}
+	err = e.checkEntity(entity)
+	if err != nil {
+		return nil, err
+	}
+	return e.eraseEntity(entity)
+}
+
+// checkEntity refuses the erasure while the entity still takes part in the ledger.
+func (e *erasure) checkEntity(entity *types.Entity) error {
+	if entity.AccountNumber == "" {
+		return nil
+	}
+	// The account of a deleted entity is already closed with a zero balance.
+	if entity.DeletedAt.IsZero() {
+		zeroBalance, err := Account.IsZeroBalance(entity.AccountNumber)
+		if err != nil {
+			return err
+		}
+		if !zeroBalance {
+			return errors.New("Cannot erase an entity with a non-zero balance.")
+		}
+	}
+	pending, err := pg.Journal.GetPending(entity.AccountNumber)
+	if err != nil {
+		return err
+	}
+	if len(pending) != 0 {
+		return errors.New("Cannot erase an entity with pending transfers.")
+	}
+	return nil
+}
+
+// eraseEntity marks the entity as erased last so a failed erasure can be retried.
+func (e *erasure) eraseEntity(entity *types.Entity) (*types.Entity, error) {
+	pseudonym := e.entityPseudonym(entity)
+
+	if entity.AccountNumber != "" {
+		err := pg.Journal.RenameEntity(entity.AccountNumber, pseudonym)
+		if err != nil {
+			return nil, err
+		}
+		if entity.DeletedAt.IsZero() {
+			err = pg.Account.Delete(entity.AccountNumber)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := es.Entity.Erase(entity.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.PaymentRequest.RenameEntity(entity.ID, pseudonym)
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.Invitation.DeleteByEntityID(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.OwnershipTransfer.DeleteByEntityID(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.APIKey.RevokeByEntityID(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.EmailVerification.DeleteByEntityID(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = e.anonymizeActions(primitive.NilObjectID, map[string]string{
+		entity.Email: "erased-" + entity.ID.Hex() + "@erased.invalid",
+		entity.Name:  pseudonym,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = mongo.Entity.Erase(entity.ID, pseudonym)
+	if err != nil {
+		return nil, err
+	}
+	EntityImage.DeleteFiles(entity.AllImages()...)
+
+	erased := *entity
+	erased.Name = pseudonym
+	return &erased, nil
+}
+
+// anonymizeActions replaces the personal data in the logged actions of the user and in the ones mentioning it.
+// Terms shorter than three characters are skipped since they would match unrelated text.
+func (e *erasure) anonymizeActions(userID primitive.ObjectID, replacements map[string]string) error {
+	terms := []string{}
+	oldnew := []string{}
+	for old, new := range replacements {
+		if len(strings.TrimSpace(old)) < 3 {
+			continue
+		}
+		terms = append(terms, old)
+		oldnew = append(oldnew, old, new)
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	actions, err := mongo.UserAction.FindMentioning(userID, terms)
+	if err != nil {
+		return err
+	}
+	for _, a := range actions {
+		email, detail := replacer.Replace(a.Email), replacer.Replace(a.Detail)
+		// The detail of a login only holds the email and the IP address of the user.
+		if !userID.IsZero() && a.UserID == userID && strings.HasPrefix(a.Action, "user login") {
+			detail = email
+		}
+		if email == a.Email && detail == a.Detail {
+			continue
+		}
+		a.Email, a.Detail = email, detail
+		err := mongo.UserAction.Anonymize(a)
+		if err != nil {
+			return err
+		}
+		err = es.UserAction.Anonymize(a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,8 +2,17 @@ package logic
 
 import (
 	"errors"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
 )
 
 var (
-	ErrLoginLocked = errors.New("Your account has been temporarily locked for 15 minutes. Please try again later.")
+	ErrLoginLocked    = errors.New("Your account has been temporarily locked for 15 minutes. Please try again later.")
+	ErrPasswordReused = errors.New("You cannot reuse one of your recent passwords.")
+	ErrLastSuperAdmin = errors.New("There must be at least one admin with the superadmin role.")
+	// ErrNothingToWriteOff and ErrPendingTransfers are checked while the account is locked.
+	ErrNothingToWriteOff = pg.ErrNothingToWriteOff
+	ErrPendingTransfers  = pg.ErrPendingTransfers
+	// ErrMergeExceedsLimit is checked while both accounts of the merge are locked.
+	ErrMergeExceedsLimit = pg.ErrMergeExceedsLimit
 )
@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/internal/pkg/geocode"
+)
+
+type geo struct{}
+
+var Geo = &geo{}
+
+// Locate resolves the coordinates of the address, nil when it cannot be located.
+func (g *geo) Locate(postalCode string, city string, country string) *types.GeoLocation {
+	lat, lon, err := geocode.Default.Geocode(&geocode.Address{
+		PostalCode: postalCode,
+		City:       city,
+		Country:    country,
+	})
+	if err != nil {
+		return nil
+	}
+	return &types.GeoLocation{Lat: lat, Lon: lon}
+}
+
+// Resolve returns the stored coordinates of the entity or resolves them from its address.
+func (g *geo) Resolve(entity *types.Entity) *types.GeoLocation {
+	if entity.Location != nil {
+		return entity.Location
+	}
+	return g.Locate(entity.PostalCode, entity.City, entity.Country)
+}
+
+// Relocate resolves new coordinates when the address of the entity changes.
+// It returns nil to keep the current coordinates, which is always the case for manual ones.
+func (g *geo) Relocate(origin *types.Entity, postalCode string, city string, country string) *types.GeoLocation {
+	if origin.Location != nil && origin.Location.Manual {
+		return nil
+	}
+	changed := (postalCode != "" && postalCode != origin.PostalCode) ||
+		(city != "" && city != origin.City) ||
+		(country != "" && country != origin.Country)
+	if !changed && origin.Location != nil {
+		return nil
+	}
+	return g.Locate(
+		firstNonEmpty(postalCode, origin.PostalCode),
+		firstNonEmpty(city, origin.City),
+		firstNonEmpty(country, origin.Country),
+	)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/jwt"
+	"github.com/spf13/viper"
+)
+
+type impersonation struct{}
+
+var Impersonation = &impersonation{}
+
+func (i *impersonation) timeout() time.Duration {
+	timeout := viper.GetDuration("impersonation_timeout") * time.Second
+	if timeout <= 0 {
+		return 30 * time.Minute
+	}
+	return timeout
+}
+
+// Start issues a token which lets the admin act as the user until it expires. The token cannot be refreshed
+// and is read-only unless write is set, it can be revoked like any other session of the user.
+func (i *impersonation) Start(admin *types.AdminUser, user *types.User, write bool, device *types.Device) (string, *types.RefreshToken, error) {
+	// Nobody knows the refresh token of the session so it cannot be refreshed.
+	_, hash, err := RefreshToken.newToken()
+	if err != nil {
+		return "", nil, err
+	}
+	session, err := mongo.RefreshToken.Create(&types.RefreshToken{
+		UserID:             user.ID,
+		TokenHash:          hash,
+		ExpiresAt:          time.Now().Add(i.timeout()),
+		Device:             *device,
+		LastSeenAt:         time.Now(),
+		LastSeenIP:         device.IP,
+		ImpersonatedBy:     admin.ID,
+		ImpersonationWrite: write,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	token, err := jwt.NewJWTManager().GenerateImpersonation(user.ID.Hex(), session.ID.Hex(), admin.ID.Hex(), write, session.ExpiresAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, session, nil
+}
+
+// IsPermitted checks that the admin who started the impersonation still exists and may impersonate users,
+// the roles of the admin or their permissions can change while the token is valid.
+func (i *impersonation) IsPermitted(adminID string, write bool) bool {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return false
+	}
+	permissions, err := AdminRole.Permissions(admin.Roles)
+	if err != nil {
+		return false
+	}
+	if !util.ContainString(permissions, constant.AdminPermission.UsersImpersonate) {
+		return false
+	}
+	return !write || util.ContainString(permissions, constant.AdminPermission.UsersWrite)
+}
@@ -0,0 +1,89 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type invitation struct{}
+
+var Invitation = &invitation{}
+
+// POST /user/entities/{entityID}/invitations
+
+func (i *invitation) Create(inv *types.Invitation) (*types.Invitation, error) {
+	created, err := mongo.Invitation.Create(inv)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (i *invitation) FindByToken(token string) (*types.Invitation, error) {
+	inv, err := mongo.Invitation.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+func (i *invitation) FindByID(id primitive.ObjectID) (*types.Invitation, error) {
+	inv, err := mongo.Invitation.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// GET /user/entities/{entityID}/invitations
+
+func (i *invitation) FindPending(entityID primitive.ObjectID) ([]*types.Invitation, error) {
+	since := time.Now().Add(-time.Duration(viper.GetInt("invitation_timeout")) * time.Second)
+	invitations, err := mongo.Invitation.FindPending(entityID, since)
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// POST /invitations/{token}/accept
+
+// Claim makes sure concurrent requests cannot accept the same invitation twice.
+func (i *invitation) Claim(token string) error {
+	return mongo.Invitation.Claim(token)
+}
+
+// Unclaim lets the invitation be accepted again when linking the user failed.
+func (i *invitation) Unclaim(token string) {
+	err := mongo.Invitation.Unclaim(token)
+	if err != nil {
+		l.Logger.Error("[Error] Invitation.Unclaim failed:", zap.Error(err))
+	}
+}
+
+// DELETE /user/entities/{entityID}/invitations/{invitationID}
+
+func (i *invitation) Revoke(id primitive.ObjectID) error {
+	err := mongo.Invitation.Revoke(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *invitation) ExpiresAt(inv *types.Invitation) time.Time {
+	return inv.CreatedAt.Add(time.Duration(viper.GetInt("invitation_timeout")) * time.Second)
+}
+
+func (i *invitation) IsTokenInvalid(inv *types.Invitation) bool {
+	if time.Now().After(i.ExpiresAt(inv)) || inv.TokenUsed || inv.Revoked {
+		return true
+	}
+	return false
+}
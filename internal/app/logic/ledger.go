@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type ledger struct{}
+
+var Ledger = &ledger{}
+
+// POST /admin/ledger/accounts
+
+func (l *ledger) CreateAccount(req *types.AdminCreateLedgerAccountReq) (*types.LedgerAccount, error) {
+	created, err := pg.Ledger.CreateAccount(req)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GET /admin/ledger/accounts
+
+func (l *ledger) FindAccounts() ([]*types.LedgerAccount, error) {
+	accounts, err := pg.Ledger.FindAccounts()
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// POST /admin/ledger/journals
+
+func (l *ledger) CreateJournal(req *types.AdminCreateLedgerJournalReq) (*types.LedgerJournal, error) {
+	for _, p := range req.Postings {
+		_, err := pg.Ledger.FindAccountByCode(p.AccountCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	created, err := pg.Ledger.CreateJournal(req)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GET /admin/ledger/trial-balance
+
+func (l *ledger) TrialBalance(req *types.AdminTrialBalanceReq) (*types.TrialBalanceRespond, error) {
+	balances, err := pg.Ledger.TrialBalance(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTrialBalanceRespond(req.Date, balances), nil
+}
+
+// GET /admin/ledger/income-statement
+
+func (l *ledger) IncomeStatement(req *types.AdminIncomeStatementReq) (*types.IncomeStatementRespond, error) {
+	balances, err := pg.Ledger.IncomeStatement(req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewIncomeStatementRespond(req.DateFrom, req.DateTo, balances), nil
+}
@@ -0,0 +1,20 @@
+package logic
+
+import (
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+)
+
+type notification struct{}
+
+var Notification = &notification{}
+
+// GET /notifications
+
+func (n *notification) Search(req *types.SearchNotificationReq) (*types.SearchNotificationResult, error) {
+	result, err := mongo.Notification.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
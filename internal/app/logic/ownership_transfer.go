@@ -0,0 +1,160 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type ownershipTransfer struct{}
+
+var OwnershipTransfer = &ownershipTransfer{}
+
+// POST /user/entities/{entityID}/ownership-transfer
+
+func (o *ownershipTransfer) Create(transfer *types.OwnershipTransfer) (*types.OwnershipTransfer, error) {
+	created, err := mongo.OwnershipTransfer.Create(transfer)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (o *ownershipTransfer) FindByToken(token string) (*types.OwnershipTransfer, error) {
+	transfer, err := mongo.OwnershipTransfer.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// GET /user/entities/{entityID}/ownership-transfer
+
+func (o *ownershipTransfer) FindPending(entityID primitive.ObjectID) (*types.OwnershipTransfer, error) {
+	since := time.Now().Add(-time.Duration(viper.GetInt("ownership_transfer_timeout")) * time.Second)
+	transfer, err := mongo.OwnershipTransfer.FindPending(entityID, since)
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// DELETE /user/entities/{entityID}/ownership-transfer
+
+func (o *ownershipTransfer) Revoke(id primitive.ObjectID) error {
+	err := mongo.OwnershipTransfer.Revoke(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *ownershipTransfer) ExpiresAt(transfer *types.OwnershipTransfer) time.Time {
+	return transfer.CreatedAt.Add(time.Duration(viper.GetInt("ownership_transfer_timeout")) * time.Second)
+}
+
+func (o *ownershipTransfer) IsTokenInvalid(transfer *types.OwnershipTransfer) bool {
+	if time.Now().After(o.ExpiresAt(transfer)) || transfer.TokenUsed || transfer.Revoked {
+		return true
+	}
+	return false
+}
+
+// POST /ownership-transfers/{token}/accept
+
+// Complete makes the user the owner of the entity. Mongo cannot update the entity and the users in one
+// transaction so every step undoes the previous ones when it fails, and the claimed token makes sure
+// a transfer is only completed once.
+func (o *ownershipTransfer) Complete(transfer *types.OwnershipTransfer, entity *types.Entity, newOwner *types.User) (*types.Entity, error) {
+	err := mongo.OwnershipTransfer.Claim(transfer.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	users, members, previousOwners := o.newMembers(entity, newOwner.ID, transfer.PreviousOwnersRole)
+	email := ""
+	if transfer.UpdateEntityEmail {
+		email = newOwner.Email
+	}
+
+	updated, err := mongo.Entity.SetMembers(entity.ID, entity.Members, users, members, email)
+	if err != nil {
+		o.rollback(transfer, entity, nil, false)
+		return nil, err
+	}
+	if email != "" {
+		err = es.Entity.UpdateEmail(entity.ID, email)
+		if err != nil {
+			o.rollback(transfer, entity, updated, false)
+			return nil, err
+		}
+	}
+	removed := []primitive.ObjectID{}
+	if transfer.PreviousOwnersRole == "" {
+		removed = previousOwners
+	}
+	err = mongo.User.MoveEntity(entity.ID, newOwner.ID, removed)
+	if err != nil {
+		o.rollback(transfer, entity, updated, email != "")
+		return nil, err
+	}
+
+	UserAction.AcceptOwnershipTransfer(newOwner, transfer, previousOwners)
+
+	return updated, nil
+}
+
+// newMembers returns the users and members of the entity once the user is its owner, along with the
+// previous owners who are either removed or given the role.
+func (o *ownershipTransfer) newMembers(entity *types.Entity, newOwnerID primitive.ObjectID, role string) ([]primitive.ObjectID, []*types.EntityMember, []primitive.ObjectID) {
+	members := []*types.EntityMember{}
+	previousOwners := []primitive.ObjectID{}
+	for _, m := range entity.Members {
+		if m.UserID == newOwnerID {
+			continue
+		}
+		if m.Role != constant.EntityRole.Owner {
+			members = append(members, m)
+			continue
+		}
+		previousOwners = append(previousOwners, m.UserID)
+		if role != "" {
+			members = append(members, &types.EntityMember{UserID: m.UserID, Role: role})
+		}
+	}
+	members = append(members, &types.EntityMember{UserID: newOwnerID, Role: constant.EntityRole.Owner})
+
+	users := []primitive.ObjectID{}
+	for _, m := range members {
+		users = append(users, m.UserID)
+	}
+	return users, members, previousOwners
+}
+
+// rollback restores the entity as it was before the transfer and releases the token. The members are
+// only restored while they are still the ones the transfer set, updated is nil when nothing was changed.
+func (o *ownershipTransfer) rollback(transfer *types.OwnershipTransfer, entity *types.Entity, updated *types.Entity, restoreEmail bool) {
+	if updated != nil {
+		_, err := mongo.Entity.SetMembers(entity.ID, updated.Members, entity.Users, entity.Members, entity.Email)
+		if err != nil {
+			l.Logger.Error("[Error] OwnershipTransfer.rollback failed:", zap.Error(err))
+		}
+	}
+	if restoreEmail {
+		err := es.Entity.UpdateEmail(entity.ID, entity.Email)
+		if err != nil {
+			l.Logger.Error("[Error] OwnershipTransfer.rollback failed:", zap.Error(err))
+		}
+	}
+	err := mongo.OwnershipTransfer.Unclaim(transfer.Token)
+	if err != nil {
+		l.Logger.Error("[Error] OwnershipTransfer.rollback failed:", zap.Error(err))
+	}
+}
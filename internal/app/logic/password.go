@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/bcrypt"
+	"github.com/spf13/viper"
+)
+
+type password struct{}
+
+var Password = &password{}
+
+// Hash hashes the new password and returns it together with the updated password history.
+// The current password is moved into the history which keeps the last `validate.password.history` hashes.
+func (p *password) Hash(newPassword string, current string, history []string) (string, []string, error) {
+	for _, hashed := range append([]string{current}, history...) {
+		if hashed != "" && bcrypt.CompareHash(hashed, newPassword) == nil {
+			return "", nil, ErrPasswordReused
+		}
+	}
+
+	hashedPassword, err := bcrypt.Hash(newPassword)
+	if err != nil {
+		return "", nil, err
+	}
+
+	size := viper.GetInt("validate.password.history")
+	if current != "" && size > 0 {
+		history = append([]string{current}, history...)
+	}
+	if len(history) > size {
+		history = history[:size]
+	}
+
+	return hashedPassword, history, nil
+}
+
+// AdminExpired returns whether the admin has to change the password before doing anything else.
+// Admins who never changed their password are measured from the time the account was created.
+func (p *password) AdminExpired(admin *types.AdminUser) bool {
+	maxAge := viper.GetInt("validate.password.adminMaxAge")
+	if maxAge <= 0 {
+		return false
+	}
+	changedAt := admin.PasswordChangedAt
+	if changedAt.IsZero() {
+		changedAt = admin.CreatedAt
+	}
+	return time.Since(changedAt) > time.Duration(maxAge)*24*time.Hour
+}
@@ -0,0 +1,113 @@
+package logic
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/jwt"
+	"github.com/ic3network/mccs-alpha-api/util/qrcode"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type paymentRequest struct{}
+
+var PaymentRequest = &paymentRequest{}
+
+// POST /payment-requests
+
+func (p *paymentRequest) Create(req *types.PaymentRequestReq) (*types.PaymentRequest, error) {
+	created, err := mongo.PaymentRequest.Create(&types.PaymentRequest{
+		EntityID:      req.PayeeEntity.ID,
+		AccountNumber: req.PayeeEntity.AccountNumber,
+		EntityName:    req.PayeeEntity.Name,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		SingleUse:     req.SingleUse,
+		ExpiresAt:     time.Now().Add(time.Duration(req.ExpiresIn) * time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.NewJWTManager().GeneratePaymentRequest(
+		created.ID.Hex(),
+		created.AccountNumber,
+		created.Amount,
+		created.Description,
+		created.SingleUse,
+		created.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	created.Token = token
+
+	return created, nil
+}
+
+// GET /payment-requests/{token}
+
+func (p *paymentRequest) FindByToken(token string) (*types.PaymentRequest, error) {
+	claims, err := jwt.NewJWTManager().ValidatePaymentRequest(token)
+	if err != nil {
+		return nil, errors.New("The payment request is invalid or has expired.")
+	}
+	id, err := primitive.ObjectIDFromHex(claims.ID)
+	if err != nil {
+		return nil, errors.New("The payment request is invalid or has expired.")
+	}
+
+	found, err := mongo.PaymentRequest.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// The signed claims are the source of truth for what the payer is asked to pay.
+	if found.AccountNumber != claims.AccountNumber || found.Amount != claims.Amount {
+		return nil, errors.New("The payment request is invalid or has expired.")
+	}
+	if found.SingleUse && !found.UsedAt.IsZero() {
+		return nil, errors.New("The payment request has already been used.")
+	}
+	found.Token = token
+
+	return found, nil
+}
+
+// Link returns the payment link encoded in the QR code.
+func (p *paymentRequest) Link(paymentRequest *types.PaymentRequest) string {
+	return viper.GetString("url") + "/payment-requests/" + paymentRequest.Token
+}
+
+func (p *paymentRequest) QRCode(paymentRequest *types.PaymentRequest, format string, size int) ([]byte, error) {
+	if format == "svg" {
+		return qrcode.SVG(p.Link(paymentRequest), size)
+	}
+	return qrcode.PNG(p.Link(paymentRequest), size)
+}
+
+// POST /payment-requests/{token}/pay
+
+func (p *paymentRequest) SetUsed(paymentRequest *types.PaymentRequest) error {
+	if !paymentRequest.SingleUse {
+		return nil
+	}
+	err := mongo.PaymentRequest.SetUsed(paymentRequest.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *paymentRequest) UnsetUsed(paymentRequest *types.PaymentRequest) error {
+	if !paymentRequest.SingleUse {
+		return nil
+	}
+	err := mongo.PaymentRequest.UnsetUsed(paymentRequest.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package logic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/redis"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	mail "github.com/ic3network/mccs-alpha-api/internal/pkg/email"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"github.com/ic3network/mccs-alpha-api/util/jwt"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type refreshToken struct{}
+
+var RefreshToken = &refreshToken{}
+
+// Tokens is the pair of tokens returned on login and on refresh.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+func (r *refreshToken) timeout() time.Duration {
+	timeout := viper.GetDuration("jwt.refresh_token_timeout") * time.Second
+	if timeout <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return timeout
+}
+
+func (r *refreshToken) newToken() (string, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, r.hash(token), nil
+}
+
+func (r *refreshToken) hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue starts a new session for the user on the device.
+func (r *refreshToken) Issue(userID primitive.ObjectID, admin bool, device *types.Device) (*Tokens, error) {
+	token, hash, err := r.newToken()
+	if err != nil {
+		return nil, err
+	}
+	knownDevices, err := mongo.RefreshToken.FindDeviceIDs(userID, admin)
+	if err != nil {
+		return nil, err
+	}
+	created, err := mongo.RefreshToken.Create(&types.RefreshToken{
+		UserID:     userID,
+		Admin:      admin,
+		TokenHash:  hash,
+		ExpiresAt:  time.Now().Add(r.timeout()),
+		Device:     *device,
+		LastSeenAt: time.Now(),
+		LastSeenIP: device.IP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := r.generate(userID, admin, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The first recorded session of an account has nothing to be compared with.
+	if len(knownDevices) != 0 && !util.ContainString(knownDevices, device.ID) {
+		go r.notifyNewDevice(created)
+	}
+	return &Tokens{AccessToken: accessToken, RefreshToken: token}, nil
+}
+
+// notifyNewDevice lets the user know about a login from a device which has not been used before.
+func (r *refreshToken) notifyNewDevice(session *types.RefreshToken) {
+	input := &mail.NewDeviceLoginEmail{
+		Device:    session.Device.Name,
+		IPAddress: session.Device.IP,
+		LoginTime: session.CreatedAt,
+	}
+	if session.Admin {
+		admin, err := mongo.AdminUser.FindByID(session.UserID)
+		if err != nil {
+			l.Logger.Error("[Error] RefreshToken.notifyNewDevice failed:", zap.Error(err))
+			return
+		}
+		input.Receiver, input.ReceiverEmail = admin.Name, admin.Email
+	} else {
+		user, err := mongo.User.FindByID(session.UserID)
+		if err != nil {
+			l.Logger.Error("[Error] RefreshToken.notifyNewDevice failed:", zap.Error(err))
+			return
+		}
+		input.Receiver, input.ReceiverEmail = user.FirstName+" "+user.LastName, user.Email
+	}
+	mail.Session.NewDeviceLogin(input)
+}
+
+// generate issues an access token. The permissions of an admin are resolved every time
+// so changes to the roles are picked up on the next refresh.
+// An admin whose password has expired gets no permissions until the password is changed.
+func (r *refreshToken) generate(userID primitive.ObjectID, admin bool, sessionID primitive.ObjectID) (string, error) {
+	var permissions []string
+	if admin {
+		a, err := mongo.AdminUser.FindByID(userID)
+		if err != nil {
+			return "", err
+		}
+		if Password.AdminExpired(a) {
+			return jwt.NewJWTManager().GenerateForSession(userID.Hex(), admin, sessionID.Hex(), nil)
+		}
+		permissions, err = AdminRole.Permissions(a.Roles)
+		if err != nil {
+			return "", err
+		}
+	}
+	return jwt.NewJWTManager().GenerateForSession(userID.Hex(), admin, sessionID.Hex(), permissions)
+}
+
+// Refresh rotates the refresh token. A refresh token which has already been rotated means it was
+// stolen or leaked, so the whole family gets revoked and the user has to log in again.
+func (r *refreshToken) Refresh(token string, admin bool, device *types.Device) (*Tokens, error) {
+	newToken, newHash, err := r.newToken()
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := mongo.RefreshToken.Rotate(r.hash(token), newHash, device.IP)
+	if err != nil {
+		reused, findErr := mongo.RefreshToken.FindByUsedHash(r.hash(token))
+		if findErr == nil {
+			l.Logger.Warn("[Warn] RefreshToken.Refresh: refresh token reused, revoking the session.",
+				zap.String("sessionID", reused.ID.Hex()),
+				zap.String("userID", reused.UserID.Hex()))
+			err := r.Revoke(reused.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, errors.New("Refresh token is invalid.")
+	}
+	if rotated.Admin != admin {
+		return nil, errors.New("Refresh token is invalid.")
+	}
+	accessToken, err := r.generate(rotated.UserID, admin, rotated.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{AccessToken: accessToken, RefreshToken: newToken}, nil
+}
+
+// revocationTimeout is how long a revoked session is remembered, as long as the tokens issued for it live.
+func (r *refreshToken) revocationTimeout() time.Duration {
+	if Impersonation.timeout() > jwt.AccessTokenTimeout() {
+		return Impersonation.timeout()
+	}
+	return jwt.AccessTokenTimeout()
+}
+
+// Revoke ends the session. It stays in the revocation list until the access tokens issued for it expire.
+func (r *refreshToken) Revoke(sessionID primitive.ObjectID) error {
+	err := mongo.RefreshToken.Revoke(sessionID)
+	if err != nil {
+		return err
+	}
+	return redis.RevokeSession(sessionID.Hex(), r.revocationTimeout())
+}
+
+func (r *refreshToken) RevokeByStringID(sessionID string) error {
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return err
+	}
+	return r.Revoke(objID)
+}
+
+// RevokeAll ends all the sessions of the user or the admin.
+func (r *refreshToken) RevokeAll(userID primitive.ObjectID, admin bool) error {
+	_, err := r.RevokeOthers(userID, admin, primitive.NilObjectID)
+	return err
+}
+
+// RevokeOthers ends all the sessions of the user or the admin except the kept one and returns how many were ended.
+func (r *refreshToken) RevokeOthers(userID primitive.ObjectID, admin bool, keep primitive.ObjectID) (int, error) {
+	ids, err := mongo.RefreshToken.RevokeByUserID(userID, admin, keep)
+	if err != nil {
+		return 0, err
+	}
+	return r.addToRevocationList(ids)
+}
+
+// RevokeImpersonations ends the sessions the admin started to act as a user.
+func (r *refreshToken) RevokeImpersonations(adminID primitive.ObjectID) error {
+	ids, err := mongo.RefreshToken.RevokeByImpersonator(adminID)
+	if err != nil {
+		return err
+	}
+	_, err = r.addToRevocationList(ids)
+	return err
+}
+
+func (r *refreshToken) addToRevocationList(ids []primitive.ObjectID) (int, error) {
+	for _, id := range ids {
+		err := redis.RevokeSession(id.Hex(), r.revocationTimeout())
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// FindSessions returns the active sessions of the user or the admin.
+func (r *refreshToken) FindSessions(userID primitive.ObjectID, admin bool) ([]*types.RefreshToken, error) {
+	return mongo.RefreshToken.FindByUserID(userID, admin)
+}
+
+// RevokeSession ends one of the active sessions of the user or the admin.
+func (r *refreshToken) RevokeSession(userID primitive.ObjectID, admin bool, sessionID string) (*types.RefreshToken, error) {
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return nil, errors.New("Session not found.")
+	}
+	session, err := mongo.RefreshToken.FindByID(objID)
+	if err != nil || session.UserID != userID || session.Admin != admin || !session.RevokedAt.IsZero() || session.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("Session not found.")
+	}
+	err = r.Revoke(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// IsRevoked checks the revocation list and falls back to the stored session when Redis is not available.
+func (r *refreshToken) IsRevoked(sessionID string) bool {
+	revoked, err := redis.IsSessionRevoked(sessionID)
+	if err == nil {
+		return revoked
+	}
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return true
+	}
+	session, err := mongo.RefreshToken.FindByID(objID)
+	if err != nil {
+		return true
+	}
+	return !session.RevokedAt.IsZero()
+}
+
+// DeleteExpired removes the sessions which have been expired or revoked for longer than the tokens issued for them live.
+func (r *refreshToken) DeleteExpired() (int64, error) {
+	return mongo.RefreshToken.DeleteExpired(time.Now().Add(-r.revocationTimeout()))
+}
@@ -4,10 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"time"
 
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/pg"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/spf13/viper"
 )
 
 type transfer struct{}
@@ -59,9 +62,56 @@ func (t *transfer) CheckBalance(payer, payee string, amount float64) error {
 		return errors.New("Receiver will exceed its maximum balance limit." + " The maximum amount that can be received is: " + fmt.Sprintf("%.2f", amount))
 	}
 
+	return t.CheckVelocity(from.AccountNumber, amount)
+}
+
+// POST /transfers
+// PATCH /transfers/{transferID}
+
+// CheckVelocity checks the outgoing transfers of the payer against its daily and monthly velocity limits.
+// Like the balance checks above, it is not serialized against concurrent accepts, so two transfers
+// accepted at the same time can both pass and together exceed the velocity window.
+func (t *transfer) CheckVelocity(payer string, amount float64) error {
+	maxDailyAmount, maxMonthlyAmount, maxDailyCount, err := BalanceLimit.GetVelocityLimits(payer)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	if maxDailyAmount > 0 || maxDailyCount > 0 {
+		sent, count, err := pg.Journal.FindOutgoingSince(payer, startOfDay)
+		if err != nil {
+			return err
+		}
+		if maxDailyCount > 0 && count >= maxDailyCount {
+			return errors.New("Sender has reached its daily limit of " + strconv.Itoa(maxDailyCount) + " transfers." + " The number of transfers that can still be sent today is: 0")
+		}
+		if maxDailyAmount > 0 && isAmountExceeded(sent+amount, maxDailyAmount) {
+			return errors.New("Sender will exceed its daily transfer limit." + " The maximum amount that can still be sent today is: " + fmt.Sprintf("%.2f", math.Max(maxDailyAmount-sent, 0)))
+		}
+	}
+
+	if maxMonthlyAmount > 0 {
+		sent, _, err := pg.Journal.FindOutgoingSince(payer, startOfMonth)
+		if err != nil {
+			return err
+		}
+		if isAmountExceeded(sent+amount, maxMonthlyAmount) {
+			return errors.New("Sender will exceed its monthly transfer limit." + " The maximum amount that can still be sent this month is: " + fmt.Sprintf("%.2f", math.Max(maxMonthlyAmount-sent, 0)))
+		}
+	}
+
 	return nil
 }
 
+// isAmountExceeded compares amounts in cents to avoid floating point errors.
+func isAmountExceeded(amount float64, limit float64) bool {
+	return math.Round(amount*100) > math.Round(limit*100)
+}
+
 // PATCH /transfers/{transferID}
 
 func (t *transfer) FindByID(transferID string) (*types.Journal, error) {
@@ -196,6 +246,33 @@ func (t *transfer) Create(req *types.AdminTransferReq) (*types.Journal, error) {
 	return created, nil
 }
 
+// POST /admin/entities/{entityID}/write-off
+
+// FindWriteOffEntity returns the entity owning the loss/reserve account that absorbs written-off balances.
+func (t *transfer) FindWriteOffEntity() (*types.Entity, error) {
+	accountNumber := viper.GetString("write_off.account_number")
+	if accountNumber == "" {
+		return nil, errors.New("The write-off account has not been configured.")
+	}
+	return Entity.FindByAccountNumber(accountNumber)
+}
+
+func (t *transfer) WriteOff(req *types.AdminWriteOffReq) (*types.Journal, error) {
+	created, err := pg.Journal.WriteOff(req)
+	if err != nil {
+		return nil, err
+	}
+	err = es.Journal.Create(created)
+	if err != nil {
+		return nil, err
+	}
+	err = t.updateESEntityBalances(created)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
 // GET /admin/transfers
 
 func (t *transfer) AdminSearch(req *types.AdminSearchTransferReq) (*types.AdminSearchTransferRespond, error) {
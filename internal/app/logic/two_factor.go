@@ -0,0 +1,182 @@
+package logic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/repository/redis"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util/qrcode"
+	"github.com/ic3network/mccs-alpha-api/util/totp"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type twoFactor struct{}
+
+var TwoFactor = &twoFactor{}
+
+var (
+	ErrInvalidTwoFactorCode      = errors.New("Invalid code.")
+	ErrInvalidTwoFactorChallenge = errors.New("Two-factor token is invalid or has expired.")
+)
+
+// recoveryCodeAlphabet is the Crockford base32 alphabet, it has no look-alike letters and divides 256 evenly.
+const recoveryCodeAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+func (t *twoFactor) issuer() string {
+	issuer := viper.GetString("two_factor.issuer")
+	if issuer == "" {
+		return "MCCS"
+	}
+	return issuer
+}
+
+// NewSecret returns a new secret and the provisioning URI of it.
+func (t *twoFactor) NewSecret(email string) (string, string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	return secret, totp.URI(t.issuer(), email, secret), nil
+}
+
+// QRCode encodes the provisioning URI into a PNG data URI.
+func (t *twoFactor) QRCode(uri string) (string, error) {
+	png, err := qrcode.PNG(uri, 256)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
+
+// Counter returns the time step of the code if it is valid for the secret.
+func (t *twoFactor) Counter(secret string, code string) (int64, error) {
+	counter, ok := totp.Validate(secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return 0, ErrInvalidTwoFactorCode
+	}
+	return counter, nil
+}
+
+// NewRecoveryCodes returns the recovery codes shown to the user once and the hashes which are stored.
+func (t *twoFactor) NewRecoveryCodes() ([]string, []string, error) {
+	count := viper.GetInt("two_factor.recovery_codes")
+	if count <= 0 {
+		count = 10
+	}
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		b := make([]byte, 10)
+		_, err := rand.Read(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = recoveryCodeAlphabet[int(b[j])%len(recoveryCodeAlphabet)]
+		}
+		code := string(b[:5]) + "-" + string(b[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, t.HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode ignores the case and the separators the user may type.
+func (t *twoFactor) HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsTOTPCode tells the codes of the authenticator app apart from the recovery codes.
+func (t *twoFactor) IsTOTPCode(code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Verify checks the code of an authenticator app or a recovery code and marks it as used.
+func (t *twoFactor) Verify(
+	settings *types.TwoFactor,
+	code string,
+	useCounter func(counter int64) error,
+	useRecoveryCode func(hash string) error,
+) error {
+	if !settings.Enabled() {
+		return errors.New("Two-factor authentication is not enabled.")
+	}
+	if t.IsTOTPCode(code) {
+		counter, err := t.Counter(settings.Secret, code)
+		if err != nil {
+			return err
+		}
+		if useCounter(counter) != nil {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+	if useRecoveryCode(t.HashRecoveryCode(code)) != nil {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (t *twoFactor) challengeKey(token string, admin bool) string {
+	if admin {
+		return "admin:" + token
+	}
+	return "user:" + token
+}
+
+// NewChallenge is called once the password has been checked, the returned token identifies
+// the login in the second step.
+func (t *twoFactor) NewChallenge(id primitive.ObjectID, admin bool) (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	timeout := viper.GetDuration("two_factor.challenge_timeout") * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	err = redis.SetTwoFactorChallenge(t.challengeKey(token, admin), id.Hex(), timeout)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (t *twoFactor) FindChallenge(token string, admin bool) (primitive.ObjectID, error) {
+	if token == "" {
+		return primitive.NilObjectID, ErrInvalidTwoFactorChallenge
+	}
+	subject, err := redis.GetTwoFactorChallenge(t.challengeKey(token, admin))
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidTwoFactorChallenge
+	}
+	id, err := primitive.ObjectIDFromHex(subject)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidTwoFactorChallenge
+	}
+	return id, nil
+}
+
+func (t *twoFactor) DeleteChallenge(token string, admin bool) {
+	redis.DeleteTwoFactorChallenge(t.challengeKey(token, admin))
+}
@@ -2,6 +2,7 @@ package logic
 
 import (
 	"errors"
+	"time"
 
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/es"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
@@ -28,6 +29,7 @@ func (u *user) Create(user *types.User) (*types.User, error) {
 		return nil, err
 	}
 	user.Password = hashedPassword
+	user.PasswordChangedAt = time.Now()
 
 	created, err := mongo.User.Create(user)
 	if err != nil {
@@ -82,7 +84,11 @@ func (u *user) Login(email string, password string) (*types.User, error) {
 		return nil, errors.New("Invalid password.")
 	}
 
-	redis.ResetLoginAttempts(email)
+	// With two-factor authentication the counter is only reset once the code is verified,
+	// otherwise knowing the password would allow guessing the codes without a limit.
+	if !user.TwoFactor.Enabled() {
+		redis.ResetLoginAttempts(email)
+	}
 
 	return user, nil
 }
@@ -150,18 +156,21 @@ func (u *user) ResetPassword(email string, newPassword string) error {
 		return err
 	}
 
-	hashedPassword, err := bcrypt.Hash(newPassword)
+	hashedPassword, history, err := Password.Hash(newPassword, user.Password, user.PasswordHistory)
 	if err != nil {
 		return err
 	}
 
 	user.Password = hashedPassword
+	user.PasswordHistory = history
+	user.PasswordChangedAt = time.Now()
 	err = mongo.User.UpdatePassword(user)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Log out every device which knew the old password.
+	return RefreshToken.RevokeAll(user.ID, false)
 }
 
 func (u *user) FindOneAndUpdate(userID primitive.ObjectID, update *types.User) (*types.User, error) {
@@ -189,6 +198,15 @@ func (u *user) FindEntities(userID primitive.ObjectID) ([]*types.Entity, error)
 }
 
 func (u *user) AdminFindOneAndUpdate(req *types.AdminUpdateUserReq) (*types.User, error) {
+	if req.Password != "" {
+		hashedPassword, history, err := Password.Hash(req.Password, req.OriginUser.Password, req.OriginUser.PasswordHistory)
+		if err != nil {
+			return nil, err
+		}
+		req.Password = hashedPassword
+		req.PasswordHistory = history
+	}
+
 	err := es.User.AdminUpdate(req)
 	if err != nil {
 		return nil, err
@@ -197,6 +215,13 @@ func (u *user) AdminFindOneAndUpdate(req *types.AdminUpdateUserReq) (*types.User
 	if err != nil {
 		return nil, err
 	}
+	// Log out every device which knew the old password.
+	if req.Password != "" {
+		err = RefreshToken.RevokeAll(updated.ID, false)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return updated, nil
 }
 
@@ -211,6 +236,10 @@ func (u *user) AdminFindOneAndDelete(id primitive.ObjectID) (*types.User, error)
 	if err != nil {
 		return nil, err
 	}
+	err = RefreshToken.RevokeAll(id, false)
+	if err != nil {
+		return nil, err
+	}
 	return deleted, nil
 }
 
@@ -229,3 +258,89 @@ func (u *user) AdminSearchUser(req *types.AdminSearchUserReq) (*types.SearchUser
 		TotalPages:      result.TotalPages,
 	}, nil
 }
+
+// POST /user/2fa/setup
+
+// SetupTwoFactor starts the enrolment, two-factor authentication is only enabled once a code of the new secret is verified.
+func (u *user) SetupTwoFactor(user *types.User) (string, string, error) {
+	if user.TwoFactor.Enabled() {
+		return "", "", errors.New("Two-factor authentication is already enabled.")
+	}
+	secret, uri, err := TwoFactor.NewSecret(user.Email)
+	if err != nil {
+		return "", "", err
+	}
+	err = mongo.User.SetPendingTwoFactorSecret(user.ID, secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, uri, nil
+}
+
+// POST /user/2fa/enable
+
+// EnableTwoFactor returns the recovery codes, they are only stored hashed.
+func (u *user) EnableTwoFactor(user *types.User, code string) ([]string, error) {
+	if user.TwoFactor == nil || user.TwoFactor.PendingSecret == "" {
+		return nil, errors.New("Two-factor authentication setup has not been started.")
+	}
+	counter, err := TwoFactor.Counter(user.TwoFactor.PendingSecret, code)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := TwoFactor.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.User.EnableTwoFactor(user.ID, user.TwoFactor.PendingSecret, counter, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyTwoFactor checks a code of the user. It is refused while the login is locked by too many failed attempts.
+func (u *user) VerifyTwoFactor(user *types.User, code string) error {
+	attempts := redis.GetLoginAttempts(user.Email)
+	if attempts >= viper.GetInt("login_attempts.limit") {
+		return ErrLoginLocked
+	}
+
+	err := TwoFactor.Verify(
+		user.TwoFactor,
+		code,
+		func(counter int64) error { return mongo.User.UseTwoFactorCounter(user.ID, counter) },
+		func(hash string) error { return mongo.User.UseRecoveryCode(user.ID, hash) },
+	)
+	if err != nil {
+		if attempts+1 >= viper.GetInt("login_attempts.limit") {
+			return ErrLoginLocked
+		}
+		return err
+	}
+
+	redis.ResetLoginAttempts(user.Email)
+
+	return nil
+}
+
+// POST /user/2fa/recovery-codes
+
+func (u *user) RegenerateRecoveryCodes(user *types.User) ([]string, error) {
+	codes, hashes, err := TwoFactor.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = mongo.User.SetRecoveryCodes(user.ID, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// POST /user/2fa/disable
+// DELETE /admin/users/{userID}/2fa
+
+func (u *user) DisableTwoFactor(id primitive.ObjectID) error {
+	return mongo.User.DisableTwoFactor(id)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/util"
 	"github.com/ic3network/mccs-alpha-api/util/l"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
@@ -86,6 +87,48 @@ func (u *userAction) ChangePassword(user *types.User, ipAddress string) {
 	u.create(ua)
 }
 
+// POST /user/2fa/enable
+
+func (u *userAction) EnableTwoFactor(user *types.User, ipAddress string) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "enabled two-factor authentication",
+		// [email] - [IP address]
+		Detail:   user.Email + " - " + ipAddress,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /user/2fa/disable
+
+func (u *userAction) DisableTwoFactor(user *types.User, ipAddress string) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "disabled two-factor authentication",
+		// [email] - [IP address]
+		Detail:   user.Email + " - " + ipAddress,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /user/2fa/recovery-codes
+
+func (u *userAction) RegenerateRecoveryCodes(user *types.User, ipAddress string) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "regenerated two-factor recovery codes",
+		// [email] - [IP address]
+		Detail:   user.Email + " - " + ipAddress,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
 // PATCH /user
 
 func (u *userAction) ModifyUser(origin *types.User, updated *types.User) {
@@ -103,82 +146,519 @@ func (u *userAction) ModifyUser(origin *types.User, updated *types.User) {
 	u.create(ua)
 }
 
-// PATCH /user/entities/{entityID}
+// PATCH /user/entities/{entityID}
+
+func (u *userAction) ModifyEntity(userID string, origin *types.Entity, updated *types.Entity) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	modifiedFields := util.CheckFieldDiff(origin, updated)
+	if len(modifiedFields) == 0 {
+		return
+	}
+	ua := &types.UserAction{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Action:   "user modified entity details",
+		Detail:   user.Email + " - " + updated.Email + " - " + strings.Join(modifiedFields, ", "),
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// PUT /user/entities/{entityID}/logo
+
+func (u *userAction) ModifyEntityImages(userID string, entity *types.Entity, change string) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user modified entity images",
+		// [email] - [entity email] - [change]
+		Detail:   user.Email + " - " + entity.Email + " - " + change,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// GET /user/export
+
+func (u *userAction) RequestDataExport(user *types.User) {
+	ua := &types.UserAction{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Action:   "user requested a data export",
+		Detail:   user.Email,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /transfers
+
+func (u *userAction) ProposeTransfer(userID string, req *types.TransferReq) {
+	ua := &types.UserAction{
+		UserID: util.ToObjectID(userID),
+		Email:  req.FromEmail,
+		Action: "user proposed a transfer",
+		// [proposer] - [from] - [to] - [amount] - [desc]
+		Detail:   req.InitiatorEntityName + ": " + req.FromEntityName + " - " + req.FromAccountNumber + " -> " + req.ToEntityName + " - " + req.ToAccountNumber + " - " + fmt.Sprintf("%.2f", req.Amount) + " - " + req.Description,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /payment-requests
+
+func (u *userAction) CreatePaymentRequest(userID string, p *types.PaymentRequest) {
+	user, err := mongo.User.FindByID(util.ToObjectID(userID))
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user created a payment request",
+		// [email] - [payee] - [amount] - [desc]
+		Detail:   user.Email + " - " + p.EntityName + " - " + p.AccountNumber + " - " + fmt.Sprintf("%.2f", p.Amount) + " - " + p.Description,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// PATCH /transfers/{transferID}
+
+func (u *userAction) AcceptTransfer(userID string, j *types.Journal) {
+	ua := &types.UserAction{
+		UserID: util.ToObjectID(userID),
+		Email:  j.FromAccountNumber,
+		Action: "user accepted a transfer",
+		// [from] - [to] - [amount] - [desc]
+		Detail:   j.FromEntityName + " - " + j.FromAccountNumber + " -> " + j.ToEntityName + " - " + j.ToAccountNumber + " - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func (u *userAction) UpdateEntityMember(userID string, entity *types.Entity, member *types.User, role string) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user changed the role of an entity member",
+		// [email] - [entity name] - [member email] - [role]
+		Detail:   user.Email + " - " + entity.Name + " - " + member.Email + " - " + role,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+func (u *userAction) RemoveEntityMember(userID string, entity *types.Entity, member *types.User) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user removed an entity member",
+		// [email] - [entity name] - [member email]
+		Detail:   user.Email + " - " + entity.Name + " - " + member.Email,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /user/entities/{entityID}/invitations
+
+func (u *userAction) InviteEntityMember(user *types.User, inv *types.Invitation) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user invited a member to an entity",
+		// [email] - [entity name] - [invited email] - [role]
+		Detail:   user.Email + " - " + inv.EntityName + " - " + inv.Email + " - " + inv.Role,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// DELETE /user/entities/{entityID}/invitations/{invitationID}
+
+func (u *userAction) RevokeEntityInvitation(userID string, inv *types.Invitation) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user revoked an entity invitation",
+		// [email] - [entity name] - [invited email]
+		Detail:   user.Email + " - " + inv.EntityName + " - " + inv.Email,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /invitations/{token}/accept
+
+func (u *userAction) AcceptEntityInvitation(user *types.User, inv *types.Invitation) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user accepted an entity invitation",
+		// [email] - [entity name] - [role]
+		Detail:   user.Email + " - " + inv.EntityName + " - " + inv.Role,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /user/entities/{entityID}/ownership-transfer
+
+func (u *userAction) NominateEntityOwner(user *types.User, transfer *types.OwnershipTransfer) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user nominated a new entity owner",
+		// [email] - [entity name] - [nominated email]
+		Detail:   user.Email + " - " + transfer.EntityName + " - " + transfer.Email,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// DELETE /user/entities/{entityID}/ownership-transfer
+
+func (u *userAction) RevokeOwnershipTransfer(userID string, transfer *types.OwnershipTransfer) {
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user revoked an entity ownership transfer",
+		// [email] - [entity name] - [nominated email]
+		Detail:   user.Email + " - " + transfer.EntityName + " - " + transfer.Email,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /ownership-transfers/{token}/accept
+
+func (u *userAction) AcceptOwnershipTransfer(user *types.User, transfer *types.OwnershipTransfer, previousOwners []primitive.ObjectID) {
+	previousRole := transfer.PreviousOwnersRole
+	if previousRole == "" {
+		previousRole = "removed"
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user accepted an entity ownership transfer",
+		// [email] - [entity name] - [nominated by] - [previous owner ids]: [role or removed]
+		Detail:   user.Email + " - " + transfer.EntityName + " - " + transfer.NominatedBy + " - " + strings.Join(util.ToIDStrings(previousOwners), ", ") + ": " + previousRole,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /user/entities/{entityID}/application/resubmit
+
+func (u *userAction) ResubmitApplication(user *types.User, entity *types.Entity) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user resubmitted a membership application",
+		// [email] - [entity name]
+		Detail:   user.Email + " - " + entity.Name,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /email-verification/{token}
+
+func (u *userAction) VerifyEmail(verification *types.EmailVerification) {
+	if verification.UserID.IsZero() {
+		return
+	}
+	user, err := mongo.User.FindByID(verification.UserID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user verified the email address",
+		// [email]
+		Detail:   verification.Email,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /user/entities/{entityID}/api-keys
+
+func (u *userAction) CreateAPIKey(user *types.User, entity *types.Entity, key *types.APIKey) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user created an API key",
+		// [email] - [entity name] - [key prefix] - [scopes]
+		Detail:   user.Email + " - " + entity.Name + " - " + key.Prefix + " - " + strings.Join(key.Scopes, ","),
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// DELETE /user/entities/{entityID}/api-keys/{keyID}
+
+func (u *userAction) RevokeAPIKey(user *types.User, entity *types.Entity, key *types.APIKey) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user revoked an API key",
+		// [email] - [entity name] - [key prefix]
+		Detail:   user.Email + " - " + entity.Name + " - " + key.Prefix,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// DELETE /user/sessions
+// DELETE /user/sessions/{sessionID}
+
+func (u *userAction) RevokeSessions(user *types.User, sessions string, ipAddress string) {
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "user revoked sessions",
+		// [email] - [sessions] - [ip address]
+		Detail:   user.Email + " - " + sessions + " - " + ipAddress,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// UseAPIKey is logged under the member who created the key.
+func (u *userAction) UseAPIKey(key *types.APIKey, ipAddress string) {
+	user, err := mongo.User.FindByID(key.CreatedBy)
+	if err != nil {
+		return
+	}
+	entity, err := mongo.Entity.FindByID(key.EntityID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: user.ID,
+		Email:  user.Email,
+		Action: "API key used",
+		// [email] - [entity name] - [key prefix] - [IP address]
+		Detail:   user.Email + " - " + entity.Name + " - " + key.Prefix + " - " + ipAddress,
+		Category: "user",
+	}
+	u.create(ua)
+}
+
+// POST /admin/login
+
+func (u *userAction) AdminLogin(admin *types.AdminUser, ipAddress string) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin login successful",
+		// [email] - [IP address]
+		Detail:   admin.Email + " - " + ipAddress,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/login
+
+func (u *userAction) AdminLoginFail(email string, ipAddress string) {
+	admin, err := mongo.User.FindByEmail(email)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin login failed",
+		// [email] - [IP address]
+		Detail:   admin.Email + " - " + ipAddress,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/login/2fa
+
+func (u *userAction) AdminEnableTwoFactor(admin *types.AdminUser, ipAddress string) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin enabled two-factor authentication",
+		// [email] - [IP address]
+		Detail:   admin.Email + " - " + ipAddress,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/2fa/recovery-codes
+
+func (u *userAction) AdminRegenerateRecoveryCodes(admin *types.AdminUser, ipAddress string) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin regenerated two-factor recovery codes",
+		// [email] - [IP address]
+		Detail:   admin.Email + " - " + ipAddress,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// DELETE /admin/users/{userID}/2fa
+
+func (u *userAction) AdminResetUserTwoFactor(adminID string, user *types.User) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin reset the two-factor authentication of a user",
+		// [admin email] - [user email]
+		Detail:   admin.Email + " - " + user.Email,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/users/{userID}/email-verification
+// POST /admin/entities/{entityID}/email-verification
+
+func (u *userAction) AdminResendEmailVerification(adminID string, email string) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin resent an email verification",
+		// [admin email] - [email]
+		Detail:   admin.Email + " - " + email,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/admin-users
 
-func (u *userAction) ModifyEntity(userID string, origin *types.Entity, updated *types.Entity) {
-	user, err := User.FindByStringID(userID)
+func (u *userAction) AdminCreateAdminUser(adminID string, created *types.AdminUser) {
+	admin, err := AdminUser.FindByIDString(adminID)
 	if err != nil {
 		return
 	}
-	modifiedFields := util.CheckFieldDiff(origin, updated)
-	if len(modifiedFields) == 0 {
-		return
-	}
 	ua := &types.UserAction{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Action:   "user modified entity details",
-		Detail:   user.Email + " - " + updated.Email + " - " + strings.Join(modifiedFields, ", "),
-		Category: "user",
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin created an admin user",
+		// [email] - [created admin email] - [roles]
+		Detail:   admin.Email + " - " + created.Email + " - " + strings.Join(created.Roles, ", "),
+		Category: "admin",
 	}
 	u.create(ua)
 }
 
-// POST /transfers
+// PATCH /admin/admin-users/{adminID}
 
-func (u *userAction) ProposeTransfer(userID string, req *types.TransferReq) {
+func (u *userAction) AdminModifyAdminUser(adminID string, origin *types.AdminUser, updated *types.AdminUser) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
 	ua := &types.UserAction{
-		UserID: util.ToObjectID(userID),
-		Email:  req.FromEmail,
-		Action: "user proposed a transfer",
-		// [proposer] - [from] - [to] - [amount] - [desc]
-		Detail:   req.InitiatorEntityName + ": " + req.FromEntityName + " - " + req.FromAccountNumber + " -> " + req.ToEntityName + " - " + req.ToAccountNumber + " - " + fmt.Sprintf("%.2f", req.Amount) + " - " + req.Description,
-		Category: "user",
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin modified an admin user",
+		// [email] - [modified admin email] - [name] - [old roles] -> [new roles]
+		Detail:   admin.Email + " - " + updated.Email + " - " + updated.Name + " - " + strings.Join(origin.Roles, ", ") + " -> " + strings.Join(updated.Roles, ", "),
+		Category: "admin",
 	}
 	u.create(ua)
 }
 
-// PATCH /transfers/{transferID}
+// DELETE /admin/admin-users/{adminID}
 
-func (u *userAction) AcceptTransfer(userID string, j *types.Journal) {
+func (u *userAction) AdminDeleteAdminUser(adminID string, deleted *types.AdminUser) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
 	ua := &types.UserAction{
-		UserID: util.ToObjectID(userID),
-		Email:  j.FromAccountNumber,
-		Action: "user accepted a transfer",
-		// [from] - [to] - [amount] - [desc]
-		Detail:   j.FromEntityName + " - " + j.FromAccountNumber + " -> " + j.ToEntityName + " - " + j.ToAccountNumber + " - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
-		Category: "user",
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin deleted an admin user",
+		// [email] - [deleted admin email]
+		Detail:   admin.Email + " - " + deleted.Email,
+		Category: "admin",
 	}
 	u.create(ua)
 }
 
-// POST /admin/login
+// DELETE /admin/admin-users/{adminID}/2fa
 
-func (u *userAction) AdminLogin(admin *types.AdminUser, ipAddress string) {
+func (u *userAction) AdminResetAdminTwoFactor(adminID string, target *types.AdminUser) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
 	ua := &types.UserAction{
 		UserID: admin.ID,
 		Email:  admin.Email,
-		Action: "admin login successful",
-		// [email] - [IP address]
-		Detail:   admin.Email + " - " + ipAddress,
+		Action: "admin reset the two-factor authentication of an admin user",
+		// [email] - [admin email]
+		Detail:   admin.Email + " - " + target.Email,
 		Category: "admin",
 	}
 	u.create(ua)
 }
 
-// POST /admin/login
+// POST /admin/roles
+// PATCH /admin/roles/{role}
+// DELETE /admin/roles/{role}
 
-func (u *userAction) AdminLoginFail(email string, ipAddress string) {
-	admin, err := mongo.User.FindByEmail(email)
+func (u *userAction) AdminChangeRole(adminID string, action string, role *types.AdminRole) {
+	admin, err := AdminUser.FindByIDString(adminID)
 	if err != nil {
 		return
 	}
 	ua := &types.UserAction{
 		UserID: admin.ID,
 		Email:  admin.Email,
-		Action: "admin login failed",
-		// [email] - [IP address]
-		Detail:   admin.Email + " - " + ipAddress,
+		Action: "admin " + action + " an admin role",
+		// [email] - [role] - [permissions]
+		Detail:   admin.Email + " - " + role.Name + " - " + strings.Join(role.Permissions, ", "),
 		Category: "admin",
 	}
 	u.create(ua)
@@ -374,6 +854,224 @@ func (u *userAction) AdminModifyBalance(userID string, origin *types.BalanceLimi
 	u.create(ua)
 }
 
+// PATCH /admin/entities/{entityID}
+
+func (u *userAction) AdminChangeEntityStatus(admin *types.AdminUser, entity *types.Entity, from string, reason string) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin changed entity status",
+		// [email] - [entity name] - [from] -> [to] - [reason]
+		Detail:   admin.Email + " - " + entity.Name + " - " + from + " -> " + entity.Status + " - " + reason,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// PATCH /admin/applications/{applicationID}/reviewer
+
+func (u *userAction) AdminAssignApplicationReviewer(admin *types.AdminUser, entity *types.Entity, reviewer *types.AdminUser) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin assigned an application reviewer",
+		// [email] - [entity name] - [reviewer email]
+		Detail:   admin.Email + " - " + entity.Name + " - " + reviewer.Email,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/applications/{applicationID}/notes
+
+func (u *userAction) AdminAddApplicationNote(admin *types.AdminUser, entity *types.Entity) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin added an application note",
+		// [email] - [entity name]
+		Detail:   admin.Email + " - " + entity.Name,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/applications/{applicationID}/info-requests
+
+func (u *userAction) AdminRequestApplicationInfo(admin *types.AdminUser, entity *types.Entity, message string) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin requested more application information",
+		// [email] - [entity name] - [message]
+		Detail:   admin.Email + " - " + entity.Name + " - " + message,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/applications/{applicationID}/decision
+
+func (u *userAction) AdminDecideApplication(admin *types.AdminUser, entity *types.Entity, decision string, reason string) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin decided on an application",
+		// [email] - [entity name] - [decision] - [reason]
+		Detail:   admin.Email + " - " + entity.Name + " - " + decision + " - " + reason,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/entities/{entityID}/ownership-transfer
+
+func (u *userAction) AdminNominateEntityOwner(admin *types.AdminUser, transfer *types.OwnershipTransfer) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin nominated a new entity owner",
+		// [email] - [entity name] - [nominated email]
+		Detail:   admin.Email + " - " + transfer.EntityName + " - " + transfer.Email,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// DELETE /admin/entities/{entityID}/ownership-transfer
+
+func (u *userAction) AdminRevokeOwnershipTransfer(adminID string, transfer *types.OwnershipTransfer) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin revoked an entity ownership transfer",
+		// [email] - [entity name] - [nominated email]
+		Detail:   admin.Email + " - " + transfer.EntityName + " - " + transfer.Email,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// DELETE /admin/users/{userID}/sessions
+// DELETE /admin/users/{userID}/sessions/{sessionID}
+
+func (u *userAction) AdminRevokeUserSessions(adminID string, user *types.User, sessions string) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin revoked user sessions",
+		// [email] - [user email] - [sessions]
+		Detail:   admin.Email + " - " + user.Email + " - " + sessions,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/users/{userID}/impersonation
+
+func (u *userAction) AdminImpersonateUser(admin *types.AdminUser, user *types.User, write bool, reason string) {
+	access := "read-only"
+	if write {
+		access = "read-write"
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin started impersonating user",
+		// [email] - [user email] - [access] - [reason]
+		Detail:   admin.Email + " - " + user.Email + " - " + access + " - " + reason,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// ImpersonatedRequest is logged for every request an admin makes on behalf of a user.
+func (u *userAction) ImpersonatedRequest(adminID string, userID string, method string, path string, allowed bool) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
+	user, err := User.FindByStringID(userID)
+	if err != nil {
+		return
+	}
+	result := "allowed"
+	if !allowed {
+		result = "refused"
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin made a request as user",
+		// [email] - [user email] - [method] [path] - [result]
+		Detail:   admin.Email + " - " + user.Email + " - " + method + " " + path + " - " + result,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/users/{userID}/erasure
+
+func (u *userAction) AdminEraseUser(adminID string, user *types.User, entities []*types.Entity) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
+	accountNumbers := []string{}
+	for _, entity := range entities {
+		accountNumbers = append(accountNumbers, entity.AccountNumber)
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin erased user",
+		// [email] - [user id] - [erased account numbers]
+		Detail:   admin.Email + " - " + user.ID.Hex() + " - " + strings.Join(accountNumbers, ", "),
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func (u *userAction) AdminEraseEntity(adminID string, entity *types.Entity) {
+	admin, err := AdminUser.FindByIDString(adminID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin erased entity",
+		// [email] - [entity id] - [account number]
+		Detail:   admin.Email + " - " + entity.ID.Hex() + " - " + entity.AccountNumber,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// GET /admin/users/{userID}/export
+
+func (u *userAction) AdminRequestDataExport(admin *types.AdminUser, user *types.User) {
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin requested a data export",
+		// [email] - [user email]
+		Detail:   admin.Email + " - " + user.Email,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
 // DELETE /admin/entities/{entityID}
 
 func (u *userAction) AdminDeleteEntity(userID string, deleted *types.Entity) {
@@ -393,6 +1091,29 @@ func (u *userAction) AdminDeleteEntity(userID string, deleted *types.Entity) {
 	u.create(ua)
 }
 
+// POST /admin/entities/{entityID}/merge
+
+func (u *userAction) AdminMergeEntity(userID string, req *types.AdminMergeEntityReq, result *types.MergeEntityResult) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	transferID := ""
+	if result.Journal != nil {
+		transferID = result.Journal.TransferID
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin merged entities",
+		// [email] - [merged entity] ([account]) -> [kept entity] ([account]) - [balance] - [transfer id]
+		Detail: admin.Email + " - " + req.Entity.Name + " (" + req.Entity.AccountNumber + ") -> " +
+			req.IntoEntity.Name + " (" + req.IntoEntity.AccountNumber + ") - " + fmt.Sprintf("%.2f", req.Balance) + " - " + transferID,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
 func (u *userAction) create(ua *types.UserAction) {
 	created, err := mongo.UserAction.Create(ua)
 	if err != nil {
@@ -422,6 +1143,64 @@ func (u *userAction) AdminTransfer(userID string, j *types.Journal) {
 	u.create(ua)
 }
 
+// POST /admin/entities/{entityID}/write-off
+
+func (u *userAction) AdminWriteOff(userID string, j *types.Journal) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin wrote off a negative balance",
+		// admin - [entity] <- [reserve] - [amount] - [reason]
+		Detail:   admin.Email + " - " + j.ToAccountNumber + " (" + j.ToEntityName + ") <- " + j.FromAccountNumber + " (" + j.FromEntityName + ") - " + fmt.Sprintf("%.2f", j.Amount) + " - " + j.Description,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/ledger/accounts
+
+func (u *userAction) AdminCreateLedgerAccount(userID string, a *types.LedgerAccount) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin created a ledger account",
+		// [email] - [code] - [name] - [type]
+		Detail:   admin.Email + " - " + a.Code + " - " + a.Name + " - " + a.Type,
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
+// POST /admin/ledger/journals
+
+func (u *userAction) AdminCreateLedgerJournal(userID string, j *types.LedgerJournal) {
+	admin, err := AdminUser.FindByIDString(userID)
+	if err != nil {
+		return
+	}
+	postings := []string{}
+	for _, p := range j.LedgerPostings {
+		postings = append(postings, p.AccountCode+" "+fmt.Sprintf("%.2f", p.Amount))
+	}
+	ua := &types.UserAction{
+		UserID: admin.ID,
+		Email:  admin.Email,
+		Action: "admin posted a ledger journal",
+		// [email] - [entry ID] - [desc] - [postings]
+		Detail:   admin.Email + " - " + j.EntryID + " - " + j.Description + " - " + strings.Join(postings, ", "),
+		Category: "admin",
+	}
+	u.create(ua)
+}
+
 // GET /admin/log
 
 func (u *userAction) Search(req *types.AdminSearchLogReq) (*types.ESSearchUserActionResult, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/global/constant"
@@ -49,6 +50,8 @@ func (es *entity) Create(id primitive.ObjectID, entity *types.Entity) error {
 		Balance:       &balance,
 		MaxPosBal:     &maxPosBal,
 		MaxNegBal:     &maxNegBal,
+		// Geo
+		Location: types.NewESGeoPoint(entity.Location),
 	}
 	_, err := es.c.Index().
 		Index(es.index).
@@ -61,6 +64,30 @@ func (es *entity) Create(id primitive.ObjectID, entity *types.Entity) error {
 	return nil
 }
 
+// PUT /user/entities/{entityID}/logo
+
+// UpdateImages replaces the image URLs of the entity, empty values clear the previous images.
+func (es *entity) UpdateImages(entity *types.Entity) error {
+	doc := map[string]interface{}{
+		"logoURL":          "",
+		"logoThumbnailURL": "",
+		"imageURLs":        types.EntityImageURLs(entity.Images),
+	}
+	if entity.Logo != nil {
+		doc["logoURL"] = entity.Logo.URL
+		doc["logoThumbnailURL"] = entity.Logo.ThumbnailURL
+	}
+	_, err := es.c.Update().
+		Index(es.index).
+		Id(entity.ID.Hex()).
+		Doc(doc).
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // PATCH /user/entities/{entityID}
 
 func (es *entity) Update(req *types.UpdateUserEntityReq) error {
@@ -71,6 +98,8 @@ func (es *entity) Update(req *types.UpdateUserEntityReq) error {
 		City:    req.City,
 		Region:  req.Region,
 		Country: req.Country,
+		// Geo
+		Location: types.NewESGeoPoint(req.Location),
 	}
 
 	script := es.getUpateTagScript(req.AddedOffers, req.AddedWants, req.RemovedOffers, req.RemovedWants)
@@ -110,6 +139,8 @@ func (es *entity) AdminUpdate(req *types.AdminUpdateEntityReq) error {
 		// Account
 		MaxNegBal: req.MaxNegBal,
 		MaxPosBal: req.MaxPosBal,
+		// Geo
+		Location: types.NewESGeoPoint(req.Location),
 	}
 
 	script := es.getUpateTagScript(req.AddedOffers, req.AddedWants, req.RemovedOffers, req.RemovedWants)
@@ -215,16 +246,28 @@ func (es *entity) Search(req *types.SearchEntityReq) (*types.ESSearchEntityResul
 	})
 
 	from := req.PageSize * (req.Page - 1)
-	res, err := es.c.Search().
+	search := es.c.Search().
 		Index(es.index).
 		From(from).
-		Size(req.PageSize).
-		Query(q).
-		Do(context.Background())
+		Size(req.PageSize)
+
+	if req.Near != nil {
+		q.Filter(elastic.NewGeoDistanceQuery("location").
+			Lat(req.Near.Lat).
+			Lon(req.Near.Lon).
+			Distance(strconv.FormatFloat(req.Radius, 'f', -1, 64) + "km"))
+		search.SortBy(elastic.NewGeoDistanceSort("location").
+			Point(req.Near.Lat, req.Near.Lon).
+			Unit("km").
+			Asc())
+	}
+
+	res, err := search.Query(q).Do(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
+	distances := map[string]float64{}
 	for _, hit := range res.Hits.Hits {
 		var record types.EntityESRecord
 		err := json.Unmarshal(hit.Source, &record)
@@ -232,6 +275,12 @@ func (es *entity) Search(req *types.SearchEntityReq) (*types.ESSearchEntityResul
 			return nil, err
 		}
 		ids = append(ids, record.ID)
+		// The sort value of a geo distance sort is the distance in the requested unit.
+		if req.Near != nil && len(hit.Sort) != 0 {
+			if distance, ok := hit.Sort[0].(float64); ok {
+				distances[record.ID] = distance
+			}
+		}
 	}
 
 	numberOfResults := int(res.Hits.TotalHits.Value)
@@ -241,6 +290,7 @@ func (es *entity) Search(req *types.SearchEntityReq) (*types.ESSearchEntityResul
 		IDs:             ids,
 		NumberOfResults: int(numberOfResults),
 		TotalPages:      totalPages,
+		Distances:       distances,
 	}, nil
 }
 
@@ -319,7 +369,11 @@ func seachByTags(q *elastic.BoolQuery, query *byTag) {
 
 func seachByAccount(q *elastic.BoolQuery, req *byAccount) {
 	if req.AccountNumber != "" {
-		q.Must(elastic.NewMatchQuery("accountNumber", req.AccountNumber))
+		// The account numbers of merged entities still find the entity they were merged into.
+		q.Must(elastic.NewBoolQuery().
+			Should(elastic.NewMatchQuery("accountNumber", req.AccountNumber)).
+			Should(elastic.NewTermQuery("accountAliases", req.AccountNumber)).
+			MinimumNumberShouldMatch(1))
 	}
 	if req.Balance != nil {
 		q.Must(elastic.NewRangeQuery("balance").Gte(*req.Balance).Lte(*req.Balance))
@@ -557,3 +611,56 @@ func (es *entity) UpdateBalance(accountNumber string, balance float64) error {
 	}
 	return nil
 }
+
+// POST /admin/entities/{entityID}/erasure
+
+// Erase removes the entity from the index, deleted entities are already gone.
+func (es *entity) Erase(id string) error {
+	_, err := es.c.Delete().
+		Index(es.index).
+		Id(id).
+		Do(context.Background())
+	if err != nil && !elastic.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// POST /admin/entities/{entityID}/merge
+
+// Merge updates the tags, categories, balance and account aliases of the kept entity and removes the merged one.
+func (es *entity) Merge(merged *types.Entity, into *types.Entity, balance float64) error {
+	doc := map[string]interface{}{
+		"offers":         into.Offers,
+		"wants":          into.Wants,
+		"categories":     into.Categories,
+		"balance":        balance,
+		"accountAliases": into.AccountAliases,
+	}
+	_, err := es.c.Update().
+		Index(es.index).
+		Id(into.ID.Hex()).
+		Doc(doc).
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+	return es.Erase(merged.ID.Hex())
+}
+
+// POST /ownership-transfers/{token}/accept
+
+func (es *entity) UpdateEmail(id primitive.ObjectID, email string) error {
+	doc := map[string]interface{}{
+		"email": email,
+	}
+	_, err := es.c.Update().
+		Index(es.index).
+		Id(id.Hex()).
+		Doc(doc).
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+	return nil
+}
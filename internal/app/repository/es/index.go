@@ -2,6 +2,7 @@ package es
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 
 	"github.com/olivere/elastic/v7"
@@ -22,10 +23,15 @@ func checkIndex(client *elastic.Client, index string) {
 	}
 
 	if exists {
+		updateMapping(client, index)
 		return
 	}
 
-	createIndex, err := client.CreateIndex(index).BodyString(indexMappings[index]).Do(ctx)
+	createIndex(client, index)
+}
+
+func createIndex(client *elastic.Client, index string) {
+	createIndex, err := client.CreateIndex(index).BodyString(indexMappings[index]).Do(context.Background())
 	if err != nil {
 		panic(err)
 	}
@@ -36,6 +42,42 @@ func checkIndex(client *elastic.Client, index string) {
 	}
 }
 
+// updateMapping adds the fields which are missing from an index created by an earlier version.
+// A field which was already mapped differently cannot be changed, the index has to be recreated
+// and filled again by cmd/es-restore.
+func updateMapping(client *elastic.Client, index string) {
+	var body struct {
+		Mappings map[string]interface{} `json:"mappings"`
+	}
+	err := json.Unmarshal([]byte(indexMappings[index]), &body)
+	if err != nil {
+		panic(err)
+	}
+	_, err = client.PutMapping().Index(index).BodyJson(body.Mappings).Do(context.Background())
+	if err != nil {
+		log.Printf("Updating the mapping of the %s index failed, run es-restore to recreate it: %+v \n", index, err)
+	}
+}
+
+// RecreateIndexes deletes the indexes and creates them with the current mappings.
+func RecreateIndexes() error {
+	ctx := context.Background()
+	for _, index := range indexes {
+		exists, err := client.IndexExists(index).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			_, err = client.DeleteIndex(index).Do(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		createIndex(client, index)
+	}
+	return nil
+}
+
 var indexes = []string{"entities", "users", "tags", "journals", "user_actions"}
 
 // Notes:
@@ -159,6 +201,9 @@ var indexMappings = map[string]string{
 				"accountNumber": {
 					"type": "keyword"
 				},
+				"accountAliases": {
+					"type": "keyword"
+				},
 				"balance": {
 					"type" : "float"
 				},
@@ -167,6 +212,21 @@ var indexMappings = map[string]string{
 				},
 				"maxPosBal": {
 					"type" : "float"
+				},
+				"logoURL": {
+					"type": "keyword",
+					"index": false
+				},
+				"logoThumbnailURL": {
+					"type": "keyword",
+					"index": false
+				},
+				"imageURLs": {
+					"type": "keyword",
+					"index": false
+				},
+				"location": {
+					"type": "geo_point"
 				}
 			}
 		}
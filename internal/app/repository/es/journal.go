@@ -132,3 +132,23 @@ func (es *journal) seachByTime(q *elastic.BoolQuery, dateFrom time.Time, dateTo
 		q.Must(qq)
 	}
 }
+
+// POST /admin/entities/{entityID}/merge
+
+// UpdateAccounts updates a pending transfer moved to another account by a merge.
+func (es *journal) UpdateAccounts(j *types.Journal) error {
+	doc := map[string]interface{}{
+		"fromAccountNumber": j.FromAccountNumber,
+		"toAccountNumber":   j.ToAccountNumber,
+		"status":            j.Status,
+	}
+	_, err := es.c.Update().
+		Index(es.index).
+		Id(j.TransferID).
+		Doc(doc).
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+	return nil
+}
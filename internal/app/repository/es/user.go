@@ -136,3 +136,17 @@ func (es *user) AdminUpdate(req *types.AdminUpdateUserReq) error {
 	}
 	return nil
 }
+
+// POST /admin/users/{userID}/erasure
+
+// Erase removes the user from the index, deleted users are already gone.
+func (es *user) Erase(id string) error {
+	_, err := es.c.Delete().
+		Index(es.index).
+		Id(id).
+		Do(context.Background())
+	if err != nil && !elastic.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -111,3 +111,22 @@ func (es *userAction) seachByTime(q *elastic.BoolQuery, dateFrom time.Time, date
 		q.Must(qq)
 	}
 }
+
+// POST /admin/users/{userID}/erasure
+// POST /admin/entities/{entityID}/erasure
+
+func (es *userAction) Anonymize(ua *types.UserAction) error {
+	doc := map[string]interface{}{
+		"email":  ua.Email,
+		"detail": ua.Detail,
+	}
+	_, err := es.c.Update().
+		Index(es.index).
+		Id(ua.ID.Hex()).
+		Doc(doc).
+		Do(context.Background())
+	if err != nil && !elastic.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type adminRole struct {
+	c *mongo.Collection
+}
+
+var AdminRole = &adminRole{}
+
+func (a *adminRole) Register(db *mongo.Database) {
+	a.c = db.Collection("adminRoles")
+}
+
+func (a *adminRole) Create(role *types.AdminRole) (*types.AdminRole, error) {
+	filter := bson.M{"name": role.Name}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"name":        role.Name,
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"createdAt":   time.Now(),
+			"updatedAt":   time.Now(),
+		},
+	}
+	result, err := a.c.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	if result.UpsertedCount == 0 {
+		return nil, errors.New("The role already exists.")
+	}
+	return a.FindByName(role.Name)
+}
+
+func (a *adminRole) FindByName(name string) (*types.AdminRole, error) {
+	role := types.AdminRole{}
+	err := a.c.FindOne(context.Background(), bson.M{"name": name}).Decode(&role)
+	if err != nil {
+		return nil, errors.New("The specified role could not be found.")
+	}
+	return &role, nil
+}
+
+func (a *adminRole) FindByNames(names []string) ([]*types.AdminRole, error) {
+	return a.find(bson.M{"name": bson.M{"$in": names}})
+}
+
+func (a *adminRole) FindAll() ([]*types.AdminRole, error) {
+	return a.find(bson.M{})
+}
+
+func (a *adminRole) find(filter bson.M) ([]*types.AdminRole, error) {
+	findOptions := options.Find().SetSort(bson.M{"name": 1})
+	cur, err := a.c.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []*types.AdminRole{}
+	for cur.Next(context.Background()) {
+		var elem types.AdminRole
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+	return roles, nil
+}
+
+func (a *adminRole) FindOneAndUpdate(role *types.AdminRole) (*types.AdminRole, error) {
+	filter := bson.M{"name": role.Name}
+	update := bson.M{"$set": bson.M{
+		"description": role.Description,
+		"permissions": role.Permissions,
+		"updatedAt":   time.Now(),
+	}}
+	result := a.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, errors.New("The specified role could not be found.")
+	}
+	updated := types.AdminRole{}
+	err := result.Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (a *adminRole) Delete(name string) error {
+	result, err := a.c.DeleteOne(context.Background(), bson.M{"name": name})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("The specified role could not be found.")
+	}
+	return nil
+}
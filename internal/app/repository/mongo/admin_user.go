@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type adminUser struct {
@@ -92,7 +93,12 @@ func (u *adminUser) UpdateLoginInfo(id primitive.ObjectID, newLoginIP string) (*
 
 func (u *adminUser) UpdatePassword(user *types.AdminUser) error {
 	filter := bson.M{"_id": user.ID}
-	update := bson.M{"$set": bson.M{"password": user.Password, "updatedAt": time.Now()}}
+	update := bson.M{"$set": bson.M{
+		"password":          user.Password,
+		"passwordHistory":   user.PasswordHistory,
+		"passwordChangedAt": user.PasswordChangedAt,
+		"updatedAt":         time.Now(),
+	}}
 	_, err := u.c.UpdateOne(
 		context.Background(),
 		filter,
@@ -103,3 +109,147 @@ func (u *adminUser) UpdatePassword(user *types.AdminUser) error {
 	}
 	return nil
 }
+
+func (u *adminUser) SetPendingTwoFactorSecret(id primitive.ObjectID, secret string) error {
+	return setPendingTwoFactorSecret(u.c, id, secret)
+}
+
+func (u *adminUser) EnableTwoFactor(id primitive.ObjectID, secret string, counter int64, recoveryCodes []string) error {
+	return enableTwoFactor(u.c, id, secret, counter, recoveryCodes)
+}
+
+func (u *adminUser) DisableTwoFactor(id primitive.ObjectID) error {
+	return disableTwoFactor(u.c, id)
+}
+
+func (u *adminUser) UseTwoFactorCounter(id primitive.ObjectID, counter int64) error {
+	return useTwoFactorCounter(u.c, id, counter)
+}
+
+func (u *adminUser) UseRecoveryCode(id primitive.ObjectID, hash string) error {
+	return useRecoveryCode(u.c, id, hash)
+}
+
+func (u *adminUser) SetRecoveryCodes(id primitive.ObjectID, recoveryCodes []string) error {
+	return setRecoveryCodes(u.c, id, recoveryCodes)
+}
+
+// POST /admin/admin-users
+
+func (u *adminUser) Create(admin *types.AdminUser) (*types.AdminUser, error) {
+	filter := bson.M{"email": admin.Email, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"email":             admin.Email,
+			"name":              admin.Name,
+			"password":          admin.Password,
+			"passwordChangedAt": time.Now(),
+			"roles":             admin.Roles,
+			"createdAt":         time.Now(),
+			"updatedAt":         time.Now(),
+		},
+	}
+	result, err := u.c.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	if result.UpsertedCount == 0 {
+		return nil, errors.New("Admin email address is already registered.")
+	}
+	return u.FindByID(result.UpsertedID.(primitive.ObjectID))
+}
+
+// GET /admin/admin-users
+
+func (u *adminUser) FindAll() ([]*types.AdminUser, error) {
+	filter := bson.M{"deletedAt": bson.M{"$exists": false}}
+	cur, err := u.c.Find(context.Background(), filter, options.Find().SetSort(bson.M{"email": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	admins := []*types.AdminUser{}
+	for cur.Next(context.Background()) {
+		var elem types.AdminUser
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+	return admins, nil
+}
+
+// PATCH /admin/admin-users/{adminID}
+
+func (u *adminUser) FindOneAndUpdate(id primitive.ObjectID, update *types.AdminUser) (*types.AdminUser, error) {
+	set := bson.M{"updatedAt": time.Now()}
+	if update.Name != "" {
+		set["name"] = update.Name
+	}
+	if update.Roles != nil {
+		set["roles"] = update.Roles
+	}
+	filter := bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}
+	result := u.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, errors.New("The specified admin could not be found.")
+	}
+	updated := types.AdminUser{}
+	err := result.Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DELETE /admin/admin-users/{adminID}
+
+func (u *adminUser) FindOneAndDelete(id primitive.ObjectID) (*types.AdminUser, error) {
+	filter := bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"deletedAt": time.Now(), "updatedAt": time.Now()}}
+	result := u.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, errors.New("The specified admin could not be found.")
+	}
+	deleted := types.AdminUser{}
+	err := result.Decode(&deleted)
+	if err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
+// CountByRole counts the admins who have the role.
+func (u *adminUser) CountByRole(role string) (int64, error) {
+	filter := bson.M{"roles": role, "deletedAt": bson.M{"$exists": false}}
+	return u.c.CountDocuments(context.Background(), filter)
+}
+
+// BackfillRoles gives the role to the admins created before admin roles existed.
+func (u *adminUser) BackfillRoles(role string) (int64, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"roles": bson.M{"$exists": false}},
+		{"roles": bson.M{"$size": 0}},
+	}}
+	update := bson.M{"$set": bson.M{"roles": []string{role}, "updatedAt": time.Now()}}
+	result, err := u.c.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,126 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type apiKey struct {
+	c *mongo.Collection
+}
+
+var APIKey = &apiKey{}
+
+func (a *apiKey) Register(db *mongo.Database) {
+	a.c = db.Collection("apiKeys")
+}
+
+func (a *apiKey) Create(key *types.APIKey) (*types.APIKey, error) {
+	key.ID = primitive.NewObjectID()
+	key.CreatedAt = time.Now()
+	key.UpdatedAt = time.Now()
+	_, err := a.c.InsertOne(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// FindActiveByHash returns the key which has not been revoked.
+func (a *apiKey) FindActiveByHash(hash string) (*types.APIKey, error) {
+	key := types.APIKey{}
+	filter := bson.M{"keyHash": hash, "revokedAt": bson.M{"$exists": false}}
+	err := a.c.FindOne(context.Background(), filter).Decode(&key)
+	if err != nil {
+		return nil, errors.New("API key is invalid.")
+	}
+	return &key, nil
+}
+
+func (a *apiKey) FindByID(id primitive.ObjectID) (*types.APIKey, error) {
+	key := types.APIKey{}
+	err := a.c.FindOne(context.Background(), bson.M{"_id": id}).Decode(&key)
+	if err != nil {
+		return nil, errors.New("API key not found.")
+	}
+	return &key, nil
+}
+
+// FindByEntityID returns the active keys of the entity, the newest first.
+func (a *apiKey) FindByEntityID(entityID primitive.ObjectID) ([]*types.APIKey, error) {
+	filter := bson.M{"entityID": entityID, "revokedAt": bson.M{"$exists": false}}
+	findOptions := options.Find().SetSort(bson.M{"createdAt": -1})
+	cur, err := a.c.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	keys := []*types.APIKey{}
+	for cur.Next(context.Background()) {
+		var elem types.APIKey
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+	return keys, nil
+}
+
+func (a *apiKey) Revoke(id primitive.ObjectID) (*types.APIKey, error) {
+	filter := bson.M{"_id": id, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now(), "updatedAt": time.Now()}}
+	result := a.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, errors.New("API key not found.")
+	}
+	key := types.APIKey{}
+	err := result.Decode(&key)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeByEntityID revokes every active key of the entity.
+func (a *apiKey) RevokeByEntityID(entityID primitive.ObjectID) error {
+	filter := bson.M{"entityID": entityID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now(), "updatedAt": time.Now()}}
+	_, err := a.c.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// UpdateLastUsed records the use of the key and returns the key as it was before.
+func (a *apiKey) UpdateLastUsed(id primitive.ObjectID, ipAddress string) (*types.APIKey, error) {
+	update := bson.M{"$set": bson.M{"lastUsedAt": time.Now(), "lastUsedIP": ipAddress}}
+	result := a.c.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": id},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	key := types.APIKey{}
+	err := result.Decode(&key)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,213 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type application struct {
+	c *mongo.Collection
+}
+
+var Application = &application{}
+
+func (a *application) Register(db *mongo.Database) {
+	a.c = db.Collection("applications")
+}
+
+var openApplication = bson.M{"$in": []string{constant.Application.Open, constant.Application.InfoRequested}}
+
+// Create opens an application for the entity unless it already has one.
+func (a *application) Create(entityID primitive.ObjectID) (*types.Application, error) {
+	filter := bson.M{"entityID": entityID}
+	update := bson.M{"$setOnInsert": bson.M{
+		"entityID":    entityID,
+		"status":      constant.Application.Open,
+		"submittedAt": time.Now(),
+		"createdAt":   time.Now(),
+		"updatedAt":   time.Now(),
+	}}
+
+	result := a.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	created := types.Application{}
+	err := result.Decode(&created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (a *application) FindByID(id primitive.ObjectID) (*types.Application, error) {
+	application := types.Application{}
+	err := a.c.FindOne(context.Background(), bson.M{"_id": id}).Decode(&application)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("Application not found.")
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (a *application) FindByEntityID(entityID primitive.ObjectID) (*types.Application, error) {
+	application := types.Application{}
+	err := a.c.FindOne(context.Background(), bson.M{"entityID": entityID}).Decode(&application)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("Application not found.")
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+// GET /admin/applications
+
+func (a *application) Search(req *types.AdminSearchApplicationReq) (*types.SearchApplicationResult, error) {
+	var results []*types.Application
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(req.PageSize * (req.Page - 1)))
+	findOptions.SetLimit(int64(req.PageSize))
+	findOptions.SetSort(bson.M{"submittedAt": 1})
+
+	filter := bson.M{"status": bson.M{"$in": req.Statuses}}
+	if !req.ReviewerID.IsZero() {
+		filter["reviewerID"] = req.ReviewerID
+	}
+	cur, err := a.c.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for cur.Next(context.TODO()) {
+		var elem types.Application
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+
+	totalCount, err := a.c.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SearchApplicationResult{
+		Applications:    results,
+		NumberOfResults: int(totalCount),
+		TotalPages:      util.GetNumberOfPages(int(totalCount), req.PageSize),
+	}, nil
+}
+
+func (a *application) update(filter bson.M, update bson.M, opts ...*options.FindOneAndUpdateOptions) (*types.Application, error) {
+	opts = append(opts, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	result := a.c.FindOneAndUpdate(context.Background(), filter, update, opts...)
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, errors.New("The application is not open for review.")
+		}
+		return nil, result.Err()
+	}
+
+	updated := types.Application{}
+	err := result.Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// PATCH /admin/applications/{applicationID}/reviewer
+
+func (a *application) AssignReviewer(id primitive.ObjectID, reviewer *types.AdminUser) (*types.Application, error) {
+	filter := bson.M{"_id": id, "status": openApplication}
+	update := bson.M{"$set": bson.M{
+		"reviewerID":    reviewer.ID,
+		"reviewerEmail": reviewer.Email,
+		"updatedAt":     time.Now(),
+	}}
+	return a.update(filter, update)
+}
+
+// POST /admin/applications/{applicationID}/notes
+
+func (a *application) AddNote(id primitive.ObjectID, note *types.ApplicationNote) (*types.Application, error) {
+	note.CreatedAt = time.Now()
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$push": bson.M{"notes": note},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return a.update(filter, update)
+}
+
+// POST /admin/applications/{applicationID}/info-requests
+
+func (a *application) RequestInfo(id primitive.ObjectID, infoRequest *types.ApplicationInfoRequest) (*types.Application, error) {
+	infoRequest.RequestedAt = time.Now()
+	filter := bson.M{"_id": id, "status": openApplication}
+	update := bson.M{
+		"$push": bson.M{"infoRequests": infoRequest},
+		"$set": bson.M{
+			"status":    constant.Application.InfoRequested,
+			"updatedAt": time.Now(),
+		},
+	}
+	return a.update(filter, update)
+}
+
+// POST /user/entities/{entityID}/application/resubmit
+
+// Resubmit answers the outstanding information requests and puts the application back in the queue.
+func (a *application) Resubmit(entityID primitive.ObjectID, response string) (*types.Application, error) {
+	filter := bson.M{"entityID": entityID, "status": constant.Application.InfoRequested}
+	update := bson.M{"$set": bson.M{
+		"status":                              constant.Application.Open,
+		"submittedAt":                         time.Now(),
+		"updatedAt":                           time.Now(),
+		"infoRequests.$[pending].response":    response,
+		"infoRequests.$[pending].respondedAt": time.Now(),
+	}}
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{bson.M{"pending.respondedAt": bson.M{"$exists": false}}},
+	}
+	return a.update(filter, update, options.FindOneAndUpdate().SetArrayFilters(arrayFilters))
+}
+
+// Close records the final decision on the open application of the entity.
+func (a *application) Close(entityID primitive.ObjectID, status string, reason string, decidedBy string) error {
+	filter := bson.M{"entityID": entityID, "status": openApplication}
+	update := bson.M{"$set": bson.M{
+		"status":         status,
+		"decisionReason": reason,
+		"decidedBy":      decidedBy,
+		"decidedAt":      time.Now(),
+		"updatedAt":      time.Now(),
+	}}
+	_, err := a.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
@@ -0,0 +1,178 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type dataExport struct {
+	c *mongo.Collection
+}
+
+var DataExport = &dataExport{}
+
+func (d *dataExport) Register(db *mongo.Database) {
+	d.c = db.Collection("dataExports")
+}
+
+func (d *dataExport) Create(export *types.DataExport) (*types.DataExport, error) {
+	filter := bson.M{"_id": bson.M{"$exists": false}}
+	update := bson.M{
+		"userID":      export.UserID,
+		"requestedBy": export.RequestedBy,
+		"status":      constant.DataExport.Pending,
+		"token":       export.Token,
+		"createdAt":   time.Now(),
+		"updatedAt":   time.Now(),
+	}
+
+	result := d.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	created := types.DataExport{}
+	err := result.Decode(&created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (d *dataExport) FindByToken(token string) (*types.DataExport, error) {
+	if token == "" {
+		return nil, errors.New("Invalid token.")
+	}
+	export := types.DataExport{}
+	err := d.c.FindOne(context.Background(), bson.M{"token": token}).Decode(&export)
+	if err != nil {
+		return nil, errors.New("Invalid token.")
+	}
+	return &export, nil
+}
+
+// FindPending returns the export of the user which is still being generated and was created after the time, if any.
+func (d *dataExport) FindPending(userID primitive.ObjectID, since time.Time) (*types.DataExport, error) {
+	export := types.DataExport{}
+	filter := bson.M{
+		"userID":    userID,
+		"status":    constant.DataExport.Pending,
+		"createdAt": bson.M{"$gte": since},
+	}
+	err := d.c.FindOne(context.Background(), filter).Decode(&export)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (d *dataExport) SetReady(id primitive.ObjectID, key string, size int) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"status":      constant.DataExport.Ready,
+		"key":         key,
+		"size":        size,
+		"completedAt": time.Now(),
+		"updatedAt":   time.Now(),
+	}}
+	_, err := d.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+func (d *dataExport) SetStatus(id primitive.ObjectID, status string) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"status":    status,
+		"updatedAt": time.Now(),
+	}}
+	_, err := d.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// FailStale marks the exports still pending since before the time as failed, their generation was interrupted.
+func (d *dataExport) FailStale(before time.Time) (int64, error) {
+	filter := bson.M{
+		"status":    constant.DataExport.Pending,
+		"createdAt": bson.M{"$lt": before},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":    constant.DataExport.Failed,
+		"updatedAt": time.Now(),
+	}}
+	result, err := d.c.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// FindExpired returns the ready exports completed before the time.
+func (d *dataExport) FindExpired(before time.Time) ([]*types.DataExport, error) {
+	filter := bson.M{
+		"status":      constant.DataExport.Ready,
+		"completedAt": bson.M{"$lt": before},
+	}
+	cur, err := d.c.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var exports []*types.DataExport
+	for cur.Next(context.Background()) {
+		var elem types.DataExport
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+
+	return exports, nil
+}
+
+// POST /admin/users/{userID}/erasure
+
+// DeleteByUserID removes the exports of the user and returns them so their archives can be deleted.
+func (d *dataExport) DeleteByUserID(userID primitive.ObjectID) ([]*types.DataExport, error) {
+	cur, err := d.c.Find(context.Background(), bson.M{"userID": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var exports []*types.DataExport
+	for cur.Next(context.Background()) {
+		var elem types.DataExport
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+
+	_, err = d.c.DeleteMany(context.Background(), bson.M{"userID": userID})
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type emailVerification struct {
+	c *mongo.Collection
+}
+
+var EmailVerification = &emailVerification{}
+
+func (e *emailVerification) Register(db *mongo.Database) {
+	e.c = db.Collection("emailVerifications")
+}
+
+// Create replaces the pending token of the same user or entity.
+func (e *emailVerification) Create(verification *types.EmailVerification) (*types.EmailVerification, error) {
+	filter := bson.M{"verifiedAt": bson.M{"$exists": false}}
+	if !verification.UserID.IsZero() {
+		filter["userID"] = verification.UserID
+	} else {
+		filter["entityID"] = verification.EntityID
+	}
+	_, err := e.c.DeleteMany(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	verification.ID = primitive.NewObjectID()
+	verification.CreatedAt = time.Now()
+	_, err = e.c.InsertOne(context.Background(), verification)
+	if err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+func (e *emailVerification) FindByToken(token string) (*types.EmailVerification, error) {
+	if token == "" {
+		return nil, errors.New("Invalid token.")
+	}
+	verification := types.EmailVerification{}
+	err := e.c.FindOne(context.Background(), bson.M{"token": token}).Decode(&verification)
+	if err != nil {
+		return nil, errors.New("Invalid token.")
+	}
+	return &verification, nil
+}
+
+// SetVerified marks the token as used, it fails when the token has already been used.
+func (e *emailVerification) SetVerified(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "verifiedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"verifiedAt": time.Now()}}
+	result, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Invalid token.")
+	}
+	return nil
+}
+
+// POST /admin/users/{userID}/erasure
+
+func (e *emailVerification) DeleteByUserID(userID primitive.ObjectID) error {
+	_, err := e.c.DeleteMany(context.Background(), bson.M{"userID": userID})
+	return err
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func (e *emailVerification) DeleteByEntityID(entityID primitive.ObjectID) error {
+	_, err := e.c.DeleteMany(context.Background(), bson.M{"entityID": entityID})
+	return err
+}
@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 
@@ -94,9 +95,13 @@ func (e *entity) FindByID(id primitive.ObjectID) (*types.Entity, error) {
 func (e *entity) FindByAccountNumber(accountNumber string) (*types.Entity, error) {
 	ctx := context.Background()
 	entity := types.Entity{}
+	// The account numbers of merged entities resolve to the entity they were merged into.
 	filter := bson.M{
-		"accountNumber": accountNumber,
-		"deletedAt":     bson.M{"$exists": false},
+		"$or": []bson.M{
+			{"accountNumber": accountNumber},
+			{"accountAliases": accountNumber},
+		},
+		"deletedAt": bson.M{"$exists": false},
 	}
 	err := e.c.FindOne(ctx, filter).Decode(&entity)
 	if err != nil {
@@ -170,12 +175,18 @@ func (e *entity) FindOneAndUpdate(req *types.UpdateUserEntityReq) (*types.Entity
 	if req.PostalCode != "" {
 		update["postalCode"] = req.PostalCode
 	}
+	if req.Location != nil {
+		update["location"] = req.Location
+	}
 	if req.ReceiveDailyMatchNotificationEmail != nil {
 		update["receiveDailyMatchNotificationEmail"] = *req.ReceiveDailyMatchNotificationEmail
 	}
 	if req.ShowTagsMatchedSinceLastLogin != nil {
 		update["showTagsMatchedSinceLastLogin"] = *req.ShowTagsMatchedSinceLastLogin
 	}
+	if req.BalanceAlerts != nil {
+		update["balanceAlerts"] = req.BalanceAlerts
+	}
 	updates = append(updates, bson.M{"$set": update})
 
 	push := bson.M{}
@@ -265,6 +276,9 @@ func (e *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.
 	if req.PostalCode != "" {
 		update["postalCode"] = req.PostalCode
 	}
+	if req.Location != nil {
+		update["location"] = req.Location
+	}
 	if req.Categories != nil {
 		update["categories"] = util.FormatTags(*req.Categories)
 	}
@@ -331,6 +345,18 @@ func (e *entity) AdminFindOneAndUpdate(req *types.AdminUpdateEntityReq) (*types.
 	if err != nil {
 		return nil, err
 	}
+	for _, userID := range req.AddedUsers {
+		err = e.AssociateUser([]primitive.ObjectID{req.OriginEntity.ID}, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, userID := range req.RemovedUsers {
+		err = e.removeAssociatedUser([]primitive.ObjectID{req.OriginEntity.ID}, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	entity, err := e.FindByID(req.OriginEntity.ID)
 	if err != nil {
@@ -348,11 +374,15 @@ func (e *entity) AdminFindOneAndDelete(id primitive.ObjectID) (*types.Entity, er
 	result := e.c.FindOneAndUpdate(
 		context.Background(),
 		filter,
-		bson.M{"$set": bson.M{
-			"users":     []primitive.ObjectID{},
-			"deletedAt": time.Now(),
-			"updatedAt": time.Now(),
-		}},
+		bson.M{
+			"$set": bson.M{
+				"users":     []primitive.ObjectID{},
+				"members":   []*types.EntityMember{},
+				"deletedAt": time.Now(),
+				"updatedAt": time.Now(),
+			},
+			"$unset": bson.M{"logo": "", "images": ""},
+		},
 		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	)
 
@@ -620,6 +650,10 @@ func (en *entity) AssociateUser(entityIDs []primitive.ObjectID, UserID primitive
 		model := mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update)
 		writes = append(writes, model)
 	}
+	// Associated users are owners unless they are already a member of the entity.
+	writes = append(writes, mongo.NewUpdateManyModel().
+		SetFilter(bson.M{"_id": bson.M{"$in": entityIDs}, "members.userID": bson.M{"$ne": UserID}}).
+		SetUpdate(bson.M{"$push": bson.M{"members": &types.EntityMember{UserID: UserID, Role: constant.EntityRole.Owner}}}))
 
 	_, err := en.c.BulkWrite(context.Background(), writes)
 	if err != nil {
@@ -633,7 +667,7 @@ func (en *entity) AssociateUser(entityIDs []primitive.ObjectID, UserID primitive
 func (e *entity) removeAssociatedUser(entityIDs []primitive.ObjectID, userID primitive.ObjectID) error {
 	filter := bson.M{"_id": bson.M{"$in": entityIDs}}
 	updates := []bson.M{
-		bson.M{"$pull": bson.M{"users": userID}},
+		bson.M{"$pull": bson.M{"users": userID, "members": bson.M{"userID": userID}}},
 		bson.M{"$set": bson.M{"updatedAt": time.Now()}},
 	}
 
@@ -650,6 +684,196 @@ func (e *entity) removeAssociatedUser(entityIDs []primitive.ObjectID, userID pri
 	return nil
 }
 
+// POST /invitations/{token}/accept
+
+func (e *entity) AddMember(entityID primitive.ObjectID, userID primitive.ObjectID, role string) error {
+	filter := bson.M{"_id": entityID, "members.userID": bson.M{"$ne": userID}}
+	update := bson.M{
+		"$addToSet": bson.M{"users": userID},
+		"$push":     bson.M{"members": &types.EntityMember{UserID: userID, Role: role}},
+		"$set":      bson.M{"updatedAt": time.Now()},
+	}
+	_, err := e.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func (e *entity) UpdateMemberRole(entityID primitive.ObjectID, userID primitive.ObjectID, role string) (*types.Entity, error) {
+	filter := bson.M{"_id": entityID, "members.userID": userID, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{
+		"members.$.role": role,
+		"updatedAt":      time.Now(),
+	}}
+
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, errors.New("Member not found.")
+	}
+
+	entity := types.Entity{}
+	err := result.Decode(&entity)
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+func (e *entity) RemoveMember(entityID primitive.ObjectID, userID primitive.ObjectID) error {
+	err := e.removeAssociatedUser([]primitive.ObjectID{entityID}, userID)
+	if err != nil {
+		return err
+	}
+	err = User.removeAssociatedEntity([]primitive.ObjectID{userID}, entityID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// PUT /user/entities/{entityID}/logo
+
+func (e *entity) SetLogo(id primitive.ObjectID, logo *types.EntityImage) (*types.Entity, error) {
+	filter := bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{
+		"logo":      logo,
+		"updatedAt": time.Now(),
+	}}
+	return e.updateImages(filter, update)
+}
+
+// DELETE /user/entities/{entityID}/logo
+
+func (e *entity) UnsetLogo(id primitive.ObjectID) (*types.Entity, error) {
+	filter := bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{
+		"$unset": bson.M{"logo": ""},
+		"$set":   bson.M{"updatedAt": time.Now()},
+	}
+	return e.updateImages(filter, update)
+}
+
+// POST /user/entities/{entityID}/images
+
+// AddImage only adds the image when the gallery has room for it.
+func (e *entity) AddImage(id primitive.ObjectID, image *types.EntityImage, maxImages int) (*types.Entity, error) {
+	filter := bson.M{
+		"_id":                                 id,
+		"deletedAt":                           bson.M{"$exists": false},
+		"images." + strconv.Itoa(maxImages-1): bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$push": bson.M{"images": image},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	updated, err := e.updateImages(filter, update)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.New("The gallery cannot have more than " + strconv.Itoa(maxImages) + " images.")
+	}
+	return updated, err
+}
+
+// DELETE /user/entities/{entityID}/images/{imageID}
+
+func (e *entity) RemoveImage(id primitive.ObjectID, imageID string) (*types.Entity, error) {
+	filter := bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{
+		"$pull": bson.M{"images": bson.M{"id": imageID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return e.updateImages(filter, update)
+}
+
+func (e *entity) updateImages(filter bson.M, update bson.M) (*types.Entity, error) {
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	entity := types.Entity{}
+	err := result.Decode(&entity)
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// BackfillMembers gives the owner role to the users associated with an entity before entity roles existed.
+func (e *entity) BackfillMembers() (int, error) {
+	filter := bson.M{"users.0": bson.M{"$exists": true}, "deletedAt": bson.M{"$exists": false}}
+	cur, err := e.c.Find(context.Background(), filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(context.Background())
+
+	count := 0
+	for cur.Next(context.Background()) {
+		var entity types.Entity
+		err := cur.Decode(&entity)
+		if err != nil {
+			return count, err
+		}
+		for _, userID := range entity.Users {
+			if entity.MemberRole(userID) != "" {
+				continue
+			}
+			err = e.AssociateUser([]primitive.ObjectID{entity.ID}, userID)
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (e *entity) FindByStatus(status string) ([]*types.Entity, error) {
+	filter := bson.M{
+		"status":    status,
+		"deletedAt": bson.M{"$exists": false},
+	}
+	cur, err := e.c.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []*types.Entity
+	for cur.Next(context.TODO()) {
+		var elem types.Entity
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+
+	return entities, nil
+}
+
 // daily_email_schedule
 
 func (e *entity) FindByDailyNotification() ([]*types.Entity, error) {
@@ -697,3 +921,210 @@ func (e *entity) UpdateLastNotificationSentDate(id primitive.ObjectID) error {
 	}
 	return nil
 }
+
+// POST /admin/entities/{entityID}/erasure
+
+// FindErasable also finds soft-deleted entities since their personal data is still stored.
+func (e *entity) FindErasable(id primitive.ObjectID) (*types.Entity, error) {
+	entity := types.Entity{}
+	filter := bson.M{"_id": id, "erasedAt": bson.M{"$exists": false}}
+	err := e.c.FindOne(context.Background(), filter).Decode(&entity)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("The specified entity could not be found or has already been erased.")
+		}
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// Erase replaces the name of the entity with the pseudonym, removes the rest of its profile and soft-deletes it.
+// The account number is kept so the entity can still be matched with its transfers.
+func (e *entity) Erase(id primitive.ObjectID, pseudonym string) (*types.Entity, error) {
+	filter := bson.M{"_id": id, "erasedAt": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set": bson.M{
+			"name":      pseudonym,
+			"users":     []primitive.ObjectID{},
+			"members":   []*types.EntityMember{},
+			"erasedAt":  time.Now(),
+			"updatedAt": time.Now(),
+		},
+		// Keeps the original deletion time of an already deleted entity.
+		"$min": bson.M{"deletedAt": time.Now()},
+		"$unset": bson.M{
+			"telephone":        "",
+			"email":            "",
+			"incType":          "",
+			"companyNumber":    "",
+			"website":          "",
+			"declaredTurnover": "",
+			"offers":           "",
+			"wants":            "",
+			"description":      "",
+			"address":          "",
+			"city":             "",
+			"region":           "",
+			"country":          "",
+			"postalCode":       "",
+			"categories":       "",
+			"favoriteEntities": "",
+			"balanceAlerts":    "",
+			"logo":             "",
+			"images":           "",
+			"location":         "",
+		},
+	}
+
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	)
+
+	entity := types.Entity{}
+	err := result.Decode(&entity)
+	if err != nil {
+		return nil, result.Err()
+	}
+
+	err = User.removeAssociatedEntity(entity.Users, entity.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+// POST /admin/entities/{entityID}/merge
+
+// Merge stores the combined profile of the kept entity, soft-deletes the merged one and moves the references
+// of the users and of the other entities' favorites over to the kept entity.
+func (e *entity) Merge(merged *types.Entity, into *types.Entity) (*types.Entity, error) {
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": into.ID, "deletedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{
+			"users":            into.Users,
+			"members":          into.Members,
+			"offers":           into.Offers,
+			"wants":            into.Wants,
+			"categories":       into.Categories,
+			"favoriteEntities": into.FavoriteEntities,
+			"accountAliases":   into.AccountAliases,
+			"updatedAt":        time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	updated := types.Entity{}
+	err := result.Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = e.c.UpdateOne(
+		context.Background(),
+		bson.M{"_id": merged.ID},
+		bson.M{"$set": bson.M{
+			"users":      []primitive.ObjectID{},
+			"members":    []*types.EntityMember{},
+			"mergedInto": into.ID,
+			"deletedAt":  time.Now(),
+			"updatedAt":  time.Now(),
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = User.replaceAssociatedEntity(merged.ID, into.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"favoriteEntities": merged.ID}
+	updates := []bson.M{
+		bson.M{"$addToSet": bson.M{"favoriteEntities": into.ID}},
+		bson.M{"$set": bson.M{"updatedAt": time.Now()}},
+		// Last since the filter no longer matches afterwards.
+		bson.M{"$pull": bson.M{"favoriteEntities": merged.ID}},
+	}
+	var writes []mongo.WriteModel
+	for _, update := range updates {
+		model := mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update)
+		writes = append(writes, model)
+	}
+	// The kept entity does not keep itself as a favorite.
+	model := mongo.NewUpdateOneModel().
+		SetFilter(bson.M{"_id": into.ID}).
+		SetUpdate(bson.M{"$pull": bson.M{"favoriteEntities": into.ID}})
+	writes = append(writes, model)
+
+	_, err = e.c.BulkWrite(context.Background(), writes, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// POST /ownership-transfers/{token}/accept
+
+// SetMembers replaces the members and the email of the entity in a single update. It only applies while the
+// members are still the expected ones so the changes made since they were read are not overwritten.
+func (e *entity) SetMembers(id primitive.ObjectID, expected []*types.EntityMember, users []primitive.ObjectID, members []*types.EntityMember, email string) (*types.Entity, error) {
+	update := bson.M{
+		"users":     users,
+		"members":   members,
+		"updatedAt": time.Now(),
+	}
+	if email != "" {
+		update["email"] = email
+	}
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": id, "members": expected, "deletedAt": bson.M{"$exists": false}},
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	entity := types.Entity{}
+	err := result.Decode(&entity)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("The members of the entity have changed, please try again.")
+		}
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// SetEmailUnverified requires the entity email address to be confirmed again.
+func (e *entity) SetEmailUnverified(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$set":   bson.M{"emailUnverified": true, "updatedAt": time.Now()},
+		"$unset": bson.M{"emailVerifiedAt": ""},
+	}
+	_, err := e.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// SetEmailVerified confirms the email address of the unverified entities which still use it.
+func (e *entity) SetEmailVerified(ids []primitive.ObjectID, email string) (int64, error) {
+	filter := bson.M{
+		"_id":             bson.M{"$in": ids},
+		"email":           email,
+		"emailUnverified": true,
+		"deletedAt":       bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set":   bson.M{"emailVerifiedAt": time.Now(), "updatedAt": time.Now()},
+		"$unset": bson.M{"emailUnverified": ""},
+	}
+	result, err := e.c.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,80 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type entityStatusChange struct {
+	c *mongo.Collection
+}
+
+var EntityStatusChange = &entityStatusChange{}
+
+func (e *entityStatusChange) Register(db *mongo.Database) {
+	e.c = db.Collection("entityStatusChanges")
+}
+
+func (e *entityStatusChange) Create(change *types.EntityStatusChange) (*types.EntityStatusChange, error) {
+	filter := bson.M{"_id": bson.M{"$exists": false}}
+	update := bson.M{
+		"entityID":  change.EntityID,
+		"from":      change.From,
+		"to":        change.To,
+		"reason":    change.Reason,
+		"changedBy": change.ChangedBy,
+		"createdAt": time.Now(),
+	}
+
+	result := e.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	created := types.EntityStatusChange{}
+	err := result.Decode(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GET /admin/entities/{entityID}/status-history
+
+func (e *entityStatusChange) FindByEntityID(entityID primitive.ObjectID) ([]*types.EntityStatusChange, error) {
+	filter := bson.M{"entityID": entityID}
+	findOptions := options.Find().SetSort(bson.M{"createdAt": 1})
+
+	cur, err := e.c.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	changes := []*types.EntityStatusChange{}
+	for cur.Next(context.Background()) {
+		var change types.EntityStatusChange
+		err := cur.Decode(&change)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
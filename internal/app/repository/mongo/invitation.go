@@ -0,0 +1,168 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type invitation struct {
+	c *mongo.Collection
+}
+
+var Invitation = &invitation{}
+
+func (i *invitation) Register(db *mongo.Database) {
+	i.c = db.Collection("invitations")
+}
+
+// Create creates an invitation record in the table, an existing invitation of the same email
+// to the same entity is replaced by the new one.
+func (i *invitation) Create(invitation *types.Invitation) (*types.Invitation, error) {
+	filter := bson.M{"entityID": invitation.EntityID, "email": invitation.Email}
+	update := bson.M{"$set": bson.M{
+		"entityID":   invitation.EntityID,
+		"entityName": invitation.EntityName,
+		"email":      invitation.Email,
+		"role":       invitation.Role,
+		"invitedBy":  invitation.InvitedBy,
+		"token":      invitation.Token,
+		"tokenUsed":  false,
+		"revoked":    false,
+		"createdAt":  time.Now(),
+	}}
+	result := i.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	created := types.Invitation{}
+	err := result.Decode(&created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (i *invitation) FindByToken(token string) (*types.Invitation, error) {
+	if token == "" {
+		return nil, errors.New("Invalid token.")
+	}
+	invitation := types.Invitation{}
+	err := i.c.FindOne(context.Background(), bson.M{"token": token}).Decode(&invitation)
+	if err != nil {
+		return nil, errors.New("Invalid token.")
+	}
+	return &invitation, nil
+}
+
+func (i *invitation) FindByID(id primitive.ObjectID) (*types.Invitation, error) {
+	invitation := types.Invitation{}
+	err := i.c.FindOne(context.Background(), bson.M{"_id": id}).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("Invitation not found.")
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GET /user/entities/{entityID}/invitations
+
+func (i *invitation) FindPending(entityID primitive.ObjectID, since time.Time) ([]*types.Invitation, error) {
+	filter := bson.M{
+		"entityID":  entityID,
+		"tokenUsed": false,
+		"revoked":   false,
+		"createdAt": bson.M{"$gte": since},
+	}
+	findOptions := options.Find().SetSort(bson.M{"createdAt": -1})
+
+	cur, err := i.c.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	invitations := []*types.Invitation{}
+	for cur.Next(context.Background()) {
+		var invitation types.Invitation
+		err := cur.Decode(&invitation)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, &invitation)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// POST /invitations/{token}/accept
+
+// Claim marks the token as used so the same invitation can only be accepted once.
+func (i *invitation) Claim(token string) error {
+	filter := bson.M{"token": token, "tokenUsed": false, "revoked": false}
+	update := bson.M{"$set": bson.M{"tokenUsed": true}}
+	result, err := i.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Token is invalid.")
+	}
+	return nil
+}
+
+// Unclaim releases the token of an invitation that could not be accepted.
+func (i *invitation) Unclaim(token string) error {
+	filter := bson.M{"token": token}
+	update := bson.M{"$set": bson.M{"tokenUsed": false}}
+	_, err := i.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// DELETE /user/entities/{entityID}/invitations/{invitationID}
+
+func (i *invitation) Revoke(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"revoked": true}}
+	_, err := i.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// POST /admin/users/{userID}/erasure
+
+func (i *invitation) DeleteByEmail(email string) error {
+	_, err := i.c.DeleteMany(context.Background(), bson.M{"email": email})
+	return err
+}
+
+// RemoveInvitedBy unlinks the invitations the user sent.
+func (i *invitation) RemoveInvitedBy(userID primitive.ObjectID) error {
+	filter := bson.M{"invitedBy": userID}
+	update := bson.M{"$unset": bson.M{"invitedBy": ""}}
+	_, err := i.c.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func (i *invitation) DeleteByEntityID(entityID primitive.ObjectID) error {
+	_, err := i.c.DeleteMany(context.Background(), bson.M{"entityID": entityID})
+	return err
+}
@@ -69,3 +69,10 @@ func (l *lostPassword) SetTokenUsed(token string) error {
 	_, err := l.c.UpdateOne(context.Background(), filter, update)
 	return err
 }
+
+// POST /admin/users/{userID}/erasure
+
+func (l *lostPassword) DeleteByEmail(email string) error {
+	_, err := l.c.DeleteMany(context.Background(), bson.M{"email": email})
+	return err
+}
@@ -31,11 +31,23 @@ func registerCollections(db *mongo.Database) {
 	Tag.Register(db)
 	Category.Register(db)
 	LostPassword.Register(db)
+	PaymentRequest.Register(db)
+	Notification.Register(db)
+	Invitation.Register(db)
+	EntityStatusChange.Register(db)
+	Application.Register(db)
+	DataExport.Register(db)
+	OwnershipTransfer.Register(db)
+	RefreshToken.Register(db)
+	AdminRole.Register(db)
+	APIKey.Register(db)
+	EmailVerification.Register(db)
 }
 
 // New returns an initialized JWT instance.
 func New() *mongo.Database {
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
 	client, err := mongo.NewClient(options.Client().ApplyURI(viper.GetString("mongo.url")))
 	if err != nil {
@@ -0,0 +1,89 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/ic3network/mccs-alpha-api/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type notification struct {
+	c *mongo.Collection
+}
+
+var Notification = &notification{}
+
+func (n *notification) Register(db *mongo.Database) {
+	n.c = db.Collection("notifications")
+}
+
+func (n *notification) Create(notification *types.Notification) (*types.Notification, error) {
+	filter := bson.M{"_id": bson.M{"$exists": false}}
+	update := bson.M{
+		"entityID":  notification.EntityID,
+		"type":      notification.Type,
+		"message":   notification.Message,
+		"createdAt": time.Now(),
+	}
+
+	result := n.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	created := types.Notification{}
+	err := result.Decode(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (n *notification) Search(req *types.SearchNotificationReq) (*types.SearchNotificationResult, error) {
+	var results []*types.Notification
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(req.PageSize * (req.Page - 1)))
+	findOptions.SetLimit(int64(req.PageSize))
+	findOptions.SetSort(bson.M{"createdAt": -1})
+
+	filter := bson.M{"entityID": req.QueryingEntity.ID}
+	cur, err := n.c.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for cur.Next(context.TODO()) {
+		var elem types.Notification
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.TODO())
+
+	totalCount, err := n.c.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SearchNotificationResult{
+		Notifications:   results,
+		NumberOfResults: int(totalCount),
+		TotalPages:      util.GetNumberOfPages(int(totalCount), req.PageSize),
+	}, nil
+}
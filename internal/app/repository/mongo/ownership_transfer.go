@@ -0,0 +1,149 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ownershipTransfer struct {
+	c *mongo.Collection
+}
+
+var OwnershipTransfer = &ownershipTransfer{}
+
+func (o *ownershipTransfer) Register(db *mongo.Database) {
+	o.c = db.Collection("ownershipTransfers")
+}
+
+// Create creates an ownership transfer record in the table, an entity has at most one open transfer
+// so a new nomination replaces the previous one.
+func (o *ownershipTransfer) Create(transfer *types.OwnershipTransfer) (*types.OwnershipTransfer, error) {
+	filter := bson.M{"entityID": transfer.EntityID, "tokenUsed": false}
+	update := bson.M{"$set": bson.M{
+		"entityID":           transfer.EntityID,
+		"entityName":         transfer.EntityName,
+		"email":              transfer.Email,
+		"nominatedBy":        transfer.NominatedBy,
+		"previousOwnersRole": transfer.PreviousOwnersRole,
+		"updateEntityEmail":  transfer.UpdateEntityEmail,
+		"token":              transfer.Token,
+		"tokenUsed":          false,
+		"revoked":            false,
+		"createdAt":          time.Now(),
+	}}
+	result := o.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	created := types.OwnershipTransfer{}
+	err := result.Decode(&created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (o *ownershipTransfer) FindByToken(token string) (*types.OwnershipTransfer, error) {
+	if token == "" {
+		return nil, errors.New("Invalid token.")
+	}
+	transfer := types.OwnershipTransfer{}
+	err := o.c.FindOne(context.Background(), bson.M{"token": token}).Decode(&transfer)
+	if err != nil {
+		return nil, errors.New("Invalid token.")
+	}
+	return &transfer, nil
+}
+
+// GET /user/entities/{entityID}/ownership-transfer
+
+// FindPending returns the open transfer of the entity created after the time.
+func (o *ownershipTransfer) FindPending(entityID primitive.ObjectID, since time.Time) (*types.OwnershipTransfer, error) {
+	filter := bson.M{
+		"entityID":  entityID,
+		"tokenUsed": false,
+		"revoked":   false,
+		"createdAt": bson.M{"$gte": since},
+	}
+	transfer := types.OwnershipTransfer{}
+	err := o.c.FindOne(context.Background(), filter).Decode(&transfer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("There is no pending ownership transfer.")
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// POST /ownership-transfers/{token}/accept
+
+// Claim marks the token as used so the same transfer can only be completed once.
+func (o *ownershipTransfer) Claim(token string) error {
+	filter := bson.M{"token": token, "tokenUsed": false, "revoked": false}
+	update := bson.M{"$set": bson.M{"tokenUsed": true, "completedAt": time.Now()}}
+	result, err := o.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Token is invalid.")
+	}
+	return nil
+}
+
+// Unclaim releases the token of a transfer that could not be completed.
+func (o *ownershipTransfer) Unclaim(token string) error {
+	filter := bson.M{"token": token}
+	update := bson.M{
+		"$set":   bson.M{"tokenUsed": false},
+		"$unset": bson.M{"completedAt": ""},
+	}
+	_, err := o.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// DELETE /user/entities/{entityID}/ownership-transfer
+
+func (o *ownershipTransfer) Revoke(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"revoked": true}}
+	_, err := o.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// POST /admin/users/{userID}/erasure
+
+// DeleteByEmail removes the nominations of the email address.
+func (o *ownershipTransfer) DeleteByEmail(email string) error {
+	_, err := o.c.DeleteMany(context.Background(), bson.M{"email": email})
+	return err
+}
+
+// RenameNominatedBy replaces the email address of the owner who made the nominations.
+func (o *ownershipTransfer) RenameNominatedBy(email string, pseudonym string) error {
+	filter := bson.M{"nominatedBy": email}
+	update := bson.M{"$set": bson.M{"nominatedBy": pseudonym}}
+	_, err := o.c.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func (o *ownershipTransfer) DeleteByEntityID(entityID primitive.ObjectID) error {
+	_, err := o.c.DeleteMany(context.Background(), bson.M{"entityID": entityID})
+	return err
+}
@@ -0,0 +1,105 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type paymentRequest struct {
+	c *mongo.Collection
+}
+
+var PaymentRequest = &paymentRequest{}
+
+func (p *paymentRequest) Register(db *mongo.Database) {
+	p.c = db.Collection("paymentRequests")
+}
+
+func (p *paymentRequest) Create(req *types.PaymentRequest) (*types.PaymentRequest, error) {
+	filter := bson.M{"_id": bson.M{"$exists": false}}
+	update := bson.M{
+		"entityID":      req.EntityID,
+		"accountNumber": req.AccountNumber,
+		"entityName":    req.EntityName,
+		"amount":        req.Amount,
+		"description":   req.Description,
+		"singleUse":     req.SingleUse,
+		"expiresAt":     req.ExpiresAt,
+		"createdAt":     time.Now(),
+	}
+
+	result := p.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		bson.M{"$set": update},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	created := types.PaymentRequest{}
+	err := result.Decode(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (p *paymentRequest) FindByID(id primitive.ObjectID) (*types.PaymentRequest, error) {
+	paymentRequest := types.PaymentRequest{}
+	filter := bson.M{"_id": id}
+	err := p.c.FindOne(context.Background(), filter).Decode(&paymentRequest)
+	if err != nil {
+		return nil, errors.New("The specified payment request could not be found.")
+	}
+	return &paymentRequest, nil
+}
+
+// SetUsed marks a single-use payment request as used. It fails if the payment request has already been used.
+func (p *paymentRequest) SetUsed(id primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":    id,
+		"usedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{
+		"usedAt":    time.Now(),
+		"updatedAt": time.Now(),
+	}}
+	result, err := p.c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("The payment request has already been used.")
+	}
+	return nil
+}
+
+// UnsetUsed makes a single-use payment request available again.
+func (p *paymentRequest) UnsetUsed(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$unset": bson.M{"usedAt": ""},
+		"$set":   bson.M{"updatedAt": time.Now()},
+	}
+	_, err := p.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func (p *paymentRequest) RenameEntity(entityID primitive.ObjectID, name string) error {
+	filter := bson.M{"entityID": entityID}
+	update := bson.M{"$set": bson.M{"entityName": name, "updatedAt": time.Now()}}
+	_, err := p.c.UpdateMany(context.Background(), filter, update)
+	return err
+}
@@ -0,0 +1,215 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type refreshToken struct {
+	c *mongo.Collection
+}
+
+var RefreshToken = &refreshToken{}
+
+func (r *refreshToken) Register(db *mongo.Database) {
+	r.c = db.Collection("refreshTokens")
+}
+
+func (r *refreshToken) Create(token *types.RefreshToken) (*types.RefreshToken, error) {
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+	token.UpdatedAt = time.Now()
+	_, err := r.c.InsertOne(context.Background(), token)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *refreshToken) FindByID(id primitive.ObjectID) (*types.RefreshToken, error) {
+	token := types.RefreshToken{}
+	err := r.c.FindOne(context.Background(), bson.M{"_id": id}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// userFilter matches the active families of the user or the admin.
+func (r *refreshToken) userFilter(userID primitive.ObjectID, admin bool) bson.M {
+	filter := bson.M{
+		"userID":    userID,
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	// The admin flag is omitted for the families of users.
+	if admin {
+		filter["admin"] = true
+	} else {
+		filter["admin"] = bson.M{"$ne": true}
+	}
+	return filter
+}
+
+// FindByUserID returns the active sessions of the user, the most recently used first.
+func (r *refreshToken) FindByUserID(userID primitive.ObjectID, admin bool) ([]*types.RefreshToken, error) {
+	findOptions := options.Find().SetSort(bson.M{"lastSeenAt": -1})
+	cur, err := r.c.Find(context.Background(), r.userFilter(userID, admin), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	tokens := []*types.RefreshToken{}
+	for cur.Next(context.Background()) {
+		var elem types.RefreshToken
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+	return tokens, nil
+}
+
+// FindDeviceIDs returns the devices of every stored session of the user, including the expired and revoked ones.
+func (r *refreshToken) FindDeviceIDs(userID primitive.ObjectID, admin bool) ([]string, error) {
+	filter := bson.M{"userID": userID, "deviceID": bson.M{"$exists": true}}
+	if admin {
+		filter["admin"] = true
+	} else {
+		filter["admin"] = bson.M{"$ne": true}
+	}
+	values, err := r.c.Distinct(context.Background(), "deviceID", filter)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Rotate replaces the current token of an active family and keeps the old hash for the reuse detection.
+func (r *refreshToken) Rotate(oldHash string, newHash string, ip string) (*types.RefreshToken, error) {
+	filter := bson.M{
+		"tokenHash": oldHash,
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"tokenHash":  newHash,
+			"lastSeenAt": time.Now(),
+			"lastSeenIP": ip,
+			"updatedAt":  time.Now(),
+		},
+		"$push": bson.M{"usedHashes": oldHash},
+	}
+	result := r.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, errors.New("Refresh token is invalid.")
+	}
+	token := types.RefreshToken{}
+	err := result.Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByUsedHash returns the family a rotated token belonged to.
+func (r *refreshToken) FindByUsedHash(hash string) (*types.RefreshToken, error) {
+	token := types.RefreshToken{}
+	err := r.c.FindOne(context.Background(), bson.M{"usedHashes": hash}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshToken) Revoke(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now(), "updatedAt": time.Now()}}
+	_, err := r.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// RevokeByUserID revokes the active families of the user except the kept one and returns their IDs.
+func (r *refreshToken) RevokeByUserID(userID primitive.ObjectID, admin bool, keep primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter := r.userFilter(userID, admin)
+	if !keep.IsZero() {
+		filter["_id"] = bson.M{"$ne": keep}
+	}
+	return r.revoke(filter)
+}
+
+// RevokeByImpersonator revokes the active sessions the admin started to act as a user and returns their IDs.
+func (r *refreshToken) RevokeByImpersonator(adminID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter := bson.M{
+		"impersonatedBy": adminID,
+		"revokedAt":      bson.M{"$exists": false},
+		"expiresAt":      bson.M{"$gt": time.Now()},
+	}
+	return r.revoke(filter)
+}
+
+func (r *refreshToken) revoke(filter bson.M) ([]primitive.ObjectID, error) {
+	cur, err := r.c.Find(context.Background(), filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	ids := []primitive.ObjectID{}
+	for cur.Next(context.Background()) {
+		var elem types.RefreshToken
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, elem.ID)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now(), "updatedAt": time.Now()}}
+	_, err = r.c.UpdateMany(context.Background(), bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// DeleteExpired deletes the families which expired or were revoked before the time.
+func (r *refreshToken) DeleteExpired(before time.Time) (int64, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"expiresAt": bson.M{"$lt": before}},
+		{"revokedAt": bson.M{"$lt": before}},
+	}}
+	result, err := r.c.DeleteMany(context.Background(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
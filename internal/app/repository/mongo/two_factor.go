@@ -0,0 +1,103 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The two-factor settings are stored the same way in the users and in the adminUsers collections.
+
+func setPendingTwoFactorSecret(c *mongo.Collection, id primitive.ObjectID, secret string) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"twoFactor.pendingSecret": secret, "updatedAt": time.Now()}}
+	_, err := c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+func enableTwoFactor(c *mongo.Collection, id primitive.ObjectID, secret string, counter int64, recoveryCodes []string) error {
+	filter := bson.M{"_id": id, "twoFactor.pendingSecret": secret}
+	update := bson.M{"$set": bson.M{
+		"twoFactor": types.TwoFactor{
+			Secret:        secret,
+			EnabledAt:     time.Now(),
+			LastCounter:   counter,
+			RecoveryCodes: recoveryCodes,
+		},
+		"updatedAt": time.Now(),
+	}}
+	result, err := c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Two-factor authentication setup has not been started.")
+	}
+	return nil
+}
+
+func disableTwoFactor(c *mongo.Collection, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$set":   bson.M{"updatedAt": time.Now()},
+		"$unset": bson.M{"twoFactor": ""},
+	}
+	_, err := c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// useTwoFactorCounter records the time step of an accepted code. It fails when the time step
+// has already been used, which stops a code from being replayed.
+func useTwoFactorCounter(c *mongo.Collection, id primitive.ObjectID, counter int64) error {
+	filter := bson.M{
+		"_id": id,
+		"$or": []bson.M{
+			{"twoFactor.lastCounter": bson.M{"$lt": counter}},
+			{"twoFactor.lastCounter": bson.M{"$exists": false}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"twoFactor.lastCounter": counter}}
+	result, err := c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Invalid code.")
+	}
+	return nil
+}
+
+// useRecoveryCode removes the recovery code so it can only be used once.
+func useRecoveryCode(c *mongo.Collection, id primitive.ObjectID, hash string) error {
+	filter := bson.M{"_id": id, "twoFactor.recoveryCodes": hash}
+	update := bson.M{
+		"$set":  bson.M{"updatedAt": time.Now()},
+		"$pull": bson.M{"twoFactor.recoveryCodes": hash},
+	}
+	result, err := c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Invalid code.")
+	}
+	return nil
+}
+
+func setRecoveryCodes(c *mongo.Collection, id primitive.ObjectID, recoveryCodes []string) error {
+	filter := bson.M{"_id": id, "twoFactor.secret": bson.M{"$exists": true}}
+	update := bson.M{"$set": bson.M{"twoFactor.recoveryCodes": recoveryCodes, "updatedAt": time.Now()}}
+	result, err := c.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("Two-factor authentication is not enabled.")
+	}
+	return nil
+}
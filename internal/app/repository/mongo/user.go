@@ -181,7 +181,12 @@ func (u *user) FindOneAndUpdate(userID primitive.ObjectID, update *types.User) (
 
 func (u *user) UpdatePassword(user *types.User) error {
 	filter := bson.M{"_id": user.ID}
-	update := bson.M{"$set": bson.M{"password": user.Password, "updatedAt": time.Now()}}
+	update := bson.M{"$set": bson.M{
+		"password":          user.Password,
+		"passwordHistory":   user.PasswordHistory,
+		"passwordChangedAt": user.PasswordChangedAt,
+		"updatedAt":         time.Now(),
+	}}
 	_, err := u.c.UpdateOne(
 		context.Background(),
 		filter,
@@ -248,6 +253,8 @@ func (u *user) AdminFindOneAndUpdate(req *types.AdminUpdateUserReq) (*types.User
 	}
 	if req.Password != "" {
 		update["password"] = req.Password
+		update["passwordHistory"] = req.PasswordHistory
+		update["passwordChangedAt"] = time.Now()
 	}
 	if req.Entity != nil {
 		update["entities"] = util.ToObjectIDs(*req.Entity)
@@ -356,3 +363,168 @@ func (u *user) removeAssociatedEntity(userIDs []primitive.ObjectID, entityID pri
 	}
 	return nil
 }
+
+// POST /admin/users/{userID}/erasure
+
+// FindErasable also finds soft-deleted users since their personal data is still stored.
+func (u *user) FindErasable(id primitive.ObjectID) (*types.User, error) {
+	user := types.User{}
+	filter := bson.M{"_id": id, "erasedAt": bson.M{"$exists": false}}
+	err := u.c.FindOne(context.Background(), filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("The specified user could not be found or has already been erased.")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Erase replaces the personal data of the user with the pseudonym and soft-deletes the user.
+func (u *user) Erase(id primitive.ObjectID, pseudonym *types.User) (*types.User, error) {
+	filter := bson.M{"_id": id, "erasedAt": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set": bson.M{
+			"firstName": pseudonym.FirstName,
+			"lastName":  pseudonym.LastName,
+			"email":     pseudonym.Email,
+			"entities":  []primitive.ObjectID{},
+			"erasedAt":  time.Now(),
+			"updatedAt": time.Now(),
+		},
+		// Keeps the original deletion time of an already deleted user.
+		"$min": bson.M{"deletedAt": time.Now()},
+		"$unset": bson.M{
+			"password":        "",
+			"passwordHistory": "",
+			"telephone":       "",
+			"currentLoginIP":  "",
+			"lastLoginIP":     "",
+			"twoFactor":       "",
+		},
+	}
+
+	result := u.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	user := types.User{}
+	err := result.Decode(&user)
+	if err != nil {
+		return nil, result.Err()
+	}
+	return &user, nil
+}
+
+// RemoveFromEntities removes the user from the entities it still belongs to.
+func (u *user) RemoveFromEntities(user *types.User) error {
+	return Entity.removeAssociatedUser(user.Entities, user.ID)
+}
+
+// POST /admin/entities/{entityID}/merge
+
+func (u *user) replaceAssociatedEntity(oldEntityID primitive.ObjectID, newEntityID primitive.ObjectID) error {
+	filter := bson.M{"entities": oldEntityID}
+	updates := []bson.M{
+		bson.M{"$addToSet": bson.M{"entities": newEntityID}},
+		bson.M{"$set": bson.M{"updatedAt": time.Now()}},
+		// Last since the filter no longer matches afterwards.
+		bson.M{"$pull": bson.M{"entities": oldEntityID}},
+	}
+
+	var writes []mongo.WriteModel
+	for _, update := range updates {
+		model := mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update)
+		writes = append(writes, model)
+	}
+
+	_, err := u.c.BulkWrite(context.Background(), writes, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// POST /ownership-transfers/{token}/accept
+
+// MoveEntity associates the entity with one user and removes it from the others.
+func (u *user) MoveEntity(entityID primitive.ObjectID, toUserID primitive.ObjectID, fromUserIDs []primitive.ObjectID) error {
+	writes := []mongo.WriteModel{
+		mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": toUserID}).
+			SetUpdate(bson.M{"$addToSet": bson.M{"entities": entityID}, "$set": bson.M{"updatedAt": time.Now()}}),
+	}
+	if len(fromUserIDs) != 0 {
+		writes = append(writes, mongo.NewUpdateManyModel().
+			SetFilter(bson.M{"_id": bson.M{"$in": fromUserIDs}}).
+			SetUpdate(bson.M{"$pull": bson.M{"entities": entityID}, "$set": bson.M{"updatedAt": time.Now()}}))
+	}
+
+	_, err := u.c.BulkWrite(context.Background(), writes, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *user) SetPendingTwoFactorSecret(id primitive.ObjectID, secret string) error {
+	return setPendingTwoFactorSecret(u.c, id, secret)
+}
+
+func (u *user) EnableTwoFactor(id primitive.ObjectID, secret string, counter int64, recoveryCodes []string) error {
+	return enableTwoFactor(u.c, id, secret, counter, recoveryCodes)
+}
+
+func (u *user) DisableTwoFactor(id primitive.ObjectID) error {
+	return disableTwoFactor(u.c, id)
+}
+
+func (u *user) UseTwoFactorCounter(id primitive.ObjectID, counter int64) error {
+	return useTwoFactorCounter(u.c, id, counter)
+}
+
+func (u *user) UseRecoveryCode(id primitive.ObjectID, hash string) error {
+	return useRecoveryCode(u.c, id, hash)
+}
+
+func (u *user) SetRecoveryCodes(id primitive.ObjectID, recoveryCodes []string) error {
+	return setRecoveryCodes(u.c, id, recoveryCodes)
+}
+
+// SetEmailUnverified requires the user to confirm the email address again.
+func (u *user) SetEmailUnverified(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$set":   bson.M{"emailUnverified": true, "updatedAt": time.Now()},
+		"$unset": bson.M{"emailVerifiedAt": ""},
+	}
+	_, err := u.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// SetEmailVerified confirms the email address, it fails when the address has changed since the token was sent.
+func (u *user) SetEmailVerified(id primitive.ObjectID, email string) (*types.User, error) {
+	filter := bson.M{"_id": id, "email": email, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set":   bson.M{"emailVerifiedAt": time.Now(), "updatedAt": time.Now()},
+		"$unset": bson.M{"emailUnverified": ""},
+	}
+	result := u.c.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, errors.New("The email address has changed since the token was sent.")
+	}
+	user := types.User{}
+	err := result.Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
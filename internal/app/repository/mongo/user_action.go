@@ -2,10 +2,12 @@ package mongo
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -49,3 +51,78 @@ func (u *userAction) Create(a *types.UserAction) (*types.UserAction, error) {
 
 	return &created, nil
 }
+
+// GET /user/export
+
+func (u *userAction) FindByUserID(userID primitive.ObjectID) ([]*types.UserAction, error) {
+	findOptions := options.Find().SetSort(bson.M{"createdAt": 1})
+	cur, err := u.c.Find(context.Background(), bson.M{"userID": userID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []*types.UserAction
+	for cur.Next(context.Background()) {
+		var elem types.UserAction
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+
+	return actions, nil
+}
+
+// POST /admin/users/{userID}/erasure
+// POST /admin/entities/{entityID}/erasure
+
+// FindMentioning returns the actions of the user and the ones whose email or detail contains any of the terms.
+func (u *userAction) FindMentioning(userID primitive.ObjectID, terms []string) ([]*types.UserAction, error) {
+	or := []bson.M{}
+	if !userID.IsZero() {
+		or = append(or, bson.M{"userID": userID})
+	}
+	for _, term := range terms {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(term)}
+		or = append(or, bson.M{"email": pattern}, bson.M{"detail": pattern})
+	}
+	if len(or) == 0 {
+		return nil, nil
+	}
+
+	cur, err := u.c.Find(context.Background(), bson.M{"$or": or})
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []*types.UserAction
+	for cur.Next(context.Background()) {
+		var elem types.UserAction
+		err := cur.Decode(&elem)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, &elem)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	cur.Close(context.Background())
+
+	return actions, nil
+}
+
+func (u *userAction) Anonymize(a *types.UserAction) error {
+	filter := bson.M{"_id": a.ID}
+	update := bson.M{"$set": bson.M{
+		"email":  a.Email,
+		"detail": a.Detail,
+	}}
+	_, err := u.c.UpdateOne(context.Background(), filter, update)
+	return err
+}
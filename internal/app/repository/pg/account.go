@@ -40,6 +40,22 @@ func (a *account) FindByAccountNumber(accountNumber string) (*types.Account, err
 	return &result, nil
 }
 
+// findForUpdate locks the account until the transaction ends, its balance cannot change in the meantime.
+func (a *account) findForUpdate(tx *gorm.DB, accountNumber string) (*types.Account, error) {
+	var result types.Account
+	err := tx.Raw(`
+		SELECT id, account_number, balance
+		FROM accounts
+		WHERE deleted_at IS NULL AND account_number = ?
+		LIMIT 1
+		FOR UPDATE
+	`, accountNumber).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 func (a *account) ifAccountExisted(db *gorm.DB, accountNumber string) bool {
 	var result types.Account
 	return !db.Raw(`
@@ -83,22 +99,22 @@ func (a *account) Create() (*types.Account, error) {
 
 func (a *account) Delete(accountNumber string) error {
 	tx := db.Begin()
+	err := a.delete(tx, accountNumber)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
 
+func (a *account) delete(tx *gorm.DB, accountNumber string) error {
 	err := tx.Exec(`
 		UPDATE accounts
 		SET deleted_at = ?, updated_at = ?
 		WHERE deleted_at IS NULL AND account_number = ?
 	`, time.Now(), time.Now(), accountNumber).Error
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
-
-	err = BalanceLimit.delete(tx, accountNumber)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	return tx.Commit().Error
+	return BalanceLimit.delete(tx, accountNumber)
 }
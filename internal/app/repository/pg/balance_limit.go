@@ -29,7 +29,7 @@ func (b *balanceLimit) FindByAccountNumber(accountNumber string) (*types.Balance
 	var result types.BalanceLimit
 
 	err := db.Raw(`
-		SELECT account_number, max_pos_bal, max_neg_bal
+		SELECT account_number, max_pos_bal, max_neg_bal, max_daily_out_amount, max_monthly_out_amount, max_daily_out_count
 		FROM balance_limits
 		WHERE deleted_at IS NULL AND account_number = ?
 		LIMIT 1
@@ -51,6 +51,16 @@ func (b *balanceLimit) AdminUpdate(req *types.AdminUpdateEntityReq) error {
 	if req.MaxNegBal != nil {
 		update["max_neg_bal"] = *req.MaxNegBal
 	}
+	// NULL restores the network default of a velocity limit.
+	if req.MaxDailyOutAmount.Set {
+		update["max_daily_out_amount"] = req.MaxDailyOutAmount.Value
+	}
+	if req.MaxMonthlyOutAmount.Set {
+		update["max_monthly_out_amount"] = req.MaxMonthlyOutAmount.Value
+	}
+	if req.MaxDailyOutCount.Set {
+		update["max_daily_out_count"] = req.MaxDailyOutCount.Value
+	}
 	err := db.Table("balance_limits").Where("deleted_at IS NULL AND account_number = ?", req.OriginEntity.AccountNumber).Updates(update).Error
 	if err != nil {
 		return err
@@ -1,6 +1,9 @@
 package pg
 
 import (
+	"errors"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/global/constant"
@@ -10,6 +13,12 @@ import (
 	"github.com/segmentio/ksuid"
 )
 
+var (
+	ErrNothingToWriteOff = errors.New("Only a negative balance can be written off.")
+	ErrPendingTransfers  = errors.New("The balance cannot be written off while the account has pending transfers.")
+	ErrMergeExceedsLimit = errors.New("The balance of the kept entity would exceed its limits, please raise its limits before merging.")
+)
+
 type journal struct{}
 
 var Journal = &journal{}
@@ -96,6 +105,25 @@ func (t *journal) Search(req *types.SearchTransferReq) (*types.SearchTransferRes
 	return found, nil
 }
 
+// FindOutgoingSince returns the total amount and the number of completed outgoing transfers since the given time.
+func (t *journal) FindOutgoingSince(accountNumber string, since time.Time) (float64, int, error) {
+	var result struct {
+		Amount float64
+		Count  int
+	}
+
+	err := db.Raw(`
+		SELECT COALESCE(SUM(amount), 0) AS amount, COUNT(*) AS count
+		FROM journals
+		WHERE deleted_at IS NULL AND from_account_number = ? AND type = ? AND status = ? AND completed_at >= ?
+	`, accountNumber, constant.TransferType.Transfer, constant.Transfer.Completed, since).Scan(&result).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return result.Amount, result.Count, nil
+}
+
 func (t *journal) FindByID(transferID string) (*types.Journal, error) {
 	var result types.Journal
 
@@ -227,6 +255,67 @@ func (t *journal) Create(req *types.AdminTransferReq) (*types.Journal, error) {
 	return updated, tx.Commit().Error
 }
 
+// POST /admin/entities/{entityID}/write-off
+
+// WriteOff reads the balance of the account while it is locked, req.Balance and req.Amount are
+// updated to the amount written off so a transfer accepted in the meantime cannot leave a residue.
+func (t *journal) WriteOff(req *types.AdminWriteOffReq) (*types.Journal, error) {
+	tx := db.Begin()
+	journal, err := t.writeOff(tx, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return journal, tx.Commit().Error
+}
+
+func (t *journal) writeOff(tx *gorm.DB, req *types.AdminWriteOffReq) (*types.Journal, error) {
+	account, err := Account.findForUpdate(tx, req.Entity.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if account.Balance >= 0 {
+		return nil, ErrNothingToWriteOff
+	}
+	pending, err := t.countPending(tx, req.Entity.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, ErrPendingTransfers
+	}
+	req.Balance = account.Balance
+	req.Amount = math.Abs(account.Balance)
+
+	journal, err := t.propose(tx, &types.TransferReq{
+		FromAccountNumber: req.ReserveEntity.AccountNumber,
+		FromEntityName:    req.ReserveEntity.Name,
+		ToAccountNumber:   req.Entity.AccountNumber,
+		ToEntityName:      req.Entity.Name,
+		Amount:            req.Amount,
+		Description:       req.Reason,
+		TransferType:      constant.TransferType.AdminWriteOff,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t.accept(tx, journal)
+}
+
+// countPending returns the number of transfers of the account which have not been accepted yet.
+func (t *journal) countPending(tx *gorm.DB, accountNumber string) (int, error) {
+	var count int
+	err := tx.Raw(`
+		SELECT COUNT(*)
+		FROM journals
+		WHERE deleted_at IS NULL AND (from_account_number = ? OR to_account_number = ?) AND status = ?
+	`, accountNumber, accountNumber, constant.Transfer.Initiated).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // GET /admin/transfers
 
 func (t *journal) FindByIDs(transferIDs []string) ([]*types.Journal, error) {
@@ -240,6 +329,22 @@ func (t *journal) FindByIDs(transferIDs []string) ([]*types.Journal, error) {
 	return journals, nil
 }
 
+// GET /user/export
+
+// FindByAccountNumber returns all the transfers of the account, oldest first.
+func (t *journal) FindByAccountNumber(accountNumber string) ([]*types.Journal, error) {
+	var journals []*types.Journal
+
+	err := db.Where("deleted_at IS NULL AND (from_account_number = ? OR to_account_number = ?)", accountNumber, accountNumber).
+		Order("created_at").
+		Find(&journals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return journals, nil
+}
+
 // GET /admin/entities/{entityID}
 
 func (t *journal) GetPending(accountNumber string) ([]*types.Journal, error) {
@@ -261,3 +366,162 @@ func (t *journal) GetPending(accountNumber string) ([]*types.Journal, error) {
 
 	return journals, nil
 }
+
+// POST /admin/entities/{entityID}/erasure
+
+// RenameEntity replaces the entity name stored in the transfers of the account, the amounts are left untouched.
+func (t *journal) RenameEntity(accountNumber string, name string) error {
+	tx := db.Begin()
+
+	err := tx.Exec(`
+		UPDATE journals
+		SET from_entity_name = ?, updated_at = ?
+		WHERE from_account_number = ?
+	`, name, time.Now(), accountNumber).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Exec(`
+		UPDATE journals
+		SET to_entity_name = ?, updated_at = ?
+		WHERE to_account_number = ?
+	`, name, time.Now(), accountNumber).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// POST /admin/entities/{entityID}/merge
+
+// Merge moves the balance of the merged account into the kept one with a merge journal, points the pending
+// transfers of the merged account to the kept one and closes the merged account, all in one transaction.
+// req.Balance is updated to the balance moved. It returns the merge journal, nil for a zero balance,
+// and the pending transfers it changed.
+func (t *journal) Merge(req *types.AdminMergeEntityReq) (*types.Journal, []*types.Journal, error) {
+	tx := db.Begin()
+	merged, pending, err := t.merge(tx, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	return merged, pending, tx.Commit().Error
+}
+
+func (t *journal) merge(tx *gorm.DB, req *types.AdminMergeEntityReq) (*types.Journal, []*types.Journal, error) {
+	// The balances are read while both accounts are locked so the closed account is left with nothing.
+	// They are always locked in the same order so two merges of the same accounts cannot deadlock.
+	numbers := []string{req.Entity.AccountNumber, req.IntoEntity.AccountNumber}
+	sort.Strings(numbers)
+	accounts := map[string]*types.Account{}
+	for _, number := range numbers {
+		account, err := Account.findForUpdate(tx, number)
+		if err != nil {
+			return nil, nil, err
+		}
+		accounts[number] = account
+	}
+	req.Balance = accounts[req.Entity.AccountNumber].Balance
+
+	// The kept account has to stay within its limits like it would for a transfer.
+	limit, err := BalanceLimit.FindByAccountNumber(req.IntoEntity.AccountNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	balance := accounts[req.IntoEntity.AccountNumber].Balance + req.Balance
+	if balance > limit.MaxPosBal || balance < -math.Abs(limit.MaxNegBal) {
+		return nil, nil, ErrMergeExceedsLimit
+	}
+
+	from, to := req.Entity, req.IntoEntity
+	// A negative balance is settled the other way around.
+	if req.Balance < 0 {
+		from, to = req.IntoEntity, req.Entity
+	}
+
+	var merged *types.Journal
+	if req.Balance != 0 {
+		proposed, err := t.propose(tx, &types.TransferReq{
+			FromAccountNumber: from.AccountNumber,
+			FromEntityName:    from.Name,
+			ToAccountNumber:   to.AccountNumber,
+			ToEntityName:      to.Name,
+			Amount:            math.Abs(req.Balance),
+			Description:       "Merge of account " + req.Entity.AccountNumber + " into account " + req.IntoEntity.AccountNumber,
+			TransferType:      constant.TransferType.Merge,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		merged, err = t.accept(tx, proposed)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var pending []*types.Journal
+	err = tx.Raw(`
+		SELECT *
+		FROM journals
+		WHERE deleted_at IS NULL AND (from_account_number = ? OR to_account_number = ?) AND status = ? ORDER BY created_at
+	`, req.Entity.AccountNumber, req.Entity.AccountNumber, constant.Transfer.Initiated).Scan(&pending).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := []*types.Journal{}
+	for _, j := range pending {
+		// A transfer between the two entities would become a transfer to itself.
+		if j.FromAccountNumber == req.IntoEntity.AccountNumber || j.ToAccountNumber == req.IntoEntity.AccountNumber {
+			err = tx.Exec(`
+				UPDATE journals
+				SET status = ?, cancellation_reason = ?, updated_at = ?
+				WHERE transfer_id = ?
+			`, constant.Transfer.Cancelled, "The entities of the transfer have been merged.", time.Now(), j.TransferID).Error
+		} else {
+			err = tx.Exec(`
+				UPDATE journals
+				SET
+					initiated_by = CASE WHEN initiated_by = ? THEN ? ELSE initiated_by END,
+					from_account_number = CASE WHEN from_account_number = ? THEN ? ELSE from_account_number END,
+					from_entity_name = CASE WHEN from_account_number = ? THEN ? ELSE from_entity_name END,
+					to_account_number = CASE WHEN to_account_number = ? THEN ? ELSE to_account_number END,
+					to_entity_name = CASE WHEN to_account_number = ? THEN ? ELSE to_entity_name END,
+					updated_at = ?
+				WHERE transfer_id = ?
+			`,
+				req.Entity.AccountNumber, req.IntoEntity.AccountNumber,
+				req.Entity.AccountNumber, req.IntoEntity.AccountNumber,
+				req.Entity.AccountNumber, req.IntoEntity.Name,
+				req.Entity.AccountNumber, req.IntoEntity.AccountNumber,
+				req.Entity.AccountNumber, req.IntoEntity.Name,
+				time.Now(), j.TransferID,
+			).Error
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var u types.Journal
+		err = tx.Raw(`
+			SELECT *
+			FROM journals
+			WHERE transfer_id = ?
+		`, j.TransferID).Scan(&u).Error
+		if err != nil {
+			return nil, nil, err
+		}
+		updated = append(updated, &u)
+	}
+
+	err = Account.delete(tx, req.Entity.AccountNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return merged, updated, nil
+}
@@ -0,0 +1,141 @@
+package pg
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/internal/app/types"
+	"github.com/jinzhu/gorm"
+	"github.com/segmentio/ksuid"
+)
+
+type ledger struct{}
+
+var Ledger = &ledger{}
+
+// POST /admin/ledger/accounts
+
+func (l *ledger) CreateAccount(req *types.AdminCreateLedgerAccountReq) (*types.LedgerAccount, error) {
+	account := &types.LedgerAccount{
+		Code:        req.Code,
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: req.Description,
+	}
+	err := db.Create(account).Error
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// GET /admin/ledger/accounts
+
+func (l *ledger) FindAccounts() ([]*types.LedgerAccount, error) {
+	var result []*types.LedgerAccount
+	err := db.Raw(`
+		SELECT *
+		FROM ledger_accounts
+		WHERE deleted_at IS NULL
+		ORDER BY code
+	`).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (l *ledger) FindAccountByCode(code string) (*types.LedgerAccount, error) {
+	var result types.LedgerAccount
+	err := db.Raw(`
+		SELECT *
+		FROM ledger_accounts
+		WHERE deleted_at IS NULL AND code = ?
+		LIMIT 1
+	`, code).Scan(&result).Error
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New("Ledger account " + code + " could not be found.")
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// POST /admin/ledger/journals
+
+func (l *ledger) CreateJournal(req *types.AdminCreateLedgerJournalReq) (*types.LedgerJournal, error) {
+	tx := db.Begin()
+	journal, err := l.createJournal(tx, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return journal, tx.Commit().Error
+}
+
+func (l *ledger) createJournal(tx *gorm.DB, req *types.AdminCreateLedgerJournalReq) (*types.LedgerJournal, error) {
+	journal := &types.LedgerJournal{
+		EntryID:     ksuid.New().String(),
+		Type:        req.Type,
+		Description: req.Description,
+		PostedBy:    req.PostedBy,
+		Date:        req.Date,
+	}
+	err := tx.Create(journal).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range req.Postings {
+		posting := types.LedgerPosting{
+			LedgerJournalID: journal.ID,
+			AccountCode:     p.AccountCode,
+			Amount:          p.Amount,
+		}
+		err := tx.Create(&posting).Error
+		if err != nil {
+			return nil, err
+		}
+		journal.LedgerPostings = append(journal.LedgerPostings, posting)
+	}
+	return journal, nil
+}
+
+// GET /admin/ledger/trial-balance
+
+func (l *ledger) TrialBalance(date time.Time) ([]*types.LedgerAccountBalance, error) {
+	var result []*types.LedgerAccountBalance
+	err := db.Raw(`
+		SELECT a.code, a.name, a.type, COALESCE(SUM(p.amount), 0) AS balance
+		FROM ledger_accounts a
+		LEFT JOIN ledger_postings p ON p.account_code = a.code AND p.deleted_at IS NULL
+			AND p.ledger_journal_id IN (SELECT id FROM ledger_journals WHERE deleted_at IS NULL AND date <= ?)
+		WHERE a.deleted_at IS NULL
+		GROUP BY a.code, a.name, a.type
+		ORDER BY a.code
+	`, date).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GET /admin/ledger/income-statement
+
+func (l *ledger) IncomeStatement(from time.Time, to time.Time) ([]*types.LedgerAccountBalance, error) {
+	var result []*types.LedgerAccountBalance
+	err := db.Raw(`
+		SELECT a.code, a.name, a.type, COALESCE(SUM(p.amount), 0) AS balance
+		FROM ledger_accounts a
+		LEFT JOIN ledger_postings p ON p.account_code = a.code AND p.deleted_at IS NULL
+			AND p.ledger_journal_id IN (SELECT id FROM ledger_journals WHERE deleted_at IS NULL AND date >= ? AND date <= ?)
+		WHERE a.deleted_at IS NULL AND a.type IN (?)
+		GROUP BY a.code, a.name, a.type
+		ORDER BY a.code
+	`, from, to, []string{constant.LedgerAccountType.Income, constant.LedgerAccountType.Expense}).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -68,10 +68,55 @@ func autoMigrate(db *gorm.DB) {
 		&types.BalanceLimit{},
 		&types.Journal{},
 		&types.Posting{},
+		&types.LedgerAccount{},
+		&types.LedgerJournal{},
+		&types.LedgerPosting{},
 	).Error
 	if err != nil {
 		panic(err)
 	}
+	err = createLedgerConstraints(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// createLedgerConstraints makes sure every general ledger journal is balanced when the transaction commits.
+func createLedgerConstraints(db *gorm.DB) error {
+	err := db.Exec(`
+		CREATE OR REPLACE FUNCTION check_ledger_journal_balanced() RETURNS trigger AS $$
+		DECLARE
+			journal_id integer;
+			total numeric;
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				journal_id := OLD.ledger_journal_id;
+			ELSE
+				journal_id := NEW.ledger_journal_id;
+			END IF;
+			SELECT COALESCE(SUM(amount), 0) INTO total
+			FROM ledger_postings
+			WHERE deleted_at IS NULL AND ledger_journal_id = journal_id;
+			IF total <> 0 THEN
+				RAISE EXCEPTION 'ledger journal % is not balanced', journal_id;
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql
+	`).Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec(`DROP TRIGGER IF EXISTS ledger_postings_balanced ON ledger_postings`).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec(`
+		CREATE CONSTRAINT TRIGGER ledger_postings_balanced
+		AFTER INSERT OR UPDATE OR DELETE ON ledger_postings
+		DEFERRABLE INITIALLY DEFERRED
+		FOR EACH ROW EXECUTE PROCEDURE check_ledger_journal_balanced()
+	`).Error
 }
 
 // For seed/migration/restore data
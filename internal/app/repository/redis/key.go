@@ -3,4 +3,8 @@ package redis
 const (
 	Ratelimiting  = "ratelimiting"
 	LoginAttempts = "loginAttempts"
+	// RevokedSessions holds the revoked sessions until the access tokens issued for them expire.
+	RevokedSessions = "revokedSessions"
+	// TwoFactorChallenges holds the logins waiting for a two-factor code.
+	TwoFactorChallenges = "twoFactorChallenges"
 )
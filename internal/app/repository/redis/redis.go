@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"log"
+	"math/rand"
 	"strconv"
 	"time"
 
@@ -17,7 +18,6 @@ import (
 var (
 	ctx                  = context.Background()
 	client               *redis.Client
-	rateLimitingDuration time.Duration
 	loginAttemptsTimeout time.Duration
 )
 
@@ -30,9 +30,6 @@ func init() {
 	password := viper.GetString("redis.password")
 
 	// Get other configuration values,
-	rateLimitingDuration = viper.GetDuration(
-		"rate_limiting.duration",
-	) * time.Minute
 	loginAttemptsTimeout = viper.GetDuration(
 		"login_attempts.timeout",
 	) * time.Second
@@ -58,33 +55,41 @@ func waitForConnection() {
 	}
 }
 
-// GetRequestCount returns the request count for a given IP address.
-func GetRequestCount(ip string) int {
-	key := Ratelimiting + ":" + ip
-	val, err := client.Get(ctx, key).Result()
-	if err != nil {
-		return 0
-	}
-	count, _ := strconv.Atoi(val)
-	return count
-}
-
-func IncRequestCount(ip string) error {
-	key := Ratelimiting + ":" + ip
-	_, err := client.Get(ctx, key).Result()
-	if err == redis.Nil {
-		// If the key does not exist, set it with a value of 1
-		err = client.Set(
-			ctx, key, 1, rateLimitingDuration,
-		).Err()
-	} else {
-		err = client.Incr(ctx, key).Err()
-	}
-
+// slidingWindow records the request in the window of the key unless the limit has been reached.
+// It returns whether the request is allowed, the remaining requests and the milliseconds until
+// the oldest request leaves the window.
+var slidingWindow = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], 0, now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	redis.call("PEXPIRE", KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+local reset = window
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, limit - count, reset}
+`)
+
+// RateLimit counts the request of the identity against the sliding window limit.
+func RateLimit(identity string, limit int, window time.Duration) (bool, int, time.Duration, error) {
+	key := Ratelimiting + ":" + identity
+	now := time.Now()
+	member := strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.FormatInt(rand.Int63(), 36)
+	result, err := slidingWindow.Run(ctx, client, []string{key}, now.UnixMilli(), window.Milliseconds(), limit, member).Int64Slice()
 	if err != nil {
-		l.Logger.Error("[ERROR] redis IncRequestCount failed:", zap.Error(err))
+		l.Logger.Error("[ERROR] redis RateLimit failed:", zap.Error(err))
+		return false, 0, 0, err
 	}
-	return err
+	return result[0] == 1, int(result[1]), time.Duration(result[2]) * time.Millisecond, nil
 }
 
 // GetLoginAttempts returns the login attempts count for a given email address.
@@ -127,3 +132,53 @@ func ResetLoginAttempts(email string) {
 		)
 	}
 }
+
+// RevokeSession marks the session as revoked for the given duration.
+func RevokeSession(sessionID string, expiration time.Duration) error {
+	key := RevokedSessions + ":" + sessionID
+	err := client.Set(ctx, key, 1, expiration).Err()
+	if err != nil {
+		l.Logger.Error("[ERROR] redis RevokeSession failed:", zap.Error(err))
+	}
+	return err
+}
+
+// IsSessionRevoked checks whether the session has been revoked.
+func IsSessionRevoked(sessionID string) (bool, error) {
+	key := RevokedSessions + ":" + sessionID
+	_, err := client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetTwoFactorChallenge stores the subject of a login waiting for a two-factor code.
+func SetTwoFactorChallenge(token string, subject string, expiration time.Duration) error {
+	key := TwoFactorChallenges + ":" + token
+	err := client.Set(ctx, key, subject, expiration).Err()
+	if err != nil {
+		l.Logger.Error("[ERROR] redis SetTwoFactorChallenge failed:", zap.Error(err))
+	}
+	return err
+}
+
+// GetTwoFactorChallenge returns the subject of a login waiting for a two-factor code.
+func GetTwoFactorChallenge(token string) (string, error) {
+	key := TwoFactorChallenges + ":" + token
+	return client.Get(ctx, key).Result()
+}
+
+func DeleteTwoFactorChallenge(token string) {
+	key := TwoFactorChallenges + ":" + token
+	_, err := client.Del(ctx, key).Result()
+	if err != nil {
+		l.Logger.Error(
+			"[ERROR] redis DeleteTwoFactorChallenge failed:",
+			zap.Error(err),
+		)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -16,7 +18,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/util"
-	"github.com/ic3network/mccs-alpha-api/util/bcrypt"
+	passwordutil "github.com/ic3network/mccs-alpha-api/util/password"
 	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -128,6 +130,110 @@ func (req *LoginReq) validate() []error {
 	return errs
 }
 
+// POST /login/2fa
+// POST /admin/login/2fa
+
+func NewTwoFactorLoginReq(r *http.Request) (*TwoFactorLoginReq, []error) {
+	var req TwoFactorLoginReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	errs := req.validateToken()
+	if req.Code == "" {
+		errs = append(errs, errors.New("Code is missing."))
+	}
+	return &req, errs
+}
+
+// POST /admin/login/2fa/setup
+
+func NewTwoFactorLoginSetupReq(r *http.Request) (*TwoFactorLoginReq, []error) {
+	var req TwoFactorLoginReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return &req, req.validateToken()
+}
+
+type TwoFactorLoginReq struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+	Code           string `json:"code"`
+}
+
+func (req *TwoFactorLoginReq) validateToken() []error {
+	errs := []error{}
+	if req.TwoFactorToken == "" {
+		errs = append(errs, errors.New("Two-factor token is missing."))
+	}
+	return errs
+}
+
+// POST /user/2fa/enable
+// POST /user/2fa/disable
+// POST /user/2fa/recovery-codes
+// POST /admin/2fa/recovery-codes
+
+func NewTwoFactorCodeReq(r *http.Request) (*TwoFactorCodeReq, []error) {
+	var req TwoFactorCodeReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	errs := []error{}
+	if req.Code == "" {
+		errs = append(errs, errors.New("Code is missing."))
+	}
+	return &req, errs
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+// POST /refresh
+// POST /admin/refresh
+
+func NewRefreshTokenReq(r *http.Request) (*RefreshTokenReq, []error) {
+	var req RefreshTokenReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return &req, req.validate()
+}
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (req *RefreshTokenReq) validate() []error {
+	errs := []error{}
+	if req.RefreshToken == "" {
+		errs = append(errs, errors.New("Refresh token is missing."))
+	}
+	return errs
+}
+
+// NewDevice describes the client which sent the login or refresh request.
+func NewDevice(r *http.Request) *Device {
+	userAgent := r.UserAgent()
+	if len(userAgent) > 500 {
+		userAgent = userAgent[:500]
+	}
+	return &Device{
+		ID:        util.DeviceID(userAgent),
+		Name:      util.DeviceName(userAgent),
+		UserAgent: userAgent,
+		IP:        util.IPAddress(r),
+	}
+}
+
 type ResetPasswordReq struct {
 	Password string `json:"password"`
 }
@@ -231,6 +337,10 @@ func NewUpdateUserEntityReq(j UpdateUserEntityJSON, originEntity *Entity) (*Upda
 		// flags
 		ShowTagsMatchedSinceLastLogin:      j.ShowTagsMatchedSinceLastLogin,
 		ReceiveDailyMatchNotificationEmail: j.ReceiveDailyMatchNotificationEmail,
+		BalanceAlerts:                      j.BalanceAlerts,
+	}
+	if j.Location != nil {
+		req.Location = &GeoLocation{Lat: j.Location.Lat, Lon: j.Location.Lon, Manual: true}
 	}
 
 	return &req, nil
@@ -262,6 +372,10 @@ type UpdateUserEntityReq struct {
 	// flags
 	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
 	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
+	// alerts
+	BalanceAlerts *BalanceAlerts
+	// Geo
+	Location *GeoLocation
 }
 
 type UpdateUserEntityJSON struct {
@@ -284,6 +398,10 @@ type UpdateUserEntityJSON struct {
 	// flags
 	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
 	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
+	// alerts
+	BalanceAlerts *BalanceAlerts `json:"balanceAlerts"`
+	// Geo
+	Location *GeoLocationJSON `json:"location"`
 	// Not allow to change
 	ID     string `json:"id"`
 	Status string `json:"status"`
@@ -321,10 +439,27 @@ func (req *UpdateUserEntityJSON) validate() []error {
 	if req.Wants != nil {
 		errs = append(errs, validateTags(*req.Wants)...)
 	}
+	if req.BalanceAlerts != nil {
+		errs = append(errs, req.BalanceAlerts.Validate()...)
+	}
+	if req.Location != nil {
+		errs = append(errs, req.Location.validate()...)
+	}
 
 	return errs
 }
 
+// GeoLocationJSON sets the coordinates of the entity manually.
+type GeoLocationJSON struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func (j *GeoLocationJSON) validate() []error {
+	location := GeoLocation{Lat: j.Lat, Lon: j.Lon}
+	return location.Validate()
+}
+
 func NewAddToFavoriteReq(r *http.Request) (*AddToFavoriteReq, []error) {
 	var req AddToFavoriteReq
 	decoder := json.NewDecoder(r.Body)
@@ -373,8 +508,15 @@ func validateTags(tags []string) []error {
 	return errs
 }
 
+// bcrypt cannot hash passwords longer than 72 bytes.
+const passwordMaxBytes = 72
+
 func validatePassword(password string) []error {
-	minLen, hasLetter, hasNumber, hasSpecial := viper.GetInt("validate.password.minLen"), false, false, false
+	minLen, maxLen := viper.GetInt("validate.password.minLen"), viper.GetInt("validate.password.maxLen")
+	if maxLen <= 0 || maxLen > passwordMaxBytes {
+		maxLen = passwordMaxBytes
+	}
+	hasLetter, hasUpper, hasLower, hasNumber, hasSpecial := false, false, false, false, false
 
 	errs := []error{}
 
@@ -382,6 +524,12 @@ func validatePassword(password string) []error {
 		switch {
 		case unicode.IsLetter(ch):
 			hasLetter = true
+			if unicode.IsUpper(ch) {
+				hasUpper = true
+			}
+			if unicode.IsLower(ch) {
+				hasLower = true
+			}
 		case unicode.IsNumber(ch):
 			hasNumber = true
 		case unicode.IsPunct(ch) || unicode.IsSymbol(ch):
@@ -393,22 +541,45 @@ func validatePassword(password string) []error {
 		errs = append(errs, errors.New("Password is missing."))
 	} else if len(password) < minLen {
 		errs = append(errs, errors.New("Password must be at least "+strconv.Itoa(minLen)+" characters long."))
-	} else if len(password) > 100 {
-		errs = append(errs, errors.New("Password cannot exceed 100 characters."))
+	} else if len(password) > maxLen {
+		errs = append(errs, errors.New("Password cannot exceed "+strconv.Itoa(maxLen)+" characters."))
 	}
-	if !hasLetter {
+	if passwordRule("requireLetter") && !hasLetter {
 		errs = append(errs, errors.New("Password must have at least one letter."))
 	}
-	if !hasNumber {
+	if passwordRule("requireUpper") && !hasUpper {
+		errs = append(errs, errors.New("Password must have at least one uppercase letter."))
+	}
+	if passwordRule("requireLower") && !hasLower {
+		errs = append(errs, errors.New("Password must have at least one lowercase letter."))
+	}
+	if passwordRule("requireNumber") && !hasNumber {
 		errs = append(errs, errors.New("Password must have at least one number."))
 	}
-	if !hasSpecial {
+	if passwordRule("requireSpecial") && !hasSpecial {
 		errs = append(errs, errors.New("Password must have at least one special character."))
 	}
+	if password != "" && passwordRule("rejectCommon") && passwordutil.IsCommon(password) {
+		errs = append(errs, errors.New("Password is too common, please choose a different one."))
+	}
 
 	return errs
 }
 
+// passwordRule returns whether the password policy rule is enabled.
+// The letter, number and special character rules predate the configuration and are on unless turned off.
+func passwordRule(name string) bool {
+	key := "validate.password." + name
+	if !viper.IsSet(key) {
+		switch name {
+		case "requireLetter", "requireNumber", "requireSpecial", "rejectCommon":
+			return true
+		}
+		return false
+	}
+	return viper.GetBool(key)
+}
+
 func NewEmailReq(r *http.Request) (*EmailReq, []error) {
 	var req EmailReq
 	decoder := json.NewDecoder(r.Body)
@@ -443,6 +614,272 @@ func (req *EmailReq) validate() []error {
 	return errs
 }
 
+// PATCH /user/entities/{entityID}/members/{userID}
+
+func NewUpdateEntityMemberReq(j UpdateEntityMemberJSON, entity *Entity, userID string) (*UpdateEntityMemberReq, []error) {
+	req := &UpdateEntityMemberReq{
+		Entity: entity,
+		UserID: util.ToObjectID(userID),
+		Role:   strings.ToLower(strings.TrimSpace(j.Role)),
+	}
+	return req, req.validate()
+}
+
+type UpdateEntityMemberJSON struct {
+	Role string `json:"role"`
+}
+
+type UpdateEntityMemberReq struct {
+	Entity *Entity
+	UserID primitive.ObjectID
+	Role   string
+}
+
+func (req *UpdateEntityMemberReq) validate() []error {
+	errs := []error{}
+	if req.UserID.IsZero() {
+		errs = append(errs, errors.New("Please enter a valid user id."))
+	}
+	if _, ok := constant.EntityRoleRank[req.Role]; !ok {
+		errs = append(errs, errors.New("Role should be one of owner, bookkeeper, staff or viewer."))
+	}
+	return errs
+}
+
+// DELETE /user/entities/{entityID}/members/{userID}
+
+func NewEntityMemberReq(entity *Entity, userID string) (*EntityMemberReq, []error) {
+	req := &EntityMemberReq{
+		Entity: entity,
+		UserID: util.ToObjectID(userID),
+	}
+	return req, req.validate()
+}
+
+type EntityMemberReq struct {
+	Entity *Entity
+	UserID primitive.ObjectID
+}
+
+func (req *EntityMemberReq) validate() []error {
+	errs := []error{}
+	if req.UserID.IsZero() {
+		errs = append(errs, errors.New("Please enter a valid user id."))
+	}
+	return errs
+}
+
+// PUT /user/entities/{entityID}/logo
+// POST /user/entities/{entityID}/images
+
+// NewEntityImageReq reads the image from the "image" field of the multipart form.
+func NewEntityImageReq(r *http.Request, entity *Entity) (*EntityImageReq, []error) {
+	maxSize := viper.GetInt64("images.max_size")
+	sizeErr := errors.New("The image cannot be larger than " + strconv.FormatInt(maxSize/1024/1024, 10) + " MB.")
+
+	err := r.ParseMultipartForm(maxSize)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, []error{sizeErr}
+		}
+		return nil, []error{errors.New("Please upload the image as multipart/form-data.")}
+	}
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		return nil, []error{errors.New("Please provide an image.")}
+	}
+	defer file.Close()
+
+	// Read one byte more than allowed to detect files over the limit.
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, []error{err}
+	}
+	if int64(len(data)) > maxSize {
+		return nil, []error{sizeErr}
+	}
+
+	return &EntityImageReq{Entity: entity, Data: data}, nil
+}
+
+type EntityImageReq struct {
+	Entity *Entity
+	Data   []byte
+}
+
+// POST /user/entities/{entityID}/invitations
+
+func NewInvitationReq(j InvitationJSON, entity *Entity) (*InvitationReq, []error) {
+	req := &InvitationReq{
+		Entity: entity,
+		Email:  strings.ToLower(strings.TrimSpace(j.Email)),
+		Role:   strings.ToLower(strings.TrimSpace(j.Role)),
+	}
+	return req, req.validate()
+}
+
+type InvitationJSON struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type InvitationReq struct {
+	Entity *Entity
+	Email  string
+	Role   string
+}
+
+func (req *InvitationReq) validate() []error {
+	errs := []error{}
+	errs = append(errs, util.ValidateEmail(req.Email)...)
+	if _, ok := constant.EntityRoleRank[req.Role]; !ok {
+		errs = append(errs, errors.New("Role should be one of owner, bookkeeper, staff or viewer."))
+	}
+	return errs
+}
+
+// POST /user/entities/{entityID}/ownership-transfer
+// POST /admin/entities/{entityID}/ownership-transfer
+
+func NewOwnershipTransferReq(j OwnershipTransferJSON, entity *Entity) (*OwnershipTransferReq, []error) {
+	req := &OwnershipTransferReq{
+		Entity:             entity,
+		Email:              strings.ToLower(strings.TrimSpace(j.Email)),
+		PreviousOwnersRole: strings.ToLower(strings.TrimSpace(j.PreviousOwnersRole)),
+		UpdateEntityEmail:  j.UpdateEntityEmail,
+	}
+	return req, req.validate()
+}
+
+type OwnershipTransferJSON struct {
+	Email              string `json:"email"`
+	PreviousOwnersRole string `json:"previousOwnersRole"`
+	UpdateEntityEmail  bool   `json:"updateEntityEmail"`
+}
+
+type OwnershipTransferReq struct {
+	Entity             *Entity
+	Email              string
+	PreviousOwnersRole string
+	UpdateEntityEmail  bool
+}
+
+func (req *OwnershipTransferReq) validate() []error {
+	errs := []error{}
+	errs = append(errs, util.ValidateEmail(req.Email)...)
+	if req.PreviousOwnersRole != "" {
+		if _, ok := constant.EntityRoleRank[req.PreviousOwnersRole]; !ok || req.PreviousOwnersRole == constant.EntityRole.Owner {
+			errs = append(errs, errors.New("Previous owners role should be one of bookkeeper, staff or viewer."))
+		}
+	}
+	return errs
+}
+
+// POST /user/entities/{entityID}/api-keys
+
+func NewAPIKeyReq(r *http.Request, entity *Entity) (*APIKeyReq, []error) {
+	var j APIKeyJSON
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&j)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req := &APIKeyReq{
+		Entity: entity,
+		Name:   strings.TrimSpace(j.Name),
+		Scopes: j.Scopes,
+	}
+	for _, ip := range j.AllowedIPs {
+		req.AllowedIPs = append(req.AllowedIPs, strings.TrimSpace(ip))
+	}
+	return req, req.validate()
+}
+
+type APIKeyJSON struct {
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	AllowedIPs []string `json:"allowedIPs"`
+}
+
+type APIKeyReq struct {
+	Entity     *Entity
+	Name       string
+	Scopes     []string
+	AllowedIPs []string
+}
+
+func (req *APIKeyReq) validate() []error {
+	errs := []error{}
+	if req.Name == "" {
+		errs = append(errs, errors.New("Name is missing."))
+	} else if len(req.Name) > 100 {
+		errs = append(errs, errors.New("Name length cannot exceed 100 characters."))
+	}
+	if len(req.Scopes) == 0 {
+		errs = append(errs, errors.New("Please provide at least one scope."))
+	}
+	for _, scope := range req.Scopes {
+		if !util.ContainString(constant.APIKeyScopes, scope) {
+			errs = append(errs, errors.New("Scope "+scope+" does not exist."))
+		}
+	}
+	for _, ip := range req.AllowedIPs {
+		_, _, cidrErr := net.ParseCIDR(ip)
+		if net.ParseIP(ip) == nil && cidrErr != nil {
+			errs = append(errs, errors.New("Allowed IP "+ip+" is not a valid IP address or CIDR range."))
+		}
+	}
+	return errs
+}
+
+// POST /invitations/{token}/accept
+// POST /ownership-transfers/{token}/accept
+
+func NewAcceptInvitationReq(r *http.Request) (*AcceptInvitationReq, []error) {
+	var req AcceptInvitationReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil && err != io.EOF {
+		return nil, []error{err}
+	}
+	req.Token = mux.Vars(r)["token"]
+	return &req, req.validate()
+}
+
+// AcceptInvitationReq only needs the user details when the invited email address is not registered yet.
+type AcceptInvitationReq struct {
+	Token     string `json:"-"`
+	Password  string `json:"password"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	UserPhone string `json:"userPhone"`
+}
+
+func (req *AcceptInvitationReq) validate() []error {
+	errs := []error{}
+	if req.Token == "" {
+		errs = append(errs, errors.New("Token is invalid."))
+	}
+	return errs
+}
+
+func (req *AcceptInvitationReq) ValidateNewUser(email string) []error {
+	errs := []error{}
+	errs = append(errs, validatePassword(req.Password)...)
+	user := User{
+		Email:     email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Telephone: req.UserPhone,
+	}
+	errs = append(errs, user.Validate()...)
+	return errs
+}
+
 // POST /transfers
 
 func NewTransferReq(userReq *TransferUserReq, initiatorEntity *Entity, receiverEntity *Entity) (*TransferReq, []error) {
@@ -691,7 +1128,21 @@ func NewSearchEntityReq(q url.Values) (*SearchEntityReq, error) {
 	if err != nil {
 		return nil, err
 	}
+	near, err := parseNear(q.Get("near"))
+	if err != nil {
+		return nil, err
+	}
+	radius := viper.GetFloat64("geocode.default_radius")
+	r, err := util.ToFloat64(strings.TrimSuffix(q.Get("radius"), "km"))
+	if err != nil {
+		return nil, errors.New("Please specify the radius in km.")
+	}
+	if r != nil {
+		radius = *r
+	}
 	return &SearchEntityReq{
+		Near:             near,
+		Radius:           radius,
 		QueryingEntityID: q.Get("querying_entity_id"),
 		Page:             page,
 		PageSize:         pageSize,
@@ -725,11 +1176,42 @@ type SearchEntityReq struct {
 
 	Country string
 	City    string
+
+	// Entities within the radius in km of the location, sorted by distance.
+	Near   *GeoLocation
+	Radius float64
+}
+
+// parseNear reads the "lat,lon" format of the near query parameter.
+func parseNear(near string) (*GeoLocation, error) {
+	if near == "" {
+		return nil, nil
+	}
+	parts := strings.Split(near, ",")
+	if len(parts) != 2 {
+		return nil, errors.New("Please specify near as lat,lon.")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return nil, errors.New("Please specify near as lat,lon.")
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return nil, errors.New("Please specify near as lat,lon.")
+	}
+	return &GeoLocation{Lat: lat, Lon: lon}, nil
 }
 
 func (query *SearchEntityReq) Validate() []error {
 	errs := []error{}
 
+	if query.Near != nil {
+		errs = append(errs, query.Near.Validate()...)
+		if query.Radius <= 0 || query.Radius > viper.GetFloat64("geocode.max_radius") {
+			errs = append(errs, errors.New("The radius should be between 0 and "+strconv.FormatFloat(viper.GetFloat64("geocode.max_radius"), 'f', -1, 64)+" km."))
+		}
+	}
+
 	if query.FavoritesOnly == true && query.QueryingEntityID == "" {
 		errs = append(errs, errors.New("Please specify the querying_entity_id."))
 	}
@@ -840,39 +1322,172 @@ func (query *BalanceReq) Validate() []error {
 	return errs
 }
 
-// Admin
-
-type AdminUpdateCategoryReq struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
+// GET /notifications
 
-func NewAdminUpdateCategoryReq(r *http.Request) (*AdminUpdateCategoryReq, []error) {
-	var req AdminUpdateCategoryReq
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&req)
+func NewSearchNotificationReq(r *http.Request, entity *Entity) (*SearchNotificationReq, []error) {
+	q := r.URL.Query()
+	page, err := util.ToInt(q.Get("page"), 1)
 	if err != nil {
 		return nil, []error{err}
 	}
-	req.Name = util.InputToTag(req.Name)
-	req.ID = mux.Vars(r)["id"]
-	return &req, req.validate()
+	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
+	if err != nil {
+		return nil, []error{err}
+	}
+	req := &SearchNotificationReq{
+		QueryingEntity: entity,
+		Page:           page,
+		PageSize:       pageSize,
+	}
+	return req, req.validate()
 }
 
-func (req *AdminUpdateCategoryReq) validate() []error {
+type SearchNotificationReq struct {
+	QueryingEntity *Entity
+	Page           int
+	PageSize       int
+}
+
+func (req *SearchNotificationReq) validate() []error {
 	errs := []error{}
-	if req.Name == "" {
-		errs = append(errs, errors.New("Please enter the category name."))
+	if req.Page < 1 {
+		errs = append(errs, errors.New("Please enter a valid page number."))
+	}
+	if req.PageSize < 1 {
+		errs = append(errs, errors.New("Please enter a valid page size."))
 	}
 	return errs
 }
 
-type AdminCreateCategoryReq struct {
-	Name string `json:"name"`
+// POST /payment-requests
+
+func NewPaymentRequestReq(userReq *PaymentRequestUserReq, payeeEntity *Entity) (*PaymentRequestReq, []error) {
+	req := &PaymentRequestReq{
+		PayeeEntity: payeeEntity,
+		Amount:      userReq.Amount,
+		Description: userReq.Description,
+		SingleUse:   userReq.SingleUse,
+		ExpiresIn:   userReq.ExpiresIn,
+	}
+	if req.ExpiresIn == 0 {
+		req.ExpiresIn = viper.GetInt("payment_request.timeout")
+	}
+	return req, req.validate()
 }
 
-func NewAdminCreateCategoryReq(r *http.Request) (*AdminCreateCategoryReq, []error) {
-	var req AdminCreateCategoryReq
+type PaymentRequestUserReq struct {
+	Payee       string  `json:"payee"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+	SingleUse   bool    `json:"singleUse"`
+	ExpiresIn   int     `json:"expiresIn"`
+}
+
+type PaymentRequestReq struct {
+	PayeeEntity *Entity
+	Amount      float64
+	Description string
+	SingleUse   bool
+	ExpiresIn   int // seconds
+}
+
+func (req *PaymentRequestReq) validate() []error {
+	errs := []error{}
+
+	// Amount should be positive value and with up to two decimal places.
+	if req.Amount <= 0 || !util.IsDecimalValid(req.Amount) {
+		errs = append(errs, errors.New("Please enter a valid numeric amount to request with up to two decimal places."))
+	}
+
+	if req.ExpiresIn < 0 || req.ExpiresIn > viper.GetInt("payment_request.max_timeout") {
+		errs = append(errs, errors.New("Please enter a valid expiry time in seconds."))
+	}
+
+	// Only allow payment requests for accounts that have "trading-accepted" status
+	if req.PayeeEntity.Status != constant.Trading.Accepted {
+		errs = append(errs, errors.New("Payee is not a trading member. Payment requests can only be created by trading members."))
+	}
+
+	return errs
+}
+
+// GET /payment-requests/{token}
+
+func NewGetPaymentRequestReq(r *http.Request) (*GetPaymentRequestReq, []error) {
+	q := r.URL.Query()
+	size, err := util.ToInt(q.Get("size"), 256)
+	if err != nil {
+		return nil, []error{err}
+	}
+	req := &GetPaymentRequestReq{
+		Token:  mux.Vars(r)["token"],
+		Format: strings.ToLower(q.Get("format")),
+		Size:   size,
+	}
+	return req, req.validate()
+}
+
+type GetPaymentRequestReq struct {
+	Token  string
+	Format string // "json", "png" or "svg"
+	Size   int
+}
+
+func (req *GetPaymentRequestReq) validate() []error {
+	errs := []error{}
+
+	if req.Format == "" {
+		req.Format = "json"
+	}
+	if req.Format != "json" && req.Format != "png" && req.Format != "svg" {
+		errs = append(errs, errors.New("Format can be only 'json', 'png' or 'svg'."))
+	}
+	if req.Size < 64 || req.Size > 1024 {
+		errs = append(errs, errors.New("Size should be between 64 and 1024 pixels."))
+	}
+
+	return errs
+}
+
+// POST /payment-requests/{token}/pay
+
+type PayPaymentRequestUserReq struct {
+	Payer string `json:"payer"`
+}
+
+// Admin
+
+type AdminUpdateCategoryReq struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func NewAdminUpdateCategoryReq(r *http.Request) (*AdminUpdateCategoryReq, []error) {
+	var req AdminUpdateCategoryReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	req.Name = util.InputToTag(req.Name)
+	req.ID = mux.Vars(r)["id"]
+	return &req, req.validate()
+}
+
+func (req *AdminUpdateCategoryReq) validate() []error {
+	errs := []error{}
+	if req.Name == "" {
+		errs = append(errs, errors.New("Please enter the category name."))
+	}
+	return errs
+}
+
+type AdminCreateCategoryReq struct {
+	Name string `json:"name"`
+}
+
+func NewAdminCreateCategoryReq(r *http.Request) (*AdminCreateCategoryReq, []error) {
+	var req AdminCreateCategoryReq
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(&req)
 	if err != nil {
@@ -1018,14 +1633,6 @@ func NewAdminUpdateUserReq(j AdminUpdateUserJSON, originUser *User) (*AdminUpdat
 		RemovedEntities: util.ToObjectIDs(removedEntities),
 	}
 
-	if req.Password != "" {
-		hashedPassword, err := bcrypt.Hash(req.Password)
-		if err != nil {
-			return nil, []error{err}
-		}
-		req.Password = hashedPassword
-	}
-
 	return &req, nil
 }
 
@@ -1035,7 +1642,9 @@ type AdminUpdateUserReq struct {
 	FirstName  string
 	LastName   string
 	Telephone  string
-	Password   string
+	// Password is hashed by the logic layer which also keeps the password history.
+	Password        string
+	PasswordHistory []string
 	// Entity
 	Entity          *[]string
 	AddedEntities   []primitive.ObjectID
@@ -1088,6 +1697,45 @@ func NewAdminDeleteUserReq(r *http.Request) (*AdminDeleteUser, []error) {
 	}, nil
 }
 
+// POST /admin/users/{userID}/impersonation
+
+func NewAdminImpersonationReq(r *http.Request) (*AdminImpersonationReq, []error) {
+	var j AdminImpersonationJSON
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&j)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req := &AdminImpersonationReq{
+		Write:  j.Write,
+		Reason: strings.TrimSpace(j.Reason),
+	}
+	return req, req.validate()
+}
+
+type AdminImpersonationJSON struct {
+	Write  bool   `json:"write"`
+	Reason string `json:"reason"`
+}
+
+type AdminImpersonationReq struct {
+	Write  bool
+	Reason string
+}
+
+func (req *AdminImpersonationReq) validate() []error {
+	errs := []error{}
+	if req.Reason == "" {
+		errs = append(errs, errors.New("Please specify a reason for the impersonation."))
+	} else if len(req.Reason) > 255 {
+		errs = append(errs, errors.New("Reason length cannot exceed 255 characters."))
+	}
+	return errs
+}
+
 type AdminSearchUserReq struct {
 	Email    string `json:"email"`
 	LastName string `json:"last_name"`
@@ -1271,9 +1919,16 @@ func NewAdminUpdateEntityReq(j AdminUpdateEntityJSON, originEntity *Entity, orig
 		PostalCode: j.PostalCode,
 		Country:    j.Country,
 		// Account
-		MaxPosBal: j.MaxPosBal,
-		MaxNegBal: j.MaxNegBal,
-		Status:    j.Status,
+		MaxPosBal:           j.MaxPosBal,
+		MaxNegBal:           j.MaxNegBal,
+		MaxDailyOutAmount:   j.MaxDailyOutAmount,
+		MaxMonthlyOutAmount: j.MaxMonthlyOutAmount,
+		MaxDailyOutCount:    j.MaxDailyOutCount,
+		Status:              j.Status,
+		StatusReason:        strings.TrimSpace(j.StatusReason),
+	}
+	if j.Location != nil {
+		req.Location = &GeoLocation{Lat: j.Location.Lat, Lon: j.Location.Lon, Manual: true}
 	}
 
 	return &req, nil
@@ -1283,6 +1938,7 @@ type AdminUpdateEntityReq struct {
 	OriginEntity                       *Entity
 	OriginBalanceLimit                 *BalanceLimit
 	Status                             string
+	StatusReason                       string
 	Name                               string
 	Email                              string
 	Telephone                          string
@@ -1314,10 +1970,17 @@ type AdminUpdateEntityReq struct {
 	// Account
 	MaxPosBal *float64
 	MaxNegBal *float64
+	// The velocity limits fall back to the network defaults when they are cleared with null.
+	MaxDailyOutAmount   NullableFloat64
+	MaxMonthlyOutAmount NullableFloat64
+	MaxDailyOutCount    NullableInt
+	// Geo
+	Location *GeoLocation
 }
 
 type AdminUpdateEntityJSON struct {
 	Status           string    `json:"status"`
+	StatusReason     string    `json:"statusReason"`
 	Name             string    `json:"name"`
 	Email            string    `json:"email"`
 	Telephone        string    `json:"telephone"`
@@ -1341,8 +2004,13 @@ type AdminUpdateEntityJSON struct {
 	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail"`
 	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin"`
 	// Account
-	MaxPosBal *float64 `json:"maxPositiveBalance"`
-	MaxNegBal *float64 `json:"maxNegativeBalance"`
+	MaxPosBal           *float64        `json:"maxPositiveBalance"`
+	MaxNegBal           *float64        `json:"maxNegativeBalance"`
+	MaxDailyOutAmount   NullableFloat64 `json:"maxDailyOutgoingAmount"`
+	MaxMonthlyOutAmount NullableFloat64 `json:"maxMonthlyOutgoingAmount"`
+	MaxDailyOutCount    NullableInt     `json:"maxDailyOutgoingTransfers"`
+	// Geo
+	Location *GeoLocationJSON `json:"location"`
 	// Useless (Do not use it)
 	ID            string `json:"id"`
 	AccountNumber string `json:"accountNumber"`
@@ -1357,12 +2025,26 @@ func (req *AdminUpdateEntityJSON) validate() []error {
 	if req.AccountNumber != "" {
 		errs = append(errs, errors.New("The account number cannot be changed."))
 	}
+	if req.StatusReason != "" && req.Status == "" {
+		errs = append(errs, errors.New("A status reason can only be given when changing the status."))
+	} else if len(req.StatusReason) > 500 {
+		errs = append(errs, errors.New("Status reason length cannot exceed 500 characters."))
+	}
 	if req.MaxPosBal != nil && *req.MaxPosBal < 0 {
 		errs = append(errs, errors.New("The max positive balance should be positive."))
 	}
 	if req.MaxNegBal != nil && *req.MaxNegBal < 0 {
 		errs = append(errs, errors.New("The max negative balance should be positive."))
 	}
+	if v := req.MaxDailyOutAmount.Value; v != nil && (*v < 0 || !util.IsDecimalValid(*v)) {
+		errs = append(errs, errors.New("The max daily outgoing amount should be positive and with up to two decimal places."))
+	}
+	if v := req.MaxMonthlyOutAmount.Value; v != nil && (*v < 0 || !util.IsDecimalValid(*v)) {
+		errs = append(errs, errors.New("The max monthly outgoing amount should be positive and with up to two decimal places."))
+	}
+	if v := req.MaxDailyOutCount.Value; v != nil && *v < 0 {
+		errs = append(errs, errors.New("The max daily outgoing transfers should be positive."))
+	}
 
 	categories := []string{}
 	if req.Categories != nil {
@@ -1393,6 +2075,9 @@ func (req *AdminUpdateEntityJSON) validate() []error {
 	if req.Wants != nil {
 		errs = append(errs, validateTags(*req.Wants)...)
 	}
+	if req.Location != nil {
+		errs = append(errs, req.Location.validate()...)
+	}
 
 	return errs
 }
@@ -1469,6 +2154,88 @@ func (req *AdminTransferReq) Validate() []error {
 	return errs
 }
 
+// POST /admin/entities/{entityID}/write-off
+
+func NewAdminWriteOffReq(userReq *AdminWriteOffUserReq, entity *Entity, reserveEntity *Entity, balance float64) (*AdminWriteOffReq, []error) {
+	req := &AdminWriteOffReq{
+		Entity:        entity,
+		ReserveEntity: reserveEntity,
+		Balance:       balance,
+		Amount:        math.Abs(balance),
+		Reason:        strings.TrimSpace(userReq.Reason),
+	}
+	return req, req.validate()
+}
+
+type AdminWriteOffUserReq struct {
+	Reason string `json:"reason"`
+}
+
+type AdminWriteOffReq struct {
+	Entity        *Entity
+	ReserveEntity *Entity
+	Balance       float64
+	Amount        float64
+	Reason        string
+}
+
+func (req *AdminWriteOffReq) validate() []error {
+	errs := []error{}
+
+	if req.Reason == "" {
+		errs = append(errs, errors.New("Please specify a reason for the write-off."))
+	} else if len(req.Reason) > 255 {
+		errs = append(errs, errors.New("Reason length cannot exceed 255 characters."))
+	}
+	if req.Balance >= 0 {
+		errs = append(errs, errors.New("Only a negative balance can be written off."))
+	}
+	if req.Entity.AccountNumber == req.ReserveEntity.AccountNumber {
+		errs = append(errs, errors.New("The write-off account cannot be written off."))
+	}
+
+	return errs
+}
+
+// POST /admin/entities/{entityID}/merge
+
+func NewAdminMergeEntityReq(entity *Entity, intoEntity *Entity, balance float64) (*AdminMergeEntityReq, []error) {
+	req := &AdminMergeEntityReq{
+		Entity:     entity,
+		IntoEntity: intoEntity,
+		Balance:    balance,
+	}
+	return req, req.validate()
+}
+
+type AdminMergeEntityUserReq struct {
+	IntoEntityID string `json:"intoEntityID"`
+}
+
+// AdminMergeEntityReq merges Entity into IntoEntity, which is the one kept.
+type AdminMergeEntityReq struct {
+	Entity     *Entity
+	IntoEntity *Entity
+	// Balance of the account of the merged entity.
+	Balance float64
+}
+
+func (req *AdminMergeEntityReq) validate() []error {
+	errs := []error{}
+
+	if req.Entity.ID == req.IntoEntity.ID {
+		errs = append(errs, errors.New("An entity cannot be merged into itself."))
+	}
+	if req.Entity.AccountNumber == "" || req.IntoEntity.AccountNumber == "" {
+		errs = append(errs, errors.New("Both entities must have an account."))
+	}
+	if req.Entity.AccountNumber == viper.GetString("write_off.account_number") {
+		errs = append(errs, errors.New("The write-off account cannot be merged."))
+	}
+
+	return errs
+}
+
 // GET /admin/transfers/{transferID}
 
 func NewAdminGetTransfer(r *http.Request) (*AdminGetTransfer, []error) {
@@ -1600,3 +2367,529 @@ func getCategories(input string) []string {
 	}
 	return strings.FieldsFunc(strings.ToLower(input), splitFn)
 }
+
+// POST /admin/ledger/accounts
+
+func NewAdminCreateLedgerAccountReq(r *http.Request) (*AdminCreateLedgerAccountReq, []error) {
+	var req AdminCreateLedgerAccountReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Code = strings.TrimSpace(req.Code)
+	req.Name = strings.TrimSpace(req.Name)
+	return &req, req.validate()
+}
+
+type AdminCreateLedgerAccountReq struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+func (req *AdminCreateLedgerAccountReq) validate() []error {
+	errs := []error{}
+
+	if req.Code == "" {
+		errs = append(errs, errors.New("Account code is empty."))
+	} else if len(req.Code) > 16 {
+		errs = append(errs, errors.New("Account code length cannot exceed 16 characters."))
+	}
+	if req.Name == "" {
+		errs = append(errs, errors.New("Account name is empty."))
+	} else if len(req.Name) > 120 {
+		errs = append(errs, errors.New("Account name length cannot exceed 120 characters."))
+	}
+	if req.Type != constant.LedgerAccountType.Asset &&
+		req.Type != constant.LedgerAccountType.Liability &&
+		req.Type != constant.LedgerAccountType.Income &&
+		req.Type != constant.LedgerAccountType.Expense &&
+		req.Type != constant.LedgerAccountType.Equity {
+		errs = append(errs, errors.New("Account type can be only 'asset', 'liability', 'income', 'expense' or 'equity'."))
+	}
+	if len(req.Description) > 510 {
+		errs = append(errs, errors.New("Description length cannot exceed 510 characters."))
+	}
+
+	return errs
+}
+
+// POST /admin/ledger/journals
+
+func NewAdminCreateLedgerJournalReq(r *http.Request) (*AdminCreateLedgerJournalReq, []error) {
+	var body AdminCreateLedgerJournalJSON
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&body)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	errs := body.validate()
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	req := &AdminCreateLedgerJournalReq{
+		Type:        constant.LedgerJournalType.Manual,
+		Description: body.Description,
+		Date:        util.ParseTime(body.Date),
+	}
+	if req.Date.IsZero() {
+		req.Date = time.Now()
+	}
+	for _, p := range body.Postings {
+		req.Postings = append(req.Postings, &LedgerPostingReq{
+			AccountCode: p.Account,
+			Amount:      p.Debit - p.Credit,
+		})
+	}
+
+	return req, nil
+}
+
+type AdminCreateLedgerJournalJSON struct {
+	Description string `json:"description"`
+	Date        string `json:"date"`
+	Postings    []struct {
+		Account string  `json:"account"`
+		Debit   float64 `json:"debit"`
+		Credit  float64 `json:"credit"`
+	} `json:"postings"`
+}
+
+func (req *AdminCreateLedgerJournalJSON) validate() []error {
+	errs := []error{}
+
+	if req.Description == "" {
+		errs = append(errs, errors.New("Description is empty."))
+	} else if len(req.Description) > 510 {
+		errs = append(errs, errors.New("Description length cannot exceed 510 characters."))
+	}
+	if len(req.Postings) < 2 {
+		errs = append(errs, errors.New("A journal entry needs at least two postings."))
+		return errs
+	}
+
+	// Compare the totals in cents to avoid floating point errors.
+	var debits, credits int64
+	for _, p := range req.Postings {
+		if p.Account == "" {
+			errs = append(errs, errors.New("Please specify the account of every posting."))
+		}
+		if p.Debit < 0 || p.Credit < 0 || !util.IsDecimalValid(p.Debit) || !util.IsDecimalValid(p.Credit) {
+			errs = append(errs, errors.New("Please enter valid numeric debit and credit amounts with up to two decimal places."))
+		} else if (p.Debit == 0) == (p.Credit == 0) {
+			errs = append(errs, errors.New("Every posting should have either a debit or a credit amount."))
+		}
+		debits += int64(math.Round(p.Debit * 100))
+		credits += int64(math.Round(p.Credit * 100))
+	}
+	if debits != credits {
+		errs = append(errs, errors.New("The total debits should equal the total credits."))
+	}
+
+	return errs
+}
+
+type AdminCreateLedgerJournalReq struct {
+	Type        string
+	Description string
+	PostedBy    string
+	Date        time.Time
+	Postings    []*LedgerPostingReq
+}
+
+type LedgerPostingReq struct {
+	AccountCode string
+	Amount      float64 // debits are positive and credits are negative
+}
+
+// GET /admin/ledger/trial-balance
+
+func NewAdminTrialBalanceReq(r *http.Request) (*AdminTrialBalanceReq, []error) {
+	req := &AdminTrialBalanceReq{
+		Date: util.ParseTime(r.URL.Query().Get("date")),
+	}
+	if req.Date.IsZero() {
+		req.Date = time.Now()
+	}
+	return req, nil
+}
+
+type AdminTrialBalanceReq struct {
+	Date time.Time
+}
+
+// GET /admin/ledger/income-statement
+
+func NewAdminIncomeStatementReq(r *http.Request) (*AdminIncomeStatementReq, []error) {
+	q := r.URL.Query()
+	req := &AdminIncomeStatementReq{
+		DateFrom: util.ParseTime(q.Get("date_from")),
+		DateTo:   util.ParseTime(q.Get("date_to")),
+	}
+	if req.DateTo.IsZero() {
+		req.DateTo = time.Now()
+	}
+	if req.DateFrom.IsZero() {
+		req.DateFrom = time.Date(req.DateTo.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return req, req.validate()
+}
+
+type AdminIncomeStatementReq struct {
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+func (req *AdminIncomeStatementReq) validate() []error {
+	errs := []error{}
+	if req.DateFrom.After(req.DateTo) {
+		errs = append(errs, errors.New("date_from should be before date_to."))
+	}
+	return errs
+}
+
+// GET /admin/applications
+
+func NewAdminSearchApplicationReq(r *http.Request) (*AdminSearchApplicationReq, []error) {
+	q := r.URL.Query()
+	page, err := util.ToInt(q.Get("page"), 1)
+	if err != nil {
+		return nil, []error{err}
+	}
+	pageSize, err := util.ToInt(q.Get("page_size"), viper.GetInt("page_size"))
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	req := &AdminSearchApplicationReq{
+		Page:       page,
+		PageSize:   pageSize,
+		Statuses:   getApplicationStatuses(q.Get("status")),
+		ReviewerID: util.ToObjectID(q.Get("reviewer_id")),
+	}
+	if len(req.Statuses) == 0 {
+		req.Statuses = []string{constant.Application.Open, constant.Application.InfoRequested}
+	}
+	return req, req.validate()
+}
+
+type AdminSearchApplicationReq struct {
+	Page       int
+	PageSize   int
+	Statuses   []string
+	ReviewerID primitive.ObjectID
+}
+
+func (req *AdminSearchApplicationReq) validate() []error {
+	errs := []error{}
+	for _, s := range req.Statuses {
+		if s != constant.Application.Open &&
+			s != constant.Application.InfoRequested &&
+			s != constant.Application.Approved &&
+			s != constant.Application.Rejected {
+			errs = append(errs, errors.New("Please specify valid status."))
+		}
+	}
+	return errs
+}
+
+// The application statuses are camel case so they are not lowercased like the other filters.
+func getApplicationStatuses(input string) []string {
+	splitFn := func(c rune) bool {
+		return c == ',' || c == ' '
+	}
+	return strings.FieldsFunc(input, splitFn)
+}
+
+// PATCH /admin/applications/{applicationID}/reviewer
+
+func NewAdminAssignReviewerReq(r *http.Request) (*AdminAssignReviewerReq, []error) {
+	var req AdminAssignReviewerReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil && err != io.EOF {
+		return nil, []error{err}
+	}
+	// Admins assign the application to themselves when no reviewer is specified.
+	if req.ReviewerID == "" {
+		req.ReviewerID = r.Header.Get("userID")
+	}
+	return &req, nil
+}
+
+type AdminAssignReviewerReq struct {
+	ReviewerID string `json:"reviewerID"`
+}
+
+// POST /admin/applications/{applicationID}/notes
+
+func NewAdminApplicationNoteReq(r *http.Request) (*AdminApplicationNoteReq, []error) {
+	var req AdminApplicationNoteReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	return &req, req.validate()
+}
+
+type AdminApplicationNoteReq struct {
+	Note string `json:"note"`
+}
+
+func (req *AdminApplicationNoteReq) validate() []error {
+	errs := []error{}
+	if req.Note == "" {
+		errs = append(errs, errors.New("Note is empty."))
+	} else if len(req.Note) > 2000 {
+		errs = append(errs, errors.New("Note length cannot exceed 2000 characters."))
+	}
+	return errs
+}
+
+// POST /admin/applications/{applicationID}/info-requests
+
+func NewAdminApplicationInfoReq(r *http.Request) (*AdminApplicationInfoReq, []error) {
+	var req AdminApplicationInfoReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	return &req, req.validate()
+}
+
+type AdminApplicationInfoReq struct {
+	Message string `json:"message"`
+}
+
+func (req *AdminApplicationInfoReq) validate() []error {
+	errs := []error{}
+	if req.Message == "" {
+		errs = append(errs, errors.New("Message is empty."))
+	} else if len(req.Message) > 2000 {
+		errs = append(errs, errors.New("Message length cannot exceed 2000 characters."))
+	}
+	return errs
+}
+
+// POST /admin/applications/{applicationID}/decision
+
+func NewAdminApplicationDecisionReq(r *http.Request) (*AdminApplicationDecisionReq, []error) {
+	var req AdminApplicationDecisionReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Decision = strings.ToLower(strings.TrimSpace(req.Decision))
+	req.Reason = strings.TrimSpace(req.Reason)
+	return &req, req.validate()
+}
+
+type AdminApplicationDecisionReq struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+func (req *AdminApplicationDecisionReq) validate() []error {
+	errs := []error{}
+	if req.Decision != constant.ApplicationDecision.Approve && req.Decision != constant.ApplicationDecision.Reject {
+		errs = append(errs, errors.New("Decision can be only 'approve' or 'reject'."))
+	}
+	if req.Decision == constant.ApplicationDecision.Reject && req.Reason == "" {
+		errs = append(errs, errors.New("Please specify a reason for the rejection."))
+	}
+	if len(req.Reason) > 500 {
+		errs = append(errs, errors.New("Reason length cannot exceed 500 characters."))
+	}
+	return errs
+}
+
+// Status returns the entity status the decision leads to.
+func (req *AdminApplicationDecisionReq) Status() string {
+	if req.Decision == constant.ApplicationDecision.Approve {
+		return constant.Entity.Accepted
+	}
+	return constant.Entity.Rejected
+}
+
+// POST /user/entities/{entityID}/application/resubmit
+
+func NewResubmitApplicationReq(r *http.Request) (*ResubmitApplicationReq, []error) {
+	var req ResubmitApplicationReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		if err == io.EOF {
+			return nil, []error{errors.New("Please provide valid inputs.")}
+		}
+		return nil, []error{err}
+	}
+	req.Response = strings.TrimSpace(req.Response)
+	return &req, req.validate()
+}
+
+type ResubmitApplicationReq struct {
+	Response string `json:"response"`
+}
+
+func (req *ResubmitApplicationReq) validate() []error {
+	errs := []error{}
+	if req.Response == "" {
+		errs = append(errs, errors.New("Response is empty."))
+	} else if len(req.Response) > 2000 {
+		errs = append(errs, errors.New("Response length cannot exceed 2000 characters."))
+	}
+	return errs
+}
+
+// POST /admin/admin-users
+
+func NewAdminCreateAdminUserReq(r *http.Request) (*AdminCreateAdminUserReq, []error) {
+	var req AdminCreateAdminUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	return &req, req.validate()
+}
+
+type AdminCreateAdminUserReq struct {
+	Email    string   `json:"email"`
+	Name     string   `json:"name"`
+	Password string   `json:"password"`
+	Roles    []string `json:"roles"`
+}
+
+func (req *AdminCreateAdminUserReq) validate() []error {
+	errs := []error{}
+	errs = append(errs, util.ValidateEmail(req.Email)...)
+	errs = append(errs, validateAdminName(req.Name)...)
+	errs = append(errs, validatePassword(req.Password)...)
+	return errs
+}
+
+// PATCH /admin/admin-users/{adminID}
+
+func NewAdminUpdateAdminUserReq(r *http.Request) (*AdminUpdateAdminUserReq, []error) {
+	var req AdminUpdateAdminUserReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	errs := []error{}
+	if req.Name != "" {
+		errs = append(errs, validateAdminName(req.Name)...)
+	}
+	return &req, errs
+}
+
+type AdminUpdateAdminUserReq struct {
+	Name  string    `json:"name"`
+	Roles *[]string `json:"roles"`
+}
+
+func validateAdminName(name string) []error {
+	errs := []error{}
+	if name == "" {
+		errs = append(errs, errors.New("Name is missing."))
+	} else if len(name) > 100 {
+		errs = append(errs, errors.New("Name length cannot exceed 100 characters."))
+	}
+	return errs
+}
+
+// POST /admin/roles
+
+func NewAdminCreateRoleReq(r *http.Request) (*AdminRoleReq, []error) {
+	var req AdminRoleReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	req.Name = strings.ToLower(strings.TrimSpace(req.Name))
+	errs := validateAdminRoleName(req.Name)
+	errs = append(errs, req.validate()...)
+	return &req, errs
+}
+
+// PATCH /admin/roles/{role}
+
+func NewAdminUpdateRoleReq(r *http.Request) (*AdminRoleReq, []error) {
+	var req AdminRoleReq
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		return nil, []error{err}
+	}
+	req.Name = mux.Vars(r)["role"]
+	if req.Name == constant.AdminRole.SuperAdmin {
+		return nil, []error{errors.New("The built-in role cannot be changed.")}
+	}
+	return &req, req.validate()
+}
+
+type AdminRoleReq struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func (req *AdminRoleReq) validate() []error {
+	errs := []error{}
+	if len(req.Description) > 500 {
+		errs = append(errs, errors.New("Description length cannot exceed 500 characters."))
+	}
+	for _, p := range req.Permissions {
+		if !util.ContainString(constant.AdminPermissions, p) {
+			errs = append(errs, errors.New("Permission "+p+" does not exist."))
+		}
+	}
+	return errs
+}
+
+func validateAdminRoleName(name string) []error {
+	errs := []error{}
+	if name == "" {
+		return append(errs, errors.New("Role name is missing."))
+	}
+	if name == constant.AdminRole.SuperAdmin {
+		return append(errs, errors.New("The role already exists."))
+	}
+	if len(name) > 50 {
+		errs = append(errs, errors.New("Role name length cannot exceed 50 characters."))
+	}
+	for _, ch := range name {
+		if !(ch >= 'a' && ch <= 'z') && !(ch >= '0' && ch <= '9') && ch != '-' && ch != '_' {
+			errs = append(errs, errors.New("Role name can only contain letters, numbers, hyphens and underscores."))
+			break
+		}
+	}
+	return errs
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"math"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/global/constant"
@@ -8,28 +9,88 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// POST /login
+// POST /login/2fa
+// POST /admin/login
+// POST /admin/login/2fa
+
+func NewLoginRespond(info *LoginInfo, token string, refreshToken string) *LoginRespond {
+	r := &LoginRespond{Token: token, RefreshToken: refreshToken}
+	if info.LastLoginIP != "" {
+		r.LastLoginIP = info.LastLoginIP
+	}
+	if !info.LastLoginDate.IsZero() {
+		r.LastLoginDate = &info.LastLoginDate
+	}
+	return r
+}
+
+// NewTwoFactorChallengeRespond is returned instead of the tokens when the password is correct but a code is still needed.
+func NewTwoFactorChallengeRespond(twoFactorToken string, setupRequired bool) *LoginRespond {
+	return &LoginRespond{
+		TwoFactorRequired:      true,
+		TwoFactorSetupRequired: setupRequired,
+		TwoFactorToken:         twoFactorToken,
+	}
+}
+
+type LoginRespond struct {
+	Token                  string     `json:"token,omitempty"`
+	RefreshToken           string     `json:"refreshToken,omitempty"`
+	LastLoginIP            string     `json:"lastLoginIP,omitempty"`
+	LastLoginDate          *time.Time `json:"lastLoginDate,omitempty"`
+	TwoFactorRequired      bool       `json:"twoFactorRequired,omitempty"`
+	TwoFactorSetupRequired bool       `json:"twoFactorSetupRequired,omitempty"`
+	TwoFactorToken         string     `json:"twoFactorToken,omitempty"`
+	// RecoveryCodes are only returned when the two-factor authentication of an admin is enabled during the login.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+	// PasswordChangeRequired is set when the password of an admin has expired, the token has no permissions until it is changed.
+	PasswordChangeRequired bool `json:"passwordChangeRequired,omitempty"`
+}
+
+// POST /user/2fa/setup
+// POST /admin/login/2fa/setup
+
+type TwoFactorSetupRespond struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qrCode"`
+}
+
+// POST /user/2fa/enable
+// POST /user/2fa/recovery-codes
+// POST /admin/2fa/recovery-codes
+
+type RecoveryCodesRespond struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
 // GET /user
 
 func NewUserRespond(user *User) *UserRespond {
 	return &UserRespond{
-		ID:            user.ID.Hex(),
-		Email:         user.Email,
-		Telephone:     user.Telephone,
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		LastLoginIP:   user.LastLoginIP,
-		LastLoginDate: user.LastLoginDate,
+		ID:               user.ID.Hex(),
+		Email:            user.Email,
+		Telephone:        user.Telephone,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		LastLoginIP:      user.LastLoginIP,
+		LastLoginDate:    user.LastLoginDate,
+		TwoFactorEnabled: user.TwoFactor.Enabled(),
+		EmailVerified:    !user.EmailUnverified,
 	}
 }
 
 type UserRespond struct {
-	ID            string    `json:"id"`
-	Email         string    `json:"email"`
-	FirstName     string    `json:"firstName"`
-	LastName      string    `json:"lastName"`
-	Telephone     string    `json:"telephone"`
-	LastLoginIP   string    `json:"lastLoginIP"`
-	LastLoginDate time.Time `json:"lastLoginDate"`
+	ID               string    `json:"id"`
+	Email            string    `json:"email"`
+	FirstName        string    `json:"firstName"`
+	LastName         string    `json:"lastName"`
+	Telephone        string    `json:"telephone"`
+	LastLoginIP      string    `json:"lastLoginIP"`
+	LastLoginDate    time.Time `json:"lastLoginDate"`
+	TwoFactorEnabled bool      `json:"twoFactorEnabled"`
+	EmailVerified    bool      `json:"emailVerified"`
 }
 
 // GET /user/entities
@@ -45,6 +106,7 @@ func NewEntityRespond(
 		AccountNumber:                      entity.AccountNumber,
 		Name:                               entity.Name,
 		Email:                              entity.Email,
+		EmailVerified:                      !entity.EmailUnverified,
 		Telephone:                          entity.Telephone,
 		IncType:                            entity.IncType,
 		CompanyNumber:                      entity.CompanyNumber,
@@ -66,35 +128,44 @@ func NewEntityRespond(
 		MaxNegativeBalance:                 balanceLimit.MaxNegBal,
 		MaxPositiveBalance:                 balanceLimit.MaxPosBal,
 		PendingTransfers:                   pendingTransfers,
+		BalanceAlerts:                      entity.BalanceAlerts,
+		Logo:                               NewEntityImageRespond(entity.Logo),
+		Images:                             NewEntityImagesRespond(entity.Images),
+		Location:                           entity.Location,
 	}
 }
 
 type EntityRespond struct {
-	ID                                 string             `json:"id"`
-	AccountNumber                      string             `json:"accountNumber"`
-	Name                               string             `json:"name"`
-	Email                              string             `json:"email,omitempty"`
-	Telephone                          string             `json:"telephone"`
-	IncType                            string             `json:"incType"`
-	CompanyNumber                      string             `json:"companyNumber"`
-	Website                            string             `json:"website"`
-	DeclaredTurnover                   *int               `json:"declaredTurnover"`
-	Description                        string             `json:"description"`
-	Address                            string             `json:"address"`
-	City                               string             `json:"city"`
-	Region                             string             `json:"region"`
-	PostalCode                         string             `json:"postalCode"`
-	Country                            string             `json:"country"`
-	Status                             string             `json:"status"`
-	ShowTagsMatchedSinceLastLogin      bool               `json:"showTagsMatchedSinceLastLogin"`
-	ReceiveDailyMatchNotificationEmail bool               `json:"receiveDailyMatchNotificationEmail"`
-	Offers                             []string           `json:"offers"`
-	Wants                              []string           `json:"wants"`
-	Categories                         []string           `json:"categories"`
-	Balance                            float64            `json:"balance"`
-	MaxPositiveBalance                 float64            `json:"maxPositiveBalance"`
-	MaxNegativeBalance                 float64            `json:"maxNegativeBalance"`
-	PendingTransfers                   []*TransferRespond `json:"pendingTransfers"`
+	ID                                 string                `json:"id"`
+	AccountNumber                      string                `json:"accountNumber"`
+	Name                               string                `json:"name"`
+	Email                              string                `json:"email,omitempty"`
+	EmailVerified                      bool                  `json:"emailVerified"`
+	Telephone                          string                `json:"telephone"`
+	IncType                            string                `json:"incType"`
+	CompanyNumber                      string                `json:"companyNumber"`
+	Website                            string                `json:"website"`
+	DeclaredTurnover                   *int                  `json:"declaredTurnover"`
+	Description                        string                `json:"description"`
+	Address                            string                `json:"address"`
+	City                               string                `json:"city"`
+	Region                             string                `json:"region"`
+	PostalCode                         string                `json:"postalCode"`
+	Country                            string                `json:"country"`
+	Status                             string                `json:"status"`
+	ShowTagsMatchedSinceLastLogin      bool                  `json:"showTagsMatchedSinceLastLogin"`
+	ReceiveDailyMatchNotificationEmail bool                  `json:"receiveDailyMatchNotificationEmail"`
+	Offers                             []string              `json:"offers"`
+	Wants                              []string              `json:"wants"`
+	Categories                         []string              `json:"categories"`
+	Balance                            float64               `json:"balance"`
+	MaxPositiveBalance                 float64               `json:"maxPositiveBalance"`
+	MaxNegativeBalance                 float64               `json:"maxNegativeBalance"`
+	PendingTransfers                   []*TransferRespond    `json:"pendingTransfers"`
+	BalanceAlerts                      *BalanceAlerts        `json:"balanceAlerts,omitempty"`
+	Logo                               *EntityImageRespond   `json:"logo"`
+	Images                             []*EntityImageRespond `json:"images"`
+	Location                           *GeoLocation          `json:"location"`
 }
 
 // GET /entities
@@ -125,30 +196,63 @@ func NewSearchEntityRespond(entity *Entity, queryingEntityStatus string, favorit
 		Wants:            TagFieldToNames(entity.Wants),
 		Categories:       entity.Categories,
 		IsFavorite:       util.ContainID(favoriteEntities, entity.ID.Hex()),
+		Logo:             NewEntityImageRespond(entity.Logo),
+		Images:           NewEntityImagesRespond(entity.Images),
+		Location:         entity.Location,
 	}
 }
 
 type SearchEntityRespond struct {
-	ID               string   `json:"id"`
-	AccountNumber    string   `json:"accountNumber"`
-	Name             string   `json:"name"`
-	Email            string   `json:"email,omitempty"`
-	Telephone        string   `json:"telephone"`
-	IncType          string   `json:"incType"`
-	CompanyNumber    string   `json:"companyNumber"`
-	Website          string   `json:"website"`
-	DeclaredTurnover *int     `json:"declaredTurnover"`
-	Description      string   `json:"description"`
-	Address          string   `json:"address"`
-	City             string   `json:"city"`
-	Region           string   `json:"region"`
-	PostalCode       string   `json:"postalCode"`
-	Country          string   `json:"country"`
-	Status           string   `json:"status"`
-	Offers           []string `json:"offers"`
-	Wants            []string `json:"wants"`
-	Categories       []string `json:"categories"`
-	IsFavorite       bool     `json:"isFavorite"`
+	ID               string                `json:"id"`
+	AccountNumber    string                `json:"accountNumber"`
+	Name             string                `json:"name"`
+	Email            string                `json:"email,omitempty"`
+	Telephone        string                `json:"telephone"`
+	IncType          string                `json:"incType"`
+	CompanyNumber    string                `json:"companyNumber"`
+	Website          string                `json:"website"`
+	DeclaredTurnover *int                  `json:"declaredTurnover"`
+	Description      string                `json:"description"`
+	Address          string                `json:"address"`
+	City             string                `json:"city"`
+	Region           string                `json:"region"`
+	PostalCode       string                `json:"postalCode"`
+	Country          string                `json:"country"`
+	Status           string                `json:"status"`
+	Offers           []string              `json:"offers"`
+	Wants            []string              `json:"wants"`
+	Categories       []string              `json:"categories"`
+	IsFavorite       bool                  `json:"isFavorite"`
+	Logo             *EntityImageRespond   `json:"logo"`
+	Images           []*EntityImageRespond `json:"images"`
+	Location         *GeoLocation          `json:"location"`
+	// Distance in km from the near location of the search.
+	Distance *float64 `json:"distance,omitempty"`
+}
+
+func NewEntityImageRespond(image *EntityImage) *EntityImageRespond {
+	if image == nil {
+		return nil
+	}
+	return &EntityImageRespond{
+		ID:           image.ID,
+		URL:          image.URL,
+		ThumbnailURL: image.ThumbnailURL,
+	}
+}
+
+func NewEntityImagesRespond(images []*EntityImage) []*EntityImageRespond {
+	responds := []*EntityImageRespond{}
+	for _, image := range images {
+		responds = append(responds, NewEntityImageRespond(image))
+	}
+	return responds
+}
+
+type EntityImageRespond struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailURL"`
 }
 
 // POST /transfers
@@ -175,6 +279,223 @@ type ProposeTransferRespond struct {
 	CreatedAt   *time.Time `json:"dateProposed,omitempty"`
 }
 
+// GET /notifications
+
+func NewNotificationsRespond(notifications []*Notification) []*NotificationRespond {
+	respond := []*NotificationRespond{}
+	for _, n := range notifications {
+		respond = append(respond, &NotificationRespond{
+			ID:        n.ID.Hex(),
+			Type:      n.Type,
+			Message:   n.Message,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return respond
+}
+
+type NotificationRespond struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// POST /payment-requests
+// GET /payment-requests/{token}
+
+func NewPaymentRequestRespond(p *PaymentRequest, link string) *PaymentRequestRespond {
+	return &PaymentRequestRespond{
+		Token:       p.Token,
+		Link:        link,
+		Payee:       p.AccountNumber,
+		EntityName:  p.EntityName,
+		Amount:      p.Amount,
+		Description: p.Description,
+		SingleUse:   p.SingleUse,
+		ExpiresAt:   &p.ExpiresAt,
+	}
+}
+
+type PaymentRequestRespond struct {
+	Token       string     `json:"token"`
+	Link        string     `json:"link"`
+	Payee       string     `json:"payee"`
+	EntityName  string     `json:"entityName"`
+	Amount      float64    `json:"amount"`
+	Description string     `json:"description"`
+	SingleUse   bool       `json:"singleUse"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// GET /user/entities/{entityID}/members
+
+func NewEntityMemberRespond(user *User, role string) *EntityMemberRespond {
+	return &EntityMemberRespond{
+		UserID:    user.ID.Hex(),
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Role:      role,
+	}
+}
+
+type EntityMemberRespond struct {
+	UserID    string `json:"userID"`
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Role      string `json:"role"`
+}
+
+// GET /user/entities/{entityID}/invitations
+
+func NewInvitationRespond(inv *Invitation, expiresAt time.Time) *InvitationRespond {
+	return &InvitationRespond{
+		ID:         inv.ID.Hex(),
+		EntityID:   inv.EntityID.Hex(),
+		EntityName: inv.EntityName,
+		Email:      inv.Email,
+		Role:       inv.Role,
+		CreatedAt:  inv.CreatedAt,
+		ExpiresAt:  expiresAt,
+	}
+}
+
+// GET /user/entities/{entityID}/ownership-transfer
+
+type OwnershipTransferRespond struct {
+	ID                 string    `json:"id"`
+	EntityID           string    `json:"entityID"`
+	EntityName         string    `json:"entityName"`
+	Email              string    `json:"email"`
+	NominatedBy        string    `json:"nominatedBy"`
+	PreviousOwnersRole string    `json:"previousOwnersRole"`
+	UpdateEntityEmail  bool      `json:"updateEntityEmail"`
+	CreatedAt          time.Time `json:"createdAt"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+func NewOwnershipTransferRespond(transfer *OwnershipTransfer, expiresAt time.Time) *OwnershipTransferRespond {
+	return &OwnershipTransferRespond{
+		ID:                 transfer.ID.Hex(),
+		EntityID:           transfer.EntityID.Hex(),
+		EntityName:         transfer.EntityName,
+		Email:              transfer.Email,
+		NominatedBy:        transfer.NominatedBy,
+		PreviousOwnersRole: transfer.PreviousOwnersRole,
+		UpdateEntityEmail:  transfer.UpdateEntityEmail,
+		CreatedAt:          transfer.CreatedAt,
+		ExpiresAt:          expiresAt,
+	}
+}
+
+// GET /user/entities/{entityID}/api-keys
+
+type APIKeyRespond struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowedIPs"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIP,omitempty"`
+	// Key is only returned when the key is created.
+	Key string `json:"key,omitempty"`
+}
+
+func NewAPIKeyRespond(key *APIKey) *APIKeyRespond {
+	res := &APIKeyRespond{
+		ID:         key.ID.Hex(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		CreatedAt:  key.CreatedAt,
+		LastUsedIP: key.LastUsedIP,
+	}
+	if res.AllowedIPs == nil {
+		res.AllowedIPs = []string{}
+	}
+	if !key.LastUsedAt.IsZero() {
+		res.LastUsedAt = &key.LastUsedAt
+	}
+	return res
+}
+
+// GET /user/sessions
+// GET /admin/users/{userID}/sessions
+
+type SessionRespond struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	LastSeenIP string    `json:"lastSeenIP"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	// Current marks the session the request was made with.
+	Current bool `json:"current"`
+	// Impersonation marks the sessions started by an admin to act as the user.
+	Impersonation bool `json:"impersonation,omitempty"`
+}
+
+func NewSessionRespond(session *RefreshToken, currentSessionID string) *SessionRespond {
+	res := &SessionRespond{
+		ID:            session.ID.Hex(),
+		Device:        session.Device.Name,
+		UserAgent:     session.Device.UserAgent,
+		IP:            session.Device.IP,
+		CreatedAt:     session.CreatedAt,
+		LastSeenAt:    session.LastSeenAt,
+		LastSeenIP:    session.LastSeenIP,
+		ExpiresAt:     session.ExpiresAt,
+		Current:       session.ID.Hex() == currentSessionID,
+		Impersonation: !session.ImpersonatedBy.IsZero(),
+	}
+	// Sessions started before the devices were recorded.
+	if res.Device == "" {
+		res.Device = "Unknown device"
+	}
+	if res.LastSeenAt.IsZero() {
+		res.LastSeenAt = session.UpdatedAt
+	}
+	return res
+}
+
+// GET /user/export
+
+type DataExportRespond struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+func NewDataExportRespond(export *DataExport) *DataExportRespond {
+	res := &DataExportRespond{
+		ID:        export.ID.Hex(),
+		Status:    export.Status,
+		CreatedAt: export.CreatedAt,
+	}
+	if !export.CompletedAt.IsZero() {
+		res.CompletedAt = &export.CompletedAt
+	}
+	return res
+}
+
+type InvitationRespond struct {
+	ID         string    `json:"id"`
+	EntityID   string    `json:"entityID"`
+	EntityName string    `json:"entityName"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
 // GET /transfers
 
 func NewJournalsToTransfersRespond(journals []*Journal, queryingAccountNumber string) []*TransferRespond {
@@ -237,6 +558,7 @@ func NewAdminEntityRespond(entity *Entity) *AdminEntityRespond {
 		AccountNumber:                      entity.AccountNumber,
 		Name:                               entity.Name,
 		Email:                              entity.Email,
+		EmailVerified:                      !entity.EmailUnverified,
 		Telephone:                          entity.Telephone,
 		IncType:                            entity.IncType,
 		CompanyNumber:                      entity.CompanyNumber,
@@ -262,6 +584,7 @@ type AdminEntityRespond struct {
 	AccountNumber                      string   `json:"accountNumber"`
 	Name                               string   `json:"name"`
 	Email                              string   `json:"email,omitempty"`
+	EmailVerified                      bool     `json:"emailVerified"`
 	Telephone                          string   `json:"telephone"`
 	IncType                            string   `json:"incType"`
 	CompanyNumber                      string   `json:"companyNumber"`
@@ -283,24 +606,26 @@ type AdminEntityRespond struct {
 
 func NewAdminUserRespond(user *User) *AdminUserRespond {
 	return &AdminUserRespond{
-		ID:            user.ID.Hex(),
-		Email:         user.Email,
-		Telephone:     user.Telephone,
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		LastLoginIP:   user.LastLoginIP,
-		LastLoginDate: user.LastLoginDate,
+		ID:               user.ID.Hex(),
+		Email:            user.Email,
+		Telephone:        user.Telephone,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		LastLoginIP:      user.LastLoginIP,
+		LastLoginDate:    user.LastLoginDate,
+		TwoFactorEnabled: user.TwoFactor.Enabled(),
 	}
 }
 
 type AdminUserRespond struct {
-	ID            string    `json:"id"`
-	Email         string    `json:"email"`
-	FirstName     string    `json:"firstName"`
-	LastName      string    `json:"lastName"`
-	Telephone     string    `json:"telephone"`
-	LastLoginIP   string    `json:"lastLoginIP"`
-	LastLoginDate time.Time `json:"lastLoginDate"`
+	ID               string    `json:"id"`
+	Email            string    `json:"email"`
+	FirstName        string    `json:"firstName"`
+	LastName         string    `json:"lastName"`
+	Telephone        string    `json:"telephone"`
+	LastLoginIP      string    `json:"lastLoginIP"`
+	LastLoginDate    time.Time `json:"lastLoginDate"`
+	TwoFactorEnabled bool      `json:"twoFactorEnabled"`
 }
 
 // Category
@@ -370,6 +695,7 @@ func NewAdminGetUserRespond(user *User, entities []*Entity) *AdminGetUserRespond
 		LastName:      user.LastName,
 		LastLoginIP:   user.LastLoginIP,
 		LastLoginDate: user.LastLoginDate,
+		EmailVerified: !user.EmailUnverified,
 		Entities:      adminEntityResponds,
 	}
 }
@@ -382,6 +708,7 @@ type AdminGetUserRespond struct {
 	Telephone     string                `json:"telephone"`
 	LastLoginIP   string                `json:"lastLoginIP"`
 	LastLoginDate time.Time             `json:"lastLoginDate"`
+	EmailVerified bool                  `json:"emailVerified"`
 	Entities      []*AdminEntityRespond `json:"entities"`
 }
 
@@ -409,6 +736,44 @@ type AdminDeleteUserRespond struct {
 	LastLoginDate time.Time `json:"lastLoginDate"`
 }
 
+// POST /admin/users/{userID}/erasure
+
+func NewAdminEraseUserRespond(user *User, entities []*Entity) *AdminEraseUserRespond {
+	res := &AdminEraseUserRespond{
+		ID:             user.ID.Hex(),
+		Email:          user.Email,
+		ErasedAt:       user.ErasedAt,
+		ErasedEntities: []*AdminEraseEntityRespond{},
+	}
+	for _, entity := range entities {
+		res.ErasedEntities = append(res.ErasedEntities, NewAdminEraseEntityRespond(entity))
+	}
+	return res
+}
+
+type AdminEraseUserRespond struct {
+	ID             string                     `json:"id"`
+	Email          string                     `json:"email"`
+	ErasedAt       time.Time                  `json:"erasedAt"`
+	ErasedEntities []*AdminEraseEntityRespond `json:"erasedEntities"`
+}
+
+// POST /admin/entities/{entityID}/erasure
+
+func NewAdminEraseEntityRespond(entity *Entity) *AdminEraseEntityRespond {
+	return &AdminEraseEntityRespond{
+		ID:            entity.ID.Hex(),
+		AccountNumber: entity.AccountNumber,
+		Name:          entity.Name,
+	}
+}
+
+type AdminEraseEntityRespond struct {
+	ID            string `json:"id"`
+	AccountNumber string `json:"accountNumber"`
+	Name          string `json:"name"`
+}
+
 // GET /admin/entities
 
 func NewAdminSearchEntityRespond(
@@ -498,6 +863,7 @@ func NewAdminGetEntityRespond(
 		AccountNumber:                      entity.AccountNumber,
 		Name:                               entity.Name,
 		Email:                              entity.Email,
+		EmailVerified:                      !entity.EmailUnverified,
 		Telephone:                          entity.Telephone,
 		IncType:                            entity.IncType,
 		CompanyNumber:                      entity.CompanyNumber,
@@ -518,6 +884,9 @@ func NewAdminGetEntityRespond(
 		Balance:                            account.Balance,
 		MaxNegativeBalance:                 balanceLimit.MaxNegBal,
 		MaxPositiveBalance:                 balanceLimit.MaxPosBal,
+		MaxDailyOutgoingAmount:             balanceLimit.MaxDailyOutAmount,
+		MaxMonthlyOutgoingAmount:           balanceLimit.MaxMonthlyOutAmount,
+		MaxDailyOutgoingTransfers:          balanceLimit.MaxDailyOutCount,
 		PendingTransfers:                   pendingTransfers,
 		Users:                              adminUserResponds,
 	}
@@ -528,6 +897,7 @@ type AdminGetEntityRespond struct {
 	AccountNumber                      string                  `json:"accountNumber"`
 	Name                               string                  `json:"name"`
 	Email                              string                  `json:"email,omitempty"`
+	EmailVerified                      bool                    `json:"emailVerified"`
 	Telephone                          string                  `json:"telephone"`
 	IncType                            string                  `json:"incType"`
 	CompanyNumber                      string                  `json:"companyNumber"`
@@ -548,10 +918,119 @@ type AdminGetEntityRespond struct {
 	Balance                            float64                 `json:"balance"`
 	MaxPositiveBalance                 float64                 `json:"maxPositiveBalance"`
 	MaxNegativeBalance                 float64                 `json:"maxNegativeBalance"`
+	MaxDailyOutgoingAmount             *float64                `json:"maxDailyOutgoingAmount"`
+	MaxMonthlyOutgoingAmount           *float64                `json:"maxMonthlyOutgoingAmount"`
+	MaxDailyOutgoingTransfers          *int                    `json:"maxDailyOutgoingTransfers"`
 	PendingTransfers                   []*AdminTransferRespond `json:"pendingTransfers"`
 	Users                              []*AdminUserRespond     `json:"users"`
 }
 
+// GET /admin/entities/{entityID}/status-history
+
+func NewEntityStatusChangeRespond(change *EntityStatusChange) *EntityStatusChangeRespond {
+	return &EntityStatusChangeRespond{
+		From:      change.From,
+		To:        change.To,
+		Reason:    change.Reason,
+		ChangedBy: change.ChangedBy,
+		ChangedAt: change.CreatedAt,
+	}
+}
+
+type EntityStatusChangeRespond struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedBy string    `json:"changedBy"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// GET /admin/applications
+
+func NewAdminApplicationRespond(application *Application, entity *Entity) *AdminApplicationRespond {
+	respond := &AdminApplicationRespond{
+		ApplicationRespond: NewApplicationRespond(application),
+		ReviewerEmail:      application.ReviewerEmail,
+		Notes:              []*ApplicationNoteRespond{},
+		DecidedBy:          application.DecidedBy,
+	}
+	if !application.ReviewerID.IsZero() {
+		respond.ReviewerID = application.ReviewerID.Hex()
+	}
+	for _, n := range application.Notes {
+		respond.Notes = append(respond.Notes, &ApplicationNoteRespond{
+			Body:      n.Body,
+			CreatedBy: n.CreatedBy,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	if entity != nil {
+		respond.Entity = NewAdminEntityRespond(entity)
+	}
+	return respond
+}
+
+type AdminApplicationRespond struct {
+	*ApplicationRespond
+	Entity        *AdminEntityRespond       `json:"entity,omitempty"`
+	ReviewerID    string                    `json:"reviewerID,omitempty"`
+	ReviewerEmail string                    `json:"reviewerEmail,omitempty"`
+	Notes         []*ApplicationNoteRespond `json:"notes"`
+	DecidedBy     string                    `json:"decidedBy,omitempty"`
+}
+
+type ApplicationNoteRespond struct {
+	Body      string    `json:"body"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GET /user/entities/{entityID}/application
+
+// NewApplicationRespond leaves out the reviewer and the internal notes so it can be shown to the applicant.
+func NewApplicationRespond(application *Application) *ApplicationRespond {
+	respond := &ApplicationRespond{
+		ID:             application.ID.Hex(),
+		EntityID:       application.EntityID.Hex(),
+		Status:         application.Status,
+		SubmittedAt:    application.SubmittedAt,
+		InfoRequests:   []*ApplicationInfoRequestRespond{},
+		DecisionReason: application.DecisionReason,
+	}
+	for _, i := range application.InfoRequests {
+		infoRequest := &ApplicationInfoRequestRespond{
+			Message:     i.Message,
+			RequestedAt: i.RequestedAt,
+			Response:    i.Response,
+		}
+		if !i.RespondedAt.IsZero() {
+			infoRequest.RespondedAt = &i.RespondedAt
+		}
+		respond.InfoRequests = append(respond.InfoRequests, infoRequest)
+	}
+	if !application.DecidedAt.IsZero() {
+		respond.DecidedAt = &application.DecidedAt
+	}
+	return respond
+}
+
+type ApplicationRespond struct {
+	ID             string                           `json:"id"`
+	EntityID       string                           `json:"entityID"`
+	Status         string                           `json:"status"`
+	SubmittedAt    time.Time                        `json:"submittedAt"`
+	InfoRequests   []*ApplicationInfoRequestRespond `json:"infoRequests"`
+	DecisionReason string                           `json:"decisionReason,omitempty"`
+	DecidedAt      *time.Time                       `json:"decidedAt,omitempty"`
+}
+
+type ApplicationInfoRequestRespond struct {
+	Message     string     `json:"message"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	Response    string     `json:"response,omitempty"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+}
+
 // PATCH /admin/entities/{entityID}
 
 func NewAdminUpdateEntityRespond(users []*User, entity *Entity, balanceLimit *BalanceLimit) *AdminUpdateEntityRespond {
@@ -583,6 +1062,9 @@ func NewAdminUpdateEntityRespond(users []*User, entity *Entity, balanceLimit *Ba
 		ReceiveDailyMatchNotificationEmail: util.ToBool(entity.ReceiveDailyMatchNotificationEmail),
 		MaxPositiveBalance:                 balanceLimit.MaxPosBal,
 		MaxNegativeBalance:                 balanceLimit.MaxNegBal,
+		MaxDailyOutgoingAmount:             balanceLimit.MaxDailyOutAmount,
+		MaxMonthlyOutgoingAmount:           balanceLimit.MaxMonthlyOutAmount,
+		MaxDailyOutgoingTransfers:          balanceLimit.MaxDailyOutCount,
 		Users:                              adminUserResponds,
 		BalanceLimit:                       balanceLimit,
 	}
@@ -613,6 +1095,9 @@ type AdminUpdateEntityRespond struct {
 	ReceiveDailyMatchNotificationEmail bool                `json:"receiveDailyMatchNotificationEmail"`
 	MaxPositiveBalance                 float64             `json:"maxPositiveBalance"`
 	MaxNegativeBalance                 float64             `json:"maxNegativeBalance"`
+	MaxDailyOutgoingAmount             *float64            `json:"maxDailyOutgoingAmount"`
+	MaxMonthlyOutgoingAmount           *float64            `json:"maxMonthlyOutgoingAmount"`
+	MaxDailyOutgoingTransfers          *int                `json:"maxDailyOutgoingTransfers"`
 	Users                              []*AdminUserRespond `json:"users"`
 	// To log user action.
 	BalanceLimit *BalanceLimit `json:"-"`
@@ -620,6 +1105,25 @@ type AdminUpdateEntityRespond struct {
 
 // DELETE /admin/entities/{entityID}
 
+// POST /admin/entities/{entityID}/merge
+
+func NewAdminMergeEntityRespond(result *MergeEntityResult) *AdminMergeEntityRespond {
+	res := &AdminMergeEntityRespond{
+		Entity:           NewAdminEntityRespond(result.Entity),
+		PendingTransfers: NewJournalsToAdminTransfersRespond(result.PendingTransfers),
+	}
+	if result.Journal != nil {
+		res.Transfer = NewJournalToAdminTransferRespond(result.Journal)
+	}
+	return res
+}
+
+type AdminMergeEntityRespond struct {
+	Entity           *AdminEntityRespond     `json:"entity"`
+	Transfer         *AdminTransferRespond   `json:"transfer,omitempty"`
+	PendingTransfers []*AdminTransferRespond `json:"pendingTransfers"`
+}
+
 func NewAdminDeleteEntityRespond(entity *Entity) *AdminDeleteEntityRespond {
 	return &AdminDeleteEntityRespond{
 		ID:                                 entity.ID.Hex(),
@@ -745,3 +1249,197 @@ func NewJournalToAdminTransferRespond(j *Journal) *AdminTransferRespond {
 	}
 	return res
 }
+
+// POST /admin/ledger/accounts
+// GET /admin/ledger/accounts
+
+func NewLedgerAccountRespond(a *LedgerAccount) *LedgerAccountRespond {
+	return &LedgerAccountRespond{
+		Code:        a.Code,
+		Name:        a.Name,
+		Type:        a.Type,
+		Description: a.Description,
+	}
+}
+
+type LedgerAccountRespond struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// POST /admin/ledger/journals
+
+func NewLedgerJournalRespond(j *LedgerJournal) *LedgerJournalRespond {
+	postings := []*LedgerPostingRespond{}
+	for _, p := range j.LedgerPostings {
+		posting := &LedgerPostingRespond{Account: p.AccountCode}
+		if p.Amount > 0 {
+			posting.Debit = p.Amount
+		} else {
+			posting.Credit = -p.Amount
+		}
+		postings = append(postings, posting)
+	}
+	return &LedgerJournalRespond{
+		ID:          j.EntryID,
+		Type:        j.Type,
+		Description: j.Description,
+		PostedBy:    j.PostedBy,
+		Date:        j.Date,
+		Postings:    postings,
+	}
+}
+
+type LedgerJournalRespond struct {
+	ID          string                  `json:"id"`
+	Type        string                  `json:"type"`
+	Description string                  `json:"description"`
+	PostedBy    string                  `json:"postedBy"`
+	Date        time.Time               `json:"date"`
+	Postings    []*LedgerPostingRespond `json:"postings"`
+}
+
+type LedgerPostingRespond struct {
+	Account string  `json:"account"`
+	Debit   float64 `json:"debit"`
+	Credit  float64 `json:"credit"`
+}
+
+// GET /admin/ledger/trial-balance
+
+func NewTrialBalanceRespond(date time.Time, balances []*LedgerAccountBalance) *TrialBalanceRespond {
+	respond := &TrialBalanceRespond{
+		Date:     date,
+		Accounts: []*TrialBalanceAccountRespond{},
+	}
+	for _, b := range balances {
+		account := &TrialBalanceAccountRespond{
+			Code: b.Code,
+			Name: b.Name,
+			Type: b.Type,
+		}
+		if b.Balance > 0 {
+			account.Debit = b.Balance
+		} else {
+			account.Credit = -b.Balance
+		}
+		respond.TotalDebit += account.Debit
+		respond.TotalCredit += account.Credit
+		respond.Accounts = append(respond.Accounts, account)
+	}
+	respond.TotalDebit = math.Round(respond.TotalDebit*100) / 100
+	respond.TotalCredit = math.Round(respond.TotalCredit*100) / 100
+	return respond
+}
+
+type TrialBalanceRespond struct {
+	Date        time.Time                     `json:"date"`
+	Accounts    []*TrialBalanceAccountRespond `json:"accounts"`
+	TotalDebit  float64                       `json:"totalDebit"`
+	TotalCredit float64                       `json:"totalCredit"`
+}
+
+type TrialBalanceAccountRespond struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Type   string  `json:"type"`
+	Debit  float64 `json:"debit"`
+	Credit float64 `json:"credit"`
+}
+
+// GET /admin/ledger/income-statement
+
+func NewIncomeStatementRespond(dateFrom time.Time, dateTo time.Time, balances []*LedgerAccountBalance) *IncomeStatementRespond {
+	respond := &IncomeStatementRespond{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+		Income:   []*IncomeStatementAccountRespond{},
+		Expenses: []*IncomeStatementAccountRespond{},
+	}
+	for _, b := range balances {
+		// Income accounts have a credit balance and expense accounts have a debit balance.
+		if b.Type == constant.LedgerAccountType.Income {
+			respond.Income = append(respond.Income, &IncomeStatementAccountRespond{Code: b.Code, Name: b.Name, Amount: -b.Balance})
+			respond.TotalIncome -= b.Balance
+		}
+		if b.Type == constant.LedgerAccountType.Expense {
+			respond.Expenses = append(respond.Expenses, &IncomeStatementAccountRespond{Code: b.Code, Name: b.Name, Amount: b.Balance})
+			respond.TotalExpenses += b.Balance
+		}
+	}
+	respond.TotalIncome = math.Round(respond.TotalIncome*100) / 100
+	respond.TotalExpenses = math.Round(respond.TotalExpenses*100) / 100
+	respond.NetIncome = math.Round((respond.TotalIncome-respond.TotalExpenses)*100) / 100
+	return respond
+}
+
+type IncomeStatementRespond struct {
+	DateFrom      time.Time                        `json:"dateFrom"`
+	DateTo        time.Time                        `json:"dateTo"`
+	Income        []*IncomeStatementAccountRespond `json:"income"`
+	Expenses      []*IncomeStatementAccountRespond `json:"expenses"`
+	TotalIncome   float64                          `json:"totalIncome"`
+	TotalExpenses float64                          `json:"totalExpenses"`
+	NetIncome     float64                          `json:"netIncome"`
+}
+
+type IncomeStatementAccountRespond struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+// GET /admin/admin-users
+
+// AdminAccountRespond describes an admin user, AdminUserRespond describes a user as seen by the admins.
+func NewAdminAccountRespond(admin *AdminUser) *AdminAccountRespond {
+	roles := admin.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	return &AdminAccountRespond{
+		ID:               admin.ID.Hex(),
+		Email:            admin.Email,
+		Name:             admin.Name,
+		Roles:            roles,
+		TwoFactorEnabled: admin.TwoFactor.Enabled(),
+		LastLoginIP:      admin.LastLoginIP,
+		LastLoginDate:    admin.LastLoginDate,
+		CreatedAt:        admin.CreatedAt,
+	}
+}
+
+type AdminAccountRespond struct {
+	ID               string    `json:"id"`
+	Email            string    `json:"email"`
+	Name             string    `json:"name"`
+	Roles            []string  `json:"roles"`
+	TwoFactorEnabled bool      `json:"twoFactorEnabled"`
+	LastLoginIP      string    `json:"lastLoginIP"`
+	LastLoginDate    time.Time `json:"lastLoginDate"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// GET /admin/roles
+
+func NewAdminRoleRespond(role *AdminRole) *AdminRoleRespond {
+	permissions := role.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return &AdminRoleRespond{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		BuiltIn:     role.ID.IsZero(),
+	}
+}
+
+type AdminRoleRespond struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"builtIn"`
+}
@@ -19,10 +19,41 @@ type EntityESRecord struct {
 	Balance       *float64 `json:"balance,omitempty"`
 	MaxNegBal     *float64 `json:"maxNegBal,omitempty"`
 	MaxPosBal     *float64 `json:"maxPosBal,omitempty"`
+	// AccountAliases are the account numbers of the entities merged into this one.
+	AccountAliases []string `json:"accountAliases,omitempty"`
+	// Images
+	LogoURL          string   `json:"logoURL,omitempty"`
+	LogoThumbnailURL string   `json:"logoThumbnailURL,omitempty"`
+	ImageURLs        []string `json:"imageURLs,omitempty"`
+	// Geo
+	Location *ESGeoPoint `json:"location,omitempty"`
+}
+
+// ESGeoPoint is the geo_point representation of the entity location.
+type ESGeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func NewESGeoPoint(location *GeoLocation) *ESGeoPoint {
+	if location == nil {
+		return nil
+	}
+	return &ESGeoPoint{Lat: location.Lat, Lon: location.Lon}
+}
+
+func EntityImageURLs(images []*EntityImage) []string {
+	urls := []string{}
+	for _, image := range images {
+		urls = append(urls, image.URL)
+	}
+	return urls
 }
 
 type ESSearchEntityResult struct {
 	IDs             []string
 	NumberOfResults int
 	TotalPages      int
+	// Distances in km by entity ID when searching near a location.
+	Distances map[string]float64
 }
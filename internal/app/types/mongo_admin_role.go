@@ -0,0 +1,19 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdminRole is the model representation of an admin role in the data model.
+type AdminRole struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	// Name is referenced by AdminUser.Roles and cannot be changed.
+	Name        string   `json:"name,omitempty" bson:"name,omitempty"`
+	Description string   `json:"description,omitempty" bson:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty" bson:"permissions,omitempty"`
+}
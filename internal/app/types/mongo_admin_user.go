@@ -18,6 +18,12 @@ type AdminUser struct {
 	Password string   `json:"password,omitempty" bson:"password,omitempty"`
 	Roles    []string `json:"roles,omitempty" bson:"roles,omitempty"`
 
+	TwoFactor *TwoFactor `json:"twoFactor,omitempty" bson:"twoFactor,omitempty"`
+
+	// PasswordHistory holds the hashes of the previous passwords which cannot be reused.
+	PasswordHistory   []string  `json:"passwordHistory,omitempty" bson:"passwordHistory,omitempty"`
+	PasswordChangedAt time.Time `json:"passwordChangedAt,omitempty" bson:"passwordChangedAt,omitempty"`
+
 	CurrentLoginIP   string    `json:"currentLoginIP,omitempty" bson:"currentLoginIP,omitempty"`
 	CurrentLoginDate time.Time `json:"currentLoginDate,omitempty" bson:"currentLoginDate,omitempty"`
 	LastLoginIP      string    `json:"lastLoginIP,omitempty" bson:"lastLoginIP,omitempty"`
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKey is the model representation of an entity API key in the data model.
+// Requests made with the key act as the member who created it, limited to the entity and the scopes.
+type APIKey struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	EntityID  primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	CreatedBy primitive.ObjectID `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	Name      string             `json:"name,omitempty" bson:"name,omitempty"`
+	// Prefix is the beginning of the key, it helps members to tell their keys apart.
+	Prefix string `json:"prefix,omitempty" bson:"prefix,omitempty"`
+	// KeyHash is the SHA-256 hash of the key, the key itself is only shown once.
+	KeyHash    string    `json:"keyHash,omitempty" bson:"keyHash,omitempty"`
+	Scopes     []string  `json:"scopes,omitempty" bson:"scopes,omitempty"`
+	AllowedIPs []string  `json:"allowedIPs,omitempty" bson:"allowedIPs,omitempty"`
+	LastUsedAt time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+	LastUsedIP string    `json:"lastUsedIP,omitempty" bson:"lastUsedIP,omitempty"`
+	RevokedAt  time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+}
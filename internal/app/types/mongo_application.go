@@ -0,0 +1,51 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Application is the model representation of a membership application in the data model.
+type Application struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	EntityID    primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	Status      string             `json:"status,omitempty" bson:"status,omitempty"`
+	SubmittedAt time.Time          `json:"submittedAt,omitempty" bson:"submittedAt,omitempty"`
+
+	ReviewerID    primitive.ObjectID `json:"reviewerID,omitempty" bson:"reviewerID,omitempty"`
+	ReviewerEmail string             `json:"reviewerEmail,omitempty" bson:"reviewerEmail,omitempty"`
+
+	// Internal notes are only visible to admins.
+	Notes        []*ApplicationNote        `json:"notes,omitempty" bson:"notes,omitempty"`
+	InfoRequests []*ApplicationInfoRequest `json:"infoRequests,omitempty" bson:"infoRequests,omitempty"`
+
+	DecisionReason string    `json:"decisionReason,omitempty" bson:"decisionReason,omitempty"`
+	DecidedBy      string    `json:"decidedBy,omitempty" bson:"decidedBy,omitempty"`
+	DecidedAt      time.Time `json:"decidedAt,omitempty" bson:"decidedAt,omitempty"`
+}
+
+type ApplicationNote struct {
+	Body      string    `json:"body,omitempty" bson:"body,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+}
+
+type ApplicationInfoRequest struct {
+	Message     string    `json:"message,omitempty" bson:"message,omitempty"`
+	RequestedBy string    `json:"requestedBy,omitempty" bson:"requestedBy,omitempty"`
+	RequestedAt time.Time `json:"requestedAt,omitempty" bson:"requestedAt,omitempty"`
+	Response    string    `json:"response,omitempty" bson:"response,omitempty"`
+	RespondedAt time.Time `json:"respondedAt,omitempty" bson:"respondedAt,omitempty"`
+}
+
+// Helper types
+
+type SearchApplicationResult struct {
+	Applications    []*Application
+	NumberOfResults int
+	TotalPages      int
+}
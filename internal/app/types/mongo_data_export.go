@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DataExport is the model representation of a personal data export in the data model.
+type DataExport struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	UserID primitive.ObjectID `json:"userID,omitempty" bson:"userID,omitempty"`
+	// Email address of the user or the admin who requested the export, the download link is sent to it.
+	RequestedBy string `json:"requestedBy,omitempty" bson:"requestedBy,omitempty"`
+	Status      string `json:"status,omitempty" bson:"status,omitempty"`
+	Token       string `json:"token,omitempty" bson:"token,omitempty"`
+
+	Key         string    `json:"key,omitempty" bson:"key,omitempty"`
+	Size        int       `json:"size,omitempty" bson:"size,omitempty"`
+	CompletedAt time.Time `json:"completedAt,omitempty" bson:"completedAt,omitempty"`
+}
@@ -0,0 +1,22 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailVerification is the model representation of an email verification token in the data model.
+// It confirms the email address of either a user or an entity.
+type EmailVerification struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+
+	UserID   primitive.ObjectID `json:"userID,omitempty" bson:"userID,omitempty"`
+	EntityID primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	Email    string             `json:"email,omitempty" bson:"email,omitempty"`
+	Token    string             `json:"token,omitempty" bson:"token,omitempty"`
+	// Signup is set on the token sent to a new user, the welcome email follows its confirmation.
+	Signup     bool      `json:"signup,omitempty" bson:"signup,omitempty"`
+	VerifiedAt time.Time `json:"verifiedAt,omitempty" bson:"verifiedAt,omitempty"`
+}
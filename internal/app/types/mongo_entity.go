@@ -14,8 +14,11 @@ type Entity struct {
 	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
 	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
 	DeletedAt time.Time          `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	// Timestamp when the personal data was anonymised
+	ErasedAt time.Time `json:"erasedAt,omitempty" bson:"erasedAt,omitempty"`
 
-	Users []primitive.ObjectID `json:"users,omitempty" bson:"users,omitempty"`
+	Users   []primitive.ObjectID `json:"users,omitempty" bson:"users,omitempty"`
+	Members []*EntityMember      `json:"members,omitempty" bson:"members,omitempty"`
 
 	Name             string      `json:"name,omitempty" bson:"name,omitempty"`
 	Telephone        string      `json:"telephone,omitempty" bson:"telephone,omitempty"`
@@ -37,14 +40,109 @@ type Entity struct {
 	// Timestamp when trading status applied
 	MemberStartedAt time.Time `json:"memberStartedAt,omitempty" bson:"memberStartedAt,omitempty"`
 
+	// EmailUnverified is set until the entity email address is confirmed.
+	EmailUnverified bool      `json:"emailUnverified,omitempty" bson:"emailUnverified,omitempty"`
+	EmailVerifiedAt time.Time `json:"emailVerifiedAt,omitempty" bson:"emailVerifiedAt,omitempty"`
+
 	// flags
 	ShowTagsMatchedSinceLastLogin      *bool `json:"showTagsMatchedSinceLastLogin,omitempty" bson:"showTagsMatchedSinceLastLogin,omitempty"`
 	ReceiveDailyMatchNotificationEmail *bool `json:"receiveDailyMatchNotificationEmail,omitempty" bson:"receiveDailyMatchNotificationEmail,omitempty"`
 
 	LastNotificationSentDate time.Time `json:"lastNotificationSentDate,omitempty" bson:"lastNotificationSentDate,omitempty"`
 
-	AccountNumber    string               `json:"accountNumber,omitempty" bson:"accountNumber,omitempty"`
+	AccountNumber string `json:"accountNumber,omitempty" bson:"accountNumber,omitempty"`
+	// Account numbers of the entities merged into this one, they still resolve to this entity.
+	AccountAliases []string `json:"accountAliases,omitempty" bson:"accountAliases,omitempty"`
+	// The entity this one has been merged into.
+	MergedInto       primitive.ObjectID   `json:"mergedInto,omitempty" bson:"mergedInto,omitempty"`
 	FavoriteEntities []primitive.ObjectID `json:"favoriteEntities,omitempty" bson:"favoriteEntities,omitempty"`
+
+	BalanceAlerts *BalanceAlerts `json:"balanceAlerts,omitempty" bson:"balanceAlerts,omitempty"`
+
+	Logo   *EntityImage   `json:"logo,omitempty" bson:"logo,omitempty"`
+	Images []*EntityImage `json:"images,omitempty" bson:"images,omitempty"`
+
+	Location *GeoLocation `json:"location,omitempty" bson:"location,omitempty"`
+}
+
+// GeoLocation holds the coordinates of an entity, either set manually or resolved from its address.
+type GeoLocation struct {
+	Lat float64 `json:"lat" bson:"lat"`
+	Lon float64 `json:"lon" bson:"lon"`
+	// Manual coordinates are kept when the address changes.
+	Manual bool `json:"manual,omitempty" bson:"manual,omitempty"`
+}
+
+func (g *GeoLocation) Validate() []error {
+	errs := []error{}
+	if g.Lat < -90 || g.Lat > 90 {
+		errs = append(errs, errors.New("Latitude should be between -90 and 90."))
+	}
+	if g.Lon < -180 || g.Lon > 180 {
+		errs = append(errs, errors.New("Longitude should be between -180 and 180."))
+	}
+	return errs
+}
+
+// EntityImage is an uploaded logo or gallery image together with its thumbnail.
+type EntityImage struct {
+	ID           string    `json:"id,omitempty" bson:"id,omitempty"`
+	Key          string    `json:"key,omitempty" bson:"key,omitempty"`
+	ThumbnailKey string    `json:"thumbnailKey,omitempty" bson:"thumbnailKey,omitempty"`
+	URL          string    `json:"url,omitempty" bson:"url,omitempty"`
+	ThumbnailURL string    `json:"thumbnailURL,omitempty" bson:"thumbnailURL,omitempty"`
+	CreatedAt    time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+}
+
+// AllImages returns the logo and the gallery images of the entity.
+func (entity *Entity) AllImages() []*EntityImage {
+	images := []*EntityImage{}
+	if entity.Logo != nil {
+		images = append(images, entity.Logo)
+	}
+	return append(images, entity.Images...)
+}
+
+// EntityMember holds the role of an associated user within the entity.
+type EntityMember struct {
+	UserID primitive.ObjectID `json:"userID,omitempty" bson:"userID,omitempty"`
+	Role   string             `json:"role,omitempty" bson:"role,omitempty"`
+}
+
+// MemberRole returns the role of the user within the entity, or an empty string if the user is not a member.
+func (entity *Entity) MemberRole(userID primitive.ObjectID) string {
+	for _, m := range entity.Members {
+		if m.UserID == userID {
+			return m.Role
+		}
+	}
+	return ""
+}
+
+// BalanceAlerts holds the thresholds at which the entity will be notified about its balance.
+type BalanceAlerts struct {
+	// Notify when the balance drops below the amount.
+	LowBalance *float64 `json:"lowBalance,omitempty" bson:"lowBalance,omitempty"`
+	// Notify when the balance is within the percentage of the maximum negative balance.
+	MaxNegBalProximity *float64 `json:"maxNegativeBalanceProximity,omitempty" bson:"maxNegBalProximity,omitempty"`
+	// Notify when the balance is within the percentage of the maximum positive balance.
+	MaxPosBalProximity *float64 `json:"maxPositiveBalanceProximity,omitempty" bson:"maxPosBalProximity,omitempty"`
+}
+
+func (b *BalanceAlerts) Validate() []error {
+	errs := []error{}
+
+	if b.LowBalance != nil && !util.IsDecimalValid(*b.LowBalance) {
+		errs = append(errs, errors.New("Low balance alert should be a numeric amount with up to two decimal places."))
+	}
+	if b.MaxNegBalProximity != nil && (*b.MaxNegBalProximity < 0 || *b.MaxNegBalProximity > 100) {
+		errs = append(errs, errors.New("Maximum negative balance proximity should be a percentage between 0 and 100."))
+	}
+	if b.MaxPosBalProximity != nil && (*b.MaxPosBalProximity < 0 || *b.MaxPosBalProximity > 100) {
+		errs = append(errs, errors.New("Maximum positive balance proximity should be a percentage between 0 and 100."))
+	}
+
+	return errs
 }
 
 func (entity *Entity) Validate() []error {
@@ -102,6 +200,15 @@ type SearchEntityResult struct {
 	Entities        []*Entity
 	NumberOfResults int
 	TotalPages      int
+	Distances       map[string]float64
+}
+
+type MergeEntityResult struct {
+	Entity *Entity
+	// Journal moving the balance of the merged entity, nil for a zero balance.
+	Journal *Journal
+	// Pending transfers moved to the kept entity or cancelled.
+	PendingTransfers []*Journal
 }
 
 type UpdateOfferAndWants struct {
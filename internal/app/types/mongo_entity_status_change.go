@@ -0,0 +1,18 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EntityStatusChange is the model representation of a change in the status of an entity in the data model.
+type EntityStatusChange struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	EntityID  primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	From      string             `json:"from,omitempty" bson:"from,omitempty"`
+	To        string             `json:"to,omitempty" bson:"to,omitempty"`
+	Reason    string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	ChangedBy string             `json:"changedBy,omitempty" bson:"changedBy,omitempty"`
+}
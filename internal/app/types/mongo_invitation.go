@@ -0,0 +1,21 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invitation is the model representation of an invitation to join an entity in the data model.
+type Invitation struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	EntityID   primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	EntityName string             `json:"entityName,omitempty" bson:"entityName,omitempty"`
+	Email      string             `json:"email,omitempty" bson:"email,omitempty"`
+	Role       string             `json:"role,omitempty" bson:"role,omitempty"`
+	InvitedBy  primitive.ObjectID `json:"invitedBy,omitempty" bson:"invitedBy,omitempty"`
+	Token      string             `json:"token,omitempty" bson:"token,omitempty"`
+	TokenUsed  bool               `json:"tokenUsed,omitempty" bson:"tokenUsed,omitempty"`
+	Revoked    bool               `json:"revoked,omitempty" bson:"revoked,omitempty"`
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification is the model representation of a stored entity notification in the data model.
+type Notification struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	EntityID  primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	Type      string             `json:"type,omitempty" bson:"type,omitempty"`
+	Message   string             `json:"message,omitempty" bson:"message,omitempty"`
+}
+
+// Helper types
+
+type SearchNotificationResult struct {
+	Notifications   []*Notification
+	NumberOfResults int
+	TotalPages      int
+}
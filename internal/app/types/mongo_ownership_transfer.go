@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OwnershipTransfer is the model representation of a nomination of a new entity owner in the data model.
+type OwnershipTransfer struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	EntityID   primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	EntityName string             `json:"entityName,omitempty" bson:"entityName,omitempty"`
+	// Email address of the nominated owner.
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+	// Email address of the owner or the admin who nominated the new owner.
+	NominatedBy string `json:"nominatedBy,omitempty" bson:"nominatedBy,omitempty"`
+	// Role kept by the previous owners, they are removed from the entity when empty.
+	PreviousOwnersRole string `json:"previousOwnersRole,omitempty" bson:"previousOwnersRole,omitempty"`
+	// Whether the entity email is replaced with the email of the new owner.
+	UpdateEntityEmail bool      `json:"updateEntityEmail,omitempty" bson:"updateEntityEmail,omitempty"`
+	Token             string    `json:"token,omitempty" bson:"token,omitempty"`
+	TokenUsed         bool      `json:"tokenUsed,omitempty" bson:"tokenUsed,omitempty"`
+	Revoked           bool      `json:"revoked,omitempty" bson:"revoked,omitempty"`
+	CompletedAt       time.Time `json:"completedAt,omitempty" bson:"completedAt,omitempty"`
+}
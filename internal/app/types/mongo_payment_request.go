@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PaymentRequest is the model representation of a payment request in the data model.
+type PaymentRequest struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	EntityID      primitive.ObjectID `json:"entityID,omitempty" bson:"entityID,omitempty"`
+	AccountNumber string             `json:"accountNumber,omitempty" bson:"accountNumber,omitempty"`
+	EntityName    string             `json:"entityName,omitempty" bson:"entityName,omitempty"`
+	Amount        float64            `json:"amount,omitempty" bson:"amount,omitempty"`
+	Description   string             `json:"description,omitempty" bson:"description,omitempty"`
+	SingleUse     bool               `json:"singleUse,omitempty" bson:"singleUse,omitempty"`
+	ExpiresAt     time.Time          `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	UsedAt        time.Time          `json:"usedAt,omitempty" bson:"usedAt,omitempty"`
+
+	// Token is the signed representation of the payment request. It is not stored.
+	Token string `json:"-" bson:"-"`
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is the model representation of a refresh token family in the data model.
+// Every login starts a new family, its ID is the session ID carried by the access tokens.
+type RefreshToken struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+
+	UserID primitive.ObjectID `json:"userID,omitempty" bson:"userID,omitempty"`
+	Admin  bool               `json:"admin,omitempty" bson:"admin,omitempty"`
+	// TokenHash is the SHA-256 hash of the current refresh token of the family.
+	TokenHash string `json:"tokenHash,omitempty" bson:"tokenHash,omitempty"`
+	// UsedHashes are the hashes of the rotated refresh tokens, presenting one of them again revokes the family.
+	UsedHashes []string  `json:"usedHashes,omitempty" bson:"usedHashes,omitempty"`
+	ExpiresAt  time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	RevokedAt  time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+
+	Device `bson:",inline"`
+	// LastSeenAt is updated every time the session is refreshed.
+	LastSeenAt time.Time `json:"lastSeenAt,omitempty" bson:"lastSeenAt,omitempty"`
+	LastSeenIP string    `json:"lastSeenIP,omitempty" bson:"lastSeenIP,omitempty"`
+
+	// ImpersonatedBy is set for the sessions an admin started to act as the user, they cannot be refreshed.
+	ImpersonatedBy     primitive.ObjectID `json:"impersonatedBy,omitempty" bson:"impersonatedBy,omitempty"`
+	ImpersonationWrite bool               `json:"impersonationWrite,omitempty" bson:"impersonationWrite,omitempty"`
+}
+
+// Device describes the client a session was started from.
+type Device struct {
+	// ID is the SHA-256 hash of the user agent, it recognises a device the user has logged in from before.
+	ID        string `json:"deviceID,omitempty" bson:"deviceID,omitempty"`
+	Name      string `json:"device,omitempty" bson:"device,omitempty"`
+	UserAgent string `json:"userAgent,omitempty" bson:"userAgent,omitempty"`
+	IP        string `json:"ip,omitempty" bson:"ip,omitempty"`
+}
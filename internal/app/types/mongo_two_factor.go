@@ -0,0 +1,22 @@
+package types
+
+import (
+	"time"
+)
+
+// TwoFactor is the model representation of the TOTP two-factor authentication settings of a user or an admin.
+type TwoFactor struct {
+	// Secret is set once the enrolment has been verified with a code.
+	Secret string `json:"secret,omitempty" bson:"secret,omitempty"`
+	// PendingSecret is the secret shown during the enrolment.
+	PendingSecret string    `json:"pendingSecret,omitempty" bson:"pendingSecret,omitempty"`
+	EnabledAt     time.Time `json:"enabledAt,omitempty" bson:"enabledAt,omitempty"`
+	// LastCounter is the time step of the last accepted code, a code cannot be used twice.
+	LastCounter int64 `json:"lastCounter,omitempty" bson:"lastCounter,omitempty"`
+	// RecoveryCodes are the SHA-256 hashes of the unused recovery codes.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty" bson:"recoveryCodes,omitempty"`
+}
+
+func (t *TwoFactor) Enabled() bool {
+	return t != nil && t.Secret != ""
+}
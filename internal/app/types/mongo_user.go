@@ -14,6 +14,8 @@ type User struct {
 	CreatedAt time.Time          `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
 	UpdatedAt time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
 	DeletedAt time.Time          `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	// Timestamp when the personal data was anonymised
+	ErasedAt time.Time `json:"erasedAt,omitempty" bson:"erasedAt,omitempty"`
 
 	FirstName string               `json:"firstName,omitempty" bson:"firstName,omitempty"`
 	LastName  string               `json:"lastName,omitempty" bson:"lastName,omitempty"`
@@ -21,6 +23,16 @@ type User struct {
 	Password  string               `json:"password,omitempty" bson:"password,omitempty"`
 	Telephone string               `json:"telephone,omitempty" bson:"telephone,omitempty"`
 	Entities  []primitive.ObjectID `json:"entities,omitempty" bson:"entities,omitempty"`
+	TwoFactor *TwoFactor           `json:"twoFactor,omitempty" bson:"twoFactor,omitempty"`
+
+	// PasswordHistory holds the hashes of the previous passwords which cannot be reused.
+	PasswordHistory   []string  `json:"passwordHistory,omitempty" bson:"passwordHistory,omitempty"`
+	PasswordChangedAt time.Time `json:"passwordChangedAt,omitempty" bson:"passwordChangedAt,omitempty"`
+
+	// EmailUnverified is set until the user confirms the email address.
+	// Users who signed up before the email verification do not have it.
+	EmailUnverified bool      `json:"emailUnverified,omitempty" bson:"emailUnverified,omitempty"`
+	EmailVerifiedAt time.Time `json:"emailVerifiedAt,omitempty" bson:"emailVerifiedAt,omitempty"`
 
 	CurrentLoginIP   string    `json:"currentLoginIP,omitempty" bson:"currentLoginIP,omitempty"`
 	CurrentLoginDate time.Time `json:"currentLoginDate,omitempty" bson:"currentLoginDate,omitempty"`
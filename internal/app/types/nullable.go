@@ -0,0 +1,33 @@
+package types
+
+import "encoding/json"
+
+// NullableFloat64 tells an omitted field apart from an explicit null, which clears the stored value.
+type NullableFloat64 struct {
+	Set   bool
+	Value *float64
+}
+
+func (n *NullableFloat64) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// NullableInt tells an omitted field apart from an explicit null, which clears the stored value.
+type NullableInt struct {
+	Set   bool
+	Value *int
+}
+
+func (n *NullableInt) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
@@ -11,4 +11,8 @@ type BalanceLimit struct {
 	AccountNumber string  `json:"accountNumber,omitempty" gorm:"type:varchar(16);not null;unique_index"`
 	MaxNegBal     float64 `json:"maxNegBal,omitempty" gorm:"type:real;not null"`
 	MaxPosBal     float64 `json:"maxPosBal,omitempty" gorm:"type:real;not null"`
+	// Velocity limits, NULL falls back to the network defaults.
+	MaxDailyOutAmount   *float64 `json:"maxDailyOutAmount,omitempty" gorm:"type:real"`
+	MaxMonthlyOutAmount *float64 `json:"maxMonthlyOutAmount,omitempty" gorm:"type:real"`
+	MaxDailyOutCount    *int     `json:"maxDailyOutCount,omitempty" gorm:"type:integer"`
 }
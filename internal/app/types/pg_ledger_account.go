@@ -0,0 +1,23 @@
+package types
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// LedgerAccount is an operator-owned account in the chart of accounts of the general ledger.
+type LedgerAccount struct {
+	gorm.Model
+	Code        string `gorm:"type:varchar(16);not null;unique_index"`
+	Name        string `gorm:"type:varchar(120);not null;default:''"`
+	Type        string `gorm:"type:varchar(16);not null;default:''"`
+	Description string `gorm:"type:varchar(510);not null;default:''"`
+}
+
+// Helper types
+
+type LedgerAccountBalance struct {
+	Code    string
+	Name    string
+	Type    string
+	Balance float64
+}
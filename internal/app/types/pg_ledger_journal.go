@@ -0,0 +1,20 @@
+package types
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// LedgerJournal is a general ledger entry. The sum of its postings must be zero.
+type LedgerJournal struct {
+	gorm.Model
+	// LedgerJournal has many postings, LedgerJournalID is the foreign key
+	LedgerPostings []LedgerPosting
+
+	EntryID     string    `gorm:"type:varchar(27);not null;unique_index"`
+	Type        string    `gorm:"type:varchar(31);not null;default:'manual'"`
+	Description string    `gorm:"type:varchar(510);not null;default:''"`
+	PostedBy    string    `gorm:"type:varchar(100);not null;default:''"`
+	Date        time.Time `gorm:"not null"`
+}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// LedgerPosting is a line of a general ledger entry. Debits are positive and credits are negative.
+type LedgerPosting struct {
+	gorm.Model
+	LedgerJournalID uint    `gorm:"not null;index"`
+	AccountCode     string  `gorm:"type:varchar(16);not null;index"`
+	Amount          float64 `gorm:"type:numeric(16,2);not null"`
+}
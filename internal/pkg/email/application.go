@@ -0,0 +1,40 @@
+package email
+
+import (
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type application struct{}
+
+var Application = &application{}
+
+// Application information requested
+
+type ApplicationInfoRequestEmail struct {
+	EntityName string
+	Email      string
+	Message    string
+}
+
+func (_ *application) InfoRequested(input *ApplicationInfoRequestEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.application_info_requested"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.EntityName+" ", input.Email),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("message", input.Message)
+	p.SetDynamicTemplateData("url", viper.GetString("url"))
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Application.InfoRequested failed", zap.Error(err))
+	}
+}
@@ -1,6 +1,7 @@
 package email
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ic3network/mccs-alpha-api/util/l"
@@ -38,3 +39,80 @@ func (_ *balance) SendNonZeroBalanceEmail(input *NonZeroBalanceEmail) {
 		l.Logger.Error("email.sendNonZeroBalanceEmail failed", zap.Error(err))
 	}
 }
+
+// Balance alerts
+
+type BalanceAlertEmail struct {
+	EntityName string
+	Email      string
+	Balance    float64
+	Threshold  float64
+}
+
+func (b *balance) SendLowBalanceEmail(input *BalanceAlertEmail) {
+	err := b.sendBalanceAlert(viper.GetString("sendgrid.template_id.low_balance_alert"), input)
+	if err != nil {
+		l.Logger.Error("email.SendLowBalanceEmail failed", zap.Error(err))
+	}
+}
+
+func (b *balance) SendMaxNegBalProximityEmail(input *BalanceAlertEmail) {
+	err := b.sendBalanceAlert(viper.GetString("sendgrid.template_id.max_negative_balance_alert"), input)
+	if err != nil {
+		l.Logger.Error("email.SendMaxNegBalProximityEmail failed", zap.Error(err))
+	}
+}
+
+func (b *balance) SendMaxPosBalProximityEmail(input *BalanceAlertEmail) {
+	err := b.sendBalanceAlert(viper.GetString("sendgrid.template_id.max_positive_balance_alert"), input)
+	if err != nil {
+		l.Logger.Error("email.SendMaxPosBalProximityEmail failed", zap.Error(err))
+	}
+}
+
+func (_ *balance) sendBalanceAlert(templateID string, input *BalanceAlertEmail) error {
+	m := e.newEmail(templateID)
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.EntityName+" ", input.Email),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("balance", fmt.Sprintf("%.2f", input.Balance))
+	p.SetDynamicTemplateData("threshold", fmt.Sprintf("%.2f", input.Threshold))
+	p.SetDynamicTemplateData("url", viper.GetString("url"))
+	m.AddPersonalizations(p)
+
+	return e.send(m)
+}
+
+// Write-off
+
+type WriteOffEmail struct {
+	EntityName string
+	Email      string
+	Amount     float64
+	Reason     string
+}
+
+func (_ *balance) SendWriteOffEmail(input *WriteOffEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.write_off"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.EntityName+" ", input.Email),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("amount", fmt.Sprintf("%.2f", input.Amount))
+	p.SetDynamicTemplateData("reason", input.Reason)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.SendWriteOffEmail failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,44 @@
+package email
+
+import (
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type dataExport struct{}
+
+var DataExport = &dataExport{}
+
+// Data export ready
+
+type DataExportReadyEmail struct {
+	Receiver      string
+	ReceiverEmail string
+	// Email address of the user whose data has been exported.
+	UserEmail string
+	Token     string
+}
+
+func (_ *dataExport) Ready(input *DataExportReadyEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.data_export_ready"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.Receiver+" ", input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("receiver", input.Receiver)
+	p.SetDynamicTemplateData("userEmail", input.UserEmail)
+	p.SetDynamicTemplateData("token", input.Token)
+	p.SetDynamicTemplateData("expiresInDays", viper.GetInt("data_export.link_timeout")/86400)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.DataExport.Ready failed", zap.Error(err))
+	}
+}
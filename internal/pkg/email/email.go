@@ -124,6 +124,41 @@ func (_ *Email) passwordReset(input *PasswordResetEmail) {
 	}
 }
 
+// Entity invitation
+
+type InvitationEmail struct {
+	InviterName   string
+	EntityName    string
+	Role          string
+	ReceiverEmail string
+	Token         string
+}
+
+func Invitation(input *InvitationEmail) {
+	e.invitation(input)
+}
+func (_ *Email) invitation(input *InvitationEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.entity_invitation"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.ReceiverEmail, input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("inviterName", input.InviterName)
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("role", input.Role)
+	p.SetDynamicTemplateData("token", input.Token)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Invitation failed", zap.Error(err))
+	}
+}
+
 // Admin password reset
 
 type AdminPasswordResetEmail struct {
@@ -0,0 +1,42 @@
+package email
+
+import (
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type emailVerification struct{}
+
+var EmailVerification = &emailVerification{}
+
+// Email address verification
+
+type EmailVerificationEmail struct {
+	Receiver      string
+	ReceiverEmail string
+	// EntityName is only set when the entity email address is verified.
+	EntityName string
+	Token      string
+}
+
+func (_ *emailVerification) Verify(input *EmailVerificationEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.email_verification"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.Receiver+" ", input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("token", input.Token)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.EmailVerification.Verify failed", zap.Error(err))
+	}
+}
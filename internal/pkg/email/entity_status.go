@@ -0,0 +1,55 @@
+package email
+
+import (
+	"github.com/ic3network/mccs-alpha-api/global/constant"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type entityStatus struct{}
+
+var EntityStatus = &entityStatus{}
+
+// Entity status changed
+
+type EntityStatusEmail struct {
+	EntityName string
+	Email      string
+	Status     string
+	Reason     string
+}
+
+var entityStatusTemplates = map[string]string{
+	constant.Entity.Accepted:  "sendgrid.template_id.entity_accepted",
+	constant.Entity.Rejected:  "sendgrid.template_id.entity_rejected",
+	constant.Trading.Accepted: "sendgrid.template_id.trading_accepted",
+	constant.Trading.Rejected: "sendgrid.template_id.trading_rejected",
+}
+
+// Send notifies the entity about its new status, statuses without a matching template are skipped.
+func (_ *entityStatus) Send(input *EntityStatusEmail) {
+	key, ok := entityStatusTemplates[input.Status]
+	if !ok {
+		return
+	}
+
+	m := e.newEmail(viper.GetString(key))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.EntityName+" ", input.Email),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("reason", input.Reason)
+	p.SetDynamicTemplateData("url", viper.GetString("url"))
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.EntityStatus.Send failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,42 @@
+package email
+
+import (
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type ownershipTransfer struct{}
+
+var OwnershipTransfer = &ownershipTransfer{}
+
+// Entity ownership transfer
+
+type OwnershipTransferEmail struct {
+	NominatedBy   string
+	EntityName    string
+	ReceiverEmail string
+	Token         string
+}
+
+func (_ *ownershipTransfer) Nominated(input *OwnershipTransferEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.ownership_transfer"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.ReceiverEmail, input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("nominatedBy", input.NominatedBy)
+	p.SetDynamicTemplateData("entityName", input.EntityName)
+	p.SetDynamicTemplateData("token", input.Token)
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.OwnershipTransfer.Nominated failed", zap.Error(err))
+	}
+}
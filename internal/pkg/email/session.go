@@ -0,0 +1,45 @@
+package email
+
+import (
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type session struct{}
+
+var Session = &session{}
+
+// New device login
+
+type NewDeviceLoginEmail struct {
+	Receiver      string
+	ReceiverEmail string
+	Device        string
+	IPAddress     string
+	LoginTime     time.Time
+}
+
+func (_ *session) NewDeviceLogin(input *NewDeviceLoginEmail) {
+	m := e.newEmail(viper.GetString("sendgrid.template_id.new_device_login"))
+
+	p := mail.NewPersonalization()
+	tos := []*mail.Email{
+		mail.NewEmail(input.Receiver+" ", input.ReceiverEmail),
+	}
+	p.AddTos(tos...)
+
+	p.SetDynamicTemplateData("serverAddress", viper.GetString("url"))
+	p.SetDynamicTemplateData("device", input.Device)
+	p.SetDynamicTemplateData("ipAddress", input.IPAddress)
+	p.SetDynamicTemplateData("loginTime", input.LoginTime.Format("2006-01-02 15:04:05"))
+	m.AddPersonalizations(p)
+
+	err := e.send(m)
+	if err != nil {
+		l.Logger.Error("email.Session.NewDeviceLogin failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,59 @@
+package geocode
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/ic3network/mccs-alpha-api/global"
+	"github.com/ic3network/mccs-alpha-api/util/l"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Address is the part of an entity address used to resolve its coordinates.
+type Address struct {
+	PostalCode string
+	City       string
+	Country    string
+}
+
+// Geocoder resolves an address to latitude and longitude.
+type Geocoder interface {
+	Geocode(address *Address) (lat float64, lon float64, err error)
+}
+
+var ErrNotFound = errors.New("The address could not be located.")
+
+// Default is the geocoder configured with the geocode.driver setting.
+var Default Geocoder
+
+func init() {
+	global.Init()
+	Default = New()
+}
+
+// New returns the geocoder for the configured driver. Geocoding is disabled when no driver is set.
+func New() Geocoder {
+	switch viper.GetString("geocode.driver") {
+	case "postcode":
+		path := viper.GetString("geocode.postcode.file")
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(global.App.RootDir, path)
+		}
+		table, err := NewPostcodeTable(path)
+		if err != nil {
+			l.Logger.Error("geocode.New failed, geocoding is disabled", zap.Error(err))
+			return None{}
+		}
+		return table
+	default:
+		return None{}
+	}
+}
+
+// None never locates an address, entities can still set their coordinates manually.
+type None struct{}
+
+func (None) Geocode(address *Address) (float64, float64, error) {
+	return 0, 0, ErrNotFound
+}
@@ -0,0 +1,142 @@
+package geocode
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PostcodeTable is an offline geocoder backed by a GeoNames postal code dump
+// (https://download.geonames.org/export/zip/), so no address ever leaves the server.
+type PostcodeTable struct {
+	// country code + postcode -> coordinates
+	byCountry map[string][2]float64
+	// postcode -> coordinates, used when the country is unknown and the postcode is not ambiguous
+	byPostcode map[string][2]float64
+}
+
+// NewPostcodeTable loads the tab-separated GeoNames file: the country code, postal code and place name
+// are the first three columns and the latitude and longitude are the 10th and 11th columns.
+func NewPostcodeTable(path string) (*PostcodeTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t := &PostcodeTable{
+		byCountry:  map[string][2]float64{},
+		byPostcode: map[string][2]float64{},
+	}
+	ambiguous := map[string]bool{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cols := strings.Split(line, "\t")
+		if len(cols) < 11 {
+			continue
+		}
+		lat, err := strconv.ParseFloat(cols[9], 64)
+		if err != nil {
+			continue
+		}
+		lon, err := strconv.ParseFloat(cols[10], 64)
+		if err != nil {
+			continue
+		}
+
+		country := normalize(cols[0])
+		postcode := normalize(cols[1])
+		point := [2]float64{lat, lon}
+		if _, ok := t.byCountry[country+"|"+postcode]; !ok {
+			t.byCountry[country+"|"+postcode] = point
+		}
+		if existing, ok := t.byPostcode[postcode]; !ok {
+			t.byPostcode[postcode] = point
+		} else if existing != point {
+			ambiguous[postcode] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for postcode := range ambiguous {
+		delete(t.byPostcode, postcode)
+	}
+	return t, nil
+}
+
+// Geocode looks up the full postcode first, then the outward part of postcodes with a space (e.g. "SW1A 1AA").
+func (t *PostcodeTable) Geocode(address *Address) (float64, float64, error) {
+	if strings.TrimSpace(address.PostalCode) == "" {
+		return 0, 0, ErrNotFound
+	}
+	candidates := []string{normalize(address.PostalCode)}
+	if fields := strings.Fields(address.PostalCode); len(fields) > 1 {
+		candidates = append(candidates, normalize(fields[0]))
+	}
+
+	country := countryCode(address.Country)
+	for _, postcode := range candidates {
+		if country != "" {
+			if point, ok := t.byCountry[country+"|"+postcode]; ok {
+				return point[0], point[1], nil
+			}
+			continue
+		}
+		if point, ok := t.byPostcode[postcode]; ok {
+			return point[0], point[1], nil
+		}
+	}
+	return 0, 0, ErrNotFound
+}
+
+func normalize(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+}
+
+// countryCode accepts ISO codes and the names of common countries since the entity country is free text.
+func countryCode(country string) string {
+	c := normalize(country)
+	if len(c) == 2 {
+		return c
+	}
+	return countryNames[c]
+}
+
+var countryNames = map[string]string{
+	"AUSTRALIA":             "AU",
+	"AUSTRIA":               "AT",
+	"BELGIUM":               "BE",
+	"BRAZIL":                "BR",
+	"CANADA":                "CA",
+	"DENMARK":               "DK",
+	"FINLAND":               "FI",
+	"FRANCE":                "FR",
+	"GERMANY":               "DE",
+	"INDIA":                 "IN",
+	"IRELAND":               "IE",
+	"ITALY":                 "IT",
+	"JAPAN":                 "JP",
+	"MEXICO":                "MX",
+	"NETHERLANDS":           "NL",
+	"NEWZEALAND":            "NZ",
+	"NORWAY":                "NO",
+	"POLAND":                "PL",
+	"PORTUGAL":              "PT",
+	"SOUTHAFRICA":           "ZA",
+	"SPAIN":                 "ES",
+	"SWEDEN":                "SE",
+	"SWITZERLAND":           "CH",
+	"UK":                    "GB",
+	"UNITEDKINGDOM":         "GB",
+	"GREATBRITAIN":          "GB",
+	"USA":                   "US",
+	"UNITEDSTATES":          "US",
+	"UNITEDSTATESOFAMERICA": "US",
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores the files in a directory on the local disk.
+type Local struct {
+	dir string
+	url string
+}
+
+func NewLocal(dir string, url string) *Local {
+	return &Local{
+		dir: dir,
+		url: strings.TrimSuffix(url, "/"),
+	}
+}
+
+func (s *Local) Put(key string, data []byte) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	err := os.MkdirAll(s.dir, 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, key), data, 0644)
+}
+
+func (s *Local) Get(key string) ([]byte, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("File not found.")
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// Delete ignores files which are already gone.
+func (s *Local) Delete(key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	err := os.Remove(filepath.Join(s.dir, key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Local) URL(key string) string {
+	return s.url + "/" + key
+}
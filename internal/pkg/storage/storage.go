@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/ic3network/mccs-alpha-api/global"
+	"github.com/spf13/viper"
+)
+
+// Storage saves uploaded files under a flat key and exposes them through a public URL.
+type Storage interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+	URL(key string) string
+}
+
+// Files are served to anyone through their URL.
+var Files Storage
+
+// Private files are never served directly, the API hands them out after checking access.
+var Private Storage
+
+func init() {
+	global.Init()
+	Files = New(viper.GetString("storage.local.dir"), viper.GetString("storage.url"))
+	Private = New(viper.GetString("storage.local.private_dir"), "")
+}
+
+// New returns the storage for the configured driver, only the local disk is supported for now.
+func New(location string, url string) Storage {
+	switch viper.GetString("storage.driver") {
+	default:
+		if !filepath.IsAbs(location) {
+			location = filepath.Join(global.App.RootDir, location)
+		}
+		return NewLocal(location, url)
+	}
+}
+
+var ErrInvalidKey = errors.New("Invalid file key.")
+
+// ValidKey prevents keys from escaping the storage location.
+func ValidKey(key string) bool {
+	return key != "" && key == filepath.Base(key) && !strings.HasPrefix(key, ".")
+}
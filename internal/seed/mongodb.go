@@ -3,6 +3,7 @@ package seed
 import (
 	"context"
 
+	"github.com/ic3network/mccs-alpha-api/global/constant"
 	"github.com/ic3network/mccs-alpha-api/internal/app/repository/mongo"
 	"github.com/ic3network/mccs-alpha-api/internal/app/types"
 	"github.com/ic3network/mccs-alpha-api/util/bcrypt"
@@ -32,7 +33,10 @@ func (_ *mongoDB) CreateUser(user types.User) (primitive.ObjectID, error) {
 
 func (_ *mongoDB) AssociateUserWithEntity(userID, entityID primitive.ObjectID) error {
 	_, err := mongo.DB().Collection("entities").UpdateOne(context.Background(), bson.M{"_id": entityID}, bson.M{
-		"$addToSet": bson.M{"users": userID},
+		"$addToSet": bson.M{
+			"users":   userID,
+			"members": &types.EntityMember{UserID: userID, Role: constant.EntityRole.Owner},
+		},
 	})
 	return err
 }
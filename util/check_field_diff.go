@@ -97,7 +97,7 @@ func handlePtr(field string, origin interface{}, update interface{}) string {
 			return handleBool(field, *boolPtr, *updateBoolPtr)
 		}
 	}
-	return ""
+	return handleSlice(field, origin, update)
 }
 
 func handleSlice(field string, origin interface{}, update interface{}) string {
@@ -33,3 +33,22 @@ func IsTradingAccepted(status string) bool {
 	}
 	return false
 }
+
+// IsValidTransition checks if the entity can move from one status to the other.
+func IsValidTransition(from string, to string) bool {
+	for _, s := range constant.EntityStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRejectedStatus checks if the entity status is a rejection.
+func IsRejectedStatus(status string) bool {
+	if status == constant.Entity.Rejected ||
+		status == constant.Trading.Rejected {
+		return true
+	}
+	return false
+}
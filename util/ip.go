@@ -3,6 +3,9 @@ package util
 import (
 	"net"
 	"net/http"
+	"strings"
+
+	"github.com/spf13/viper"
 )
 
 const (
@@ -27,3 +30,70 @@ func IPAddress(r *http.Request) string {
 
 	return remoteAddr
 }
+
+// ClientIP returns the address of the client which cannot be spoofed through the request headers.
+// The forwarding headers are only followed while the hop they come from is one of `trusted_proxies`.
+func ClientIP(r *http.Request) string {
+	remoteAddr, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteAddr = r.RemoteAddr
+	}
+	trusted := trustedProxies()
+	if !isTrustedProxy(remoteAddr, trusted) {
+		return remoteAddr
+	}
+
+	// Each proxy appends the address it received the request from, so the client is
+	// the right-most address not added by one of the trusted proxies.
+	hops := strings.Split(r.Header.Get(XForwardedFor), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !isTrustedProxy(hop, trusted) {
+			return hop
+		}
+		remoteAddr = hop
+	}
+	if ip := strings.TrimSpace(r.Header.Get(XRealIP)); ip != "" && r.Header.Get(XForwardedFor) == "" {
+		return ip
+	}
+	return remoteAddr
+}
+
+func trustedProxies() []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, proxy := range viper.GetStringSlice("trusted_proxies") {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				continue
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+func isTrustedProxy(address string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
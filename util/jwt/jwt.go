@@ -2,46 +2,278 @@ package jwt
 
 import (
 	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
-	"log"
+	"fmt"
+	"math/big"
 	"os"
+	"sync"
 	"time"
 
 	jwtlib "github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
 )
 
+// key is a RSA key pair of the keyset, keys without a private key are only used to verify tokens.
+type key struct {
+	id         string
+	signKey    *rsa.PrivateKey
+	verifyKey  *rsa.PublicKey
+	activeFrom time.Time
+	expiresAt  time.Time
+}
+
+func (k *key) expired(now time.Time) bool {
+	return !k.expiresAt.IsZero() && now.After(k.expiresAt)
+}
+
+// keyConfig is an entry of `jwt.keys`.
+type keyConfig struct {
+	ID         string `mapstructure:"kid"`
+	PrivateKey string `mapstructure:"private_key"`
+	PublicKey  string `mapstructure:"public_key"`
+	// ActiveFrom is when the key starts signing tokens, RFC 3339.
+	ActiveFrom string `mapstructure:"active_from"`
+	// ExpiresAt is when the tokens signed with the key stop being accepted, RFC 3339.
+	ExpiresAt string `mapstructure:"expires_at"`
+}
+
 // JWTManager manages JWT operations.
 type JWTManager struct {
-	signKey   *rsa.PrivateKey
-	verifyKey *rsa.PublicKey
+	mu      sync.RWMutex
+	keys    []*key
+	signKey *key
 }
 
-// NewJWTManager initializes and returns a new JWTManager instance.
+var (
+	manager  = &JWTManager{}
+	initOnce = new(sync.Once)
+	initErr  error
+)
+
+// Init parses the keys from the configuration, it is called once at startup.
+func Init() error {
+	initOnce.Do(func() {
+		_, initErr = manager.Rotate()
+	})
+	return initErr
+}
+
+// NewJWTManager returns the JWTManager holding the keys parsed at startup.
 func NewJWTManager() *JWTManager {
-	privateKeyPEM := getEnvOrFallback("jwt.private_key", "JWT_PRIVATE_KEY")
-	publicKeyPEM := getEnvOrFallback("jwt.public_key", "JWT_PUBLIC_KEY")
+	// The error has already been reported when Init was called at startup.
+	Init()
+	return manager
+}
 
-	signKey, err := jwtlib.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+// Rotate reloads the keys from the configuration and activates the most recent signing key
+// whose activation time has passed. The current keys are kept when the configuration is invalid.
+func (jm *JWTManager) Rotate() (string, error) {
+	keys, err := loadKeys()
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	verifyKey, err := jwtlib.ParseRSAPublicKeyFromPEM([]byte(publicKeyPEM))
+	now := time.Now()
+	var signKey *key
+	for _, k := range keys {
+		if k.signKey == nil || k.activeFrom.After(now) || k.expired(now) {
+			continue
+		}
+		if signKey == nil || k.activeFrom.After(signKey.activeFrom) {
+			signKey = k
+		}
+	}
+	if signKey == nil {
+		return "", errors.New("jwt: no active signing key")
+	}
+
+	jm.mu.Lock()
+	jm.keys = keys
+	jm.signKey = signKey
+	jm.mu.Unlock()
+
+	return signKey.id, nil
+}
+
+// SigningKeyID returns the ID of the key the new tokens are signed with.
+func (jm *JWTManager) SigningKeyID() string {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	if jm.signKey == nil {
+		return ""
+	}
+	return jm.signKey.id
+}
+
+// loadKeys parses `jwt.keys` together with the single key pair of `jwt.private_key` and `jwt.public_key`.
+func loadKeys() ([]*key, error) {
+	configs := []keyConfig{}
+	err := viper.UnmarshalKey("jwt.keys", &configs)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	privateKeyPEM := getEnvOrFallback("jwt.private_key", "JWT_PRIVATE_KEY")
+	publicKeyPEM := getEnvOrFallback("jwt.public_key", "JWT_PUBLIC_KEY")
+	if privateKeyPEM != "" || publicKeyPEM != "" {
+		configs = append(configs, keyConfig{PrivateKey: privateKeyPEM, PublicKey: publicKeyPEM})
+	}
+
+	keys := []*key{}
+	ids := map[string]bool{}
+	for i, c := range configs {
+		k, err := c.parse()
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %d: %w", i, err)
+		}
+		if ids[k.id] {
+			return nil, fmt.Errorf("jwt: duplicate key ID %q", k.id)
+		}
+		ids[k.id] = true
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: no keys configured")
+	}
+	return keys, nil
+}
+
+func (c keyConfig) parse() (*key, error) {
+	k := &key{id: c.ID}
+	var err error
+
+	if c.PrivateKey != "" {
+		k.signKey, err = jwtlib.ParseRSAPrivateKeyFromPEM([]byte(c.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		k.verifyKey = &k.signKey.PublicKey
+	}
+	if c.PublicKey != "" {
+		k.verifyKey, err = jwtlib.ParseRSAPublicKeyFromPEM([]byte(c.PublicKey))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if k.verifyKey == nil {
+		return nil, errors.New("public key is missing")
+	}
+	if k.signKey != nil && k.signKey.PublicKey.N.Cmp(k.verifyKey.N) != 0 {
+		return nil, errors.New("private and public keys do not match")
+	}
+
+	if c.ActiveFrom != "" {
+		k.activeFrom, err = time.Parse(time.RFC3339, c.ActiveFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.ExpiresAt != "" {
+		k.expiresAt, err = time.Parse(time.RFC3339, c.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if k.id == "" {
+		k.id = thumbprint(k.verifyKey)
+	}
+	return k, nil
+}
+
+// thumbprint is the RFC 7638 thumbprint of the public key, it gives every key a stable ID.
+func thumbprint(pub *rsa.PublicKey) string {
+	jwk := `{"e":"` + encodeExponent(pub.E) + `","kty":"RSA","n":"` + base64.RawURLEncoding.EncodeToString(pub.N.Bytes()) + `"}`
+	sum := sha256.Sum256([]byte(jwk))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func encodeExponent(e int) string {
+	return base64.RawURLEncoding.EncodeToString(big.NewInt(int64(e)).Bytes())
+}
+
+// sign signs the claims with the active signing key and sets its ID in the header.
+func (jm *JWTManager) sign(claims jwtlib.Claims) (string, error) {
+	jm.mu.RLock()
+	signKey := jm.signKey
+	jm.mu.RUnlock()
+	if signKey == nil {
+		return "", errors.New("jwt: no active signing key")
+	}
+
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
+	token.Header["kid"] = signKey.id
+	return token.SignedString(signKey.signKey)
+}
+
+// verifyKey returns the key of the token's ID. Tokens issued before the keys had IDs are checked against every key.
+func (jm *JWTManager) verifyKey(token *jwtlib.Token) (interface{}, error) {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+
+	now := time.Now()
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		set := jwtlib.VerificationKeySet{}
+		for _, k := range jm.keys {
+			if !k.expired(now) {
+				set.Keys = append(set.Keys, k.verifyKey)
+			}
+		}
+		return set, nil
+	}
+	for _, k := range jm.keys {
+		if k.id == kid && !k.expired(now) {
+			return k.verifyKey, nil
+		}
 	}
+	return nil, errors.New("jwt: unknown key ID")
+}
+
+// JWK is the public part of a key as described by RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
 
-	return &JWTManager{
-		signKey:   signKey,
-		verifyKey: verifyKey,
+// JWKS returns the public keys the tokens are currently verified with.
+func (jm *JWTManager) JWKS() []JWK {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+
+	now := time.Now()
+	jwks := []JWK{}
+	for _, k := range jm.keys {
+		if k.expired(now) {
+			continue
+		}
+		jwks = append(jwks, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: jwtlib.SigningMethodRS256.Alg(),
+			Kid: k.id,
+			N:   base64.RawURLEncoding.EncodeToString(k.verifyKey.N.Bytes()),
+			E:   encodeExponent(k.verifyKey.E),
+		})
 	}
+	return jwks
 }
 
 type userClaims struct {
 	jwtlib.RegisteredClaims
 	UserID string `json:"userID"`
 	Admin  bool   `json:"admin"`
+	// SessionID identifies the refresh token family the token was issued for, it is used to revoke the token.
+	SessionID string `json:"sid,omitempty"`
+	// Permissions of an admin, resolved from the roles when the token is issued.
+	Permissions []string `json:"permissions,omitempty"`
+	// ImpersonatedBy is the admin acting as the user, the token is read-only unless ImpersonationWrite is set.
+	ImpersonatedBy     string `json:"impersonatedBy,omitempty"`
+	ImpersonationWrite bool   `json:"impersonationWrite,omitempty"`
 }
 
 // GenerateToken generates a JWT token for a user.
@@ -49,16 +281,50 @@ func (jm *JWTManager) Generate(
 	userID string,
 	isAdmin bool,
 ) (string, error) {
+	return jm.GenerateForSession(userID, isAdmin, "", nil)
+}
+
+// GenerateForSession generates a short-lived JWT token for a user bound to a session.
+func (jm *JWTManager) GenerateForSession(
+	userID string,
+	isAdmin bool,
+	sessionID string,
+	permissions []string,
+) (string, error) {
+	now := time.Now()
 	claims := userClaims{
-		UserID: userID,
-		Admin:  isAdmin,
+		UserID:      userID,
+		Admin:       isAdmin,
+		SessionID:   sessionID,
+		Permissions: permissions,
 		RegisteredClaims: jwtlib.RegisteredClaims{
-			ExpiresAt: jwtlib.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			IssuedAt:  jwtlib.NewNumericDate(now),
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(AccessTokenTimeout())),
 		},
 	}
 
-	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
-	return token.SignedString(jm.signKey)
+	return jm.sign(claims)
+}
+
+// GenerateImpersonation generates a JWT token which lets an admin act as the user until the session expires.
+func (jm *JWTManager) GenerateImpersonation(
+	userID string,
+	sessionID string,
+	adminID string,
+	write bool,
+	expiresAt time.Time,
+) (string, error) {
+	claims := userClaims{
+		UserID:             userID,
+		SessionID:          sessionID,
+		ImpersonatedBy:     adminID,
+		ImpersonationWrite: write,
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			IssuedAt:  jwtlib.NewNumericDate(time.Now()),
+			ExpiresAt: jwtlib.NewNumericDate(expiresAt),
+		},
+	}
+	return jm.sign(claims)
 }
 
 // Validate validates a JWT token and returns the associated claims.
@@ -67,9 +333,60 @@ func (jm *JWTManager) Validate(tokenString string) (*userClaims, error) {
 	token, err := jwtlib.ParseWithClaims(
 		tokenString,
 		claims,
-		func(token *jwtlib.Token) (interface{}, error) {
-			return jm.verifyKey, nil
+		jm.verifyKey,
+		jwtlib.WithValidMethods([]string{jwtlib.SigningMethodRS256.Alg()}),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+type paymentRequestClaims struct {
+	jwtlib.RegisteredClaims
+	AccountNumber string  `json:"accountNumber"`
+	Amount        float64 `json:"amount"`
+	Description   string  `json:"description,omitempty"`
+	SingleUse     bool    `json:"singleUse,omitempty"`
+}
+
+// GeneratePaymentRequest generates a signed payment request token.
+func (jm *JWTManager) GeneratePaymentRequest(
+	id string,
+	accountNumber string,
+	amount float64,
+	description string,
+	singleUse bool,
+	expiresAt time.Time,
+) (string, error) {
+	claims := paymentRequestClaims{
+		AccountNumber: accountNumber,
+		Amount:        amount,
+		Description:   description,
+		SingleUse:     singleUse,
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			ID:        id,
+			ExpiresAt: jwtlib.NewNumericDate(expiresAt),
 		},
+	}
+
+	return jm.sign(claims)
+}
+
+// ValidatePaymentRequest validates a payment request token and returns the associated claims.
+func (jm *JWTManager) ValidatePaymentRequest(tokenString string) (*paymentRequestClaims, error) {
+	claims := &paymentRequestClaims{}
+	token, err := jwtlib.ParseWithClaims(
+		tokenString,
+		claims,
+		jm.verifyKey,
+		jwtlib.WithValidMethods([]string{jwtlib.SigningMethodRS256.Alg()}),
 	)
 
 	if err != nil {
@@ -83,6 +400,15 @@ func (jm *JWTManager) Validate(tokenString string) (*userClaims, error) {
 	return claims, nil
 }
 
+// AccessTokenTimeout returns how long an access token stays valid, 15 minutes unless configured.
+func AccessTokenTimeout() time.Duration {
+	timeout := viper.GetDuration("jwt.access_token_timeout") * time.Second
+	if timeout <= 0 {
+		return 15 * time.Minute
+	}
+	return timeout
+}
+
 func getEnvOrFallback(viperKey, envKey string) string {
 	value := viper.GetString(viperKey)
 	if value == "" {
@@ -1,7 +1,11 @@
 package jwt_test
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ic3network/mccs-alpha-api/util/jwt"
 	"github.com/stretchr/testify/require"
@@ -82,3 +86,74 @@ func TestJWT(t *testing.T) {
 		})
 	}
 }
+
+func TestPaymentRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "Valid Payment Request",
+			expiresAt: time.Now().Add(time.Hour),
+			wantErr:   false,
+		},
+		{
+			name:      "Expired Payment Request",
+			expiresAt: time.Now().Add(-time.Hour),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("jwt.private_key", TEST_PRIVATE_KEY)
+			t.Setenv("jwt.public_key", TEST_PUBLIC_KEY)
+			j := jwt.NewJWTManager()
+
+			token, err := j.GeneratePaymentRequest("123", "4111111111111111", 12.5, "Coffee", true, tt.expiresAt)
+			require.NoError(t, err)
+
+			claims, err := j.ValidatePaymentRequest(token)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, "123", claims.ID)
+			require.Equal(t, "4111111111111111", claims.AccountNumber)
+			require.Equal(t, 12.5, claims.Amount)
+			require.Equal(t, "Coffee", claims.Description)
+			require.True(t, claims.SingleUse)
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	t.Setenv("jwt.private_key", TEST_PRIVATE_KEY)
+	t.Setenv("jwt.public_key", TEST_PUBLIC_KEY)
+	j := jwt.NewJWTManager()
+
+	token, err := j.GenerateForSession("123", false, "session", nil)
+	require.NoError(t, err)
+
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	require.NoError(t, err)
+	var h struct {
+		Kid string `json:"kid"`
+	}
+	require.NoError(t, json.Unmarshal(header, &h))
+	require.Equal(t, j.SigningKeyID(), h.Kid)
+
+	keys := j.JWKS()
+	require.Len(t, keys, 1)
+	require.Equal(t, h.Kid, keys[0].Kid)
+	require.Equal(t, "RSA", keys[0].Kty)
+	require.Equal(t, "RS256", keys[0].Alg)
+	require.Equal(t, "AQAB", keys[0].E)
+
+	claims, err := j.Validate(token)
+	require.NoError(t, err)
+	require.Equal(t, "session", claims.SessionID)
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var commonPasswords = func() map[string]struct{} {
+	m := make(map[string]struct{})
+	for _, p := range strings.Split(commonPasswordList, "\n") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			m[p] = struct{}{}
+		}
+	}
+	return m
+}()
+
+// IsCommon reports whether the password appears in the bundled list of
+// breached and commonly used passwords. The match is case-insensitive.
+func IsCommon(password string) bool {
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
+}
@@ -0,0 +1,38 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+
+	qrlib "github.com/skip2/go-qrcode"
+)
+
+// PNG encodes the content into a QR code PNG image of the given size in pixels.
+func PNG(content string, size int) ([]byte, error) {
+	return qrlib.Encode(content, qrlib.Medium, size)
+}
+
+// SVG encodes the content into a QR code SVG image of the given size in pixels.
+func SVG(content string, size int) ([]byte, error) {
+	q, err := qrlib.New(content, qrlib.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := q.Bitmap()
+	modules := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, modules, modules)
+	buf.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x, black := range row {
+			if black {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes(), nil
+}
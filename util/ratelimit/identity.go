@@ -0,0 +1,20 @@
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/ic3network/mccs-alpha-api/util"
+)
+
+// Identity counts the requests of a logged in user or an API key separately from their IP address.
+// The address is the one of the connecting peer unless it is a trusted proxy, so a client cannot
+// get a new identity by changing the forwarding headers.
+func Identity(r *http.Request) string {
+	if keyID := r.Header.Get("apiKeyID"); keyID != "" {
+		return "key:" + keyID
+	}
+	if userID := r.Header.Get("userID"); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + util.ClientIP(r)
+}
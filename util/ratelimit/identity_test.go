@@ -0,0 +1,47 @@
+package ratelimit_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ic3network/mccs-alpha-api/util/ratelimit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentitySpoofedHeaders(t *testing.T) {
+	viper.Set("trusted_proxies", []string{})
+	defer viper.Set("trusted_proxies", nil)
+
+	identities := map[string]bool{}
+	for _, spoofed := range []string{"", "1.1.1.1", "2.2.2.2, 3.3.3.3"} {
+		r := httptest.NewRequest("POST", "/api/v1/login", nil)
+		r.RemoteAddr = "203.0.113.7:51234"
+		if spoofed != "" {
+			r.Header.Set("X-Forwarded-For", spoofed)
+			r.Header.Set("X-Real-IP", spoofed)
+		}
+		identities[ratelimit.Identity(r)] = true
+	}
+	require.Equal(t, map[string]bool{"ip:203.0.113.7": true}, identities)
+}
+
+func TestIdentityTrustedProxy(t *testing.T) {
+	viper.Set("trusted_proxies", []string{"10.0.0.0/8"})
+	defer viper.Set("trusted_proxies", nil)
+
+	r := httptest.NewRequest("POST", "/api/v1/login", nil)
+	r.RemoteAddr = "10.0.0.2:443"
+	// The client prepended an address, the proxy appended the one it saw.
+	r.Header.Set("X-Forwarded-For", "1.1.1.1, 203.0.113.7")
+	require.Equal(t, "ip:203.0.113.7", ratelimit.Identity(r))
+}
+
+func TestIdentityLoggedIn(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/user", nil)
+	r.Header.Set("userID", "5f0000000000000000000001")
+	require.Equal(t, "user:5f0000000000000000000001", ratelimit.Identity(r))
+
+	r.Header.Set("apiKeyID", "5f0000000000000000000002")
+	require.Equal(t, "key:5f0000000000000000000002", ratelimit.Identity(r))
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// SlidingWindow counts the requests of each identity within a sliding window in memory.
+type SlidingWindow struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+type window struct {
+	requests []time.Time
+	length   time.Duration
+}
+
+func NewSlidingWindow() *SlidingWindow {
+	return &SlidingWindow{windows: map[string]*window{}}
+}
+
+// Allow records the request made at now when the identity is still below the limit.
+// It returns whether the request is allowed, the number of requests left and the time until the oldest one leaves the window.
+func (s *SlidingWindow) Allow(identity string, limit int, length time.Duration, now time.Time) (bool, int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	w, ok := s.windows[identity]
+	if !ok {
+		w = &window{}
+		s.windows[identity] = w
+	}
+	w.length = length
+
+	// Drops the requests which have left the window.
+	i := 0
+	for i < len(w.requests) && now.Sub(w.requests[i]) >= length {
+		i++
+	}
+	w.requests = w.requests[i:]
+
+	allowed := len(w.requests) < limit
+	if allowed {
+		w.requests = append(w.requests, now)
+	}
+
+	reset := length
+	if len(w.requests) > 0 {
+		reset = w.requests[0].Add(length).Sub(now)
+	}
+	return allowed, limit - len(w.requests), reset
+}
+
+// sweep forgets the identities without requests in their window once a minute so the map does not keep growing.
+func (s *SlidingWindow) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for identity, w := range s.windows {
+		if len(w.requests) == 0 || now.Sub(w.requests[len(w.requests)-1]) >= w.length {
+			delete(s.windows, identity)
+		}
+	}
+}
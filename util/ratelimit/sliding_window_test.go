@@ -0,0 +1,47 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/util/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlidingWindowAllow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := ratelimit.NewSlidingWindow()
+
+	// The steps run in order against the same limiter, 2 requests are allowed per minute.
+	tests := []struct {
+		name      string
+		at        time.Duration
+		identity  string
+		allowed   bool
+		remaining int
+		reset     time.Duration
+	}{
+		{"first request", 0, "a", true, 1, time.Minute},
+		{"reaches the limit", 10 * time.Second, "a", true, 0, 50 * time.Second},
+		{"over the limit", 20 * time.Second, "a", false, 0, 40 * time.Second},
+		{"other identity", 20 * time.Second, "b", true, 1, time.Minute},
+		{"oldest request left the window", time.Minute, "a", true, 0, 10 * time.Second},
+		{"over the limit again", 65 * time.Second, "a", false, 0, 5 * time.Second},
+		{"second request left the window", 70 * time.Second, "a", true, 0, 50 * time.Second},
+		{"whole window expired", 10 * time.Minute, "a", true, 1, time.Minute},
+	}
+	for _, test := range tests {
+		allowed, remaining, reset := limiter.Allow(test.identity, 2, time.Minute, start.Add(test.at))
+		require.Equal(t, test.allowed, allowed, test.name)
+		require.Equal(t, test.remaining, remaining, test.name)
+		require.Equal(t, test.reset, reset, test.name)
+	}
+}
+
+func TestSlidingWindowZeroLimit(t *testing.T) {
+	limiter := ratelimit.NewSlidingWindow()
+	allowed, remaining, reset := limiter.Allow("a", 0, time.Minute, time.Now())
+	require.False(t, allowed)
+	require.Equal(t, 0, remaining)
+	require.Equal(t, time.Minute, reset)
+}
@@ -23,3 +23,12 @@ func StringDiff(new, old []string) (added []string, removed []string) {
 	}
 	return added, removed
 }
+
+func ContainString(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/ic3network/mccs-alpha-api/util/totp"
+	"github.com/stretchr/testify/require"
+)
+
+// The SHA-1 seed of the RFC 6238 test vectors.
+var secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 Appendix B lists 8 digit codes, the last 6 digits are the 6 digit ones.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, test := range tests {
+		code, err := totp.Code(secret, totp.Counter(time.Unix(test.unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, test.code, code, "time %d", test.unix)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	_, err := totp.Code("not base32!", 1)
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totp.Counter(now)
+
+	tests := []struct {
+		name    string
+		counter int64
+		valid   bool
+	}{
+		{"current period", current, true},
+		{"previous period", current - totp.Skew, true},
+		{"next period", current + totp.Skew, true},
+		{"before the skew", current - totp.Skew - 1, false},
+		{"after the skew", current + totp.Skew + 1, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, err := totp.Code(secret, test.counter)
+			require.NoError(t, err)
+			counter, ok := totp.Validate(secret, code, now)
+			require.Equal(t, test.valid, ok)
+			if test.valid {
+				require.Equal(t, test.counter, counter)
+			}
+		})
+	}
+}
+
+func TestValidateWrongLength(t *testing.T) {
+	code, err := totp.Code(secret, totp.Counter(time.Unix(59, 0)))
+	require.NoError(t, err)
+	_, ok := totp.Validate(secret, code+"0", time.Unix(59, 0))
+	require.False(t, ok)
+	_, ok = totp.Validate(secret, "", time.Unix(59, 0))
+	require.False(t, ok)
+}